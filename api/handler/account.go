@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/meysamhadeli/problem-details"
+	"github.com/samber/do"
+)
+
+type accountHandler struct {
+	i              *do.Injector
+	accountService domain.AccountService
+}
+
+func NewAccountHandler(i *do.Injector) (domain.AccountHandler, error) {
+	accountService, err := do.Invoke[domain.AccountService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &accountHandler{
+		i:              i,
+		accountService: accountService,
+	}, nil
+}
+
+func (a *accountHandler) SendDeletionCode(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "account"),
+		slog.String("func", "SendDeletionCode"),
+	)
+
+	log.Info("Initializing send account deletion code process")
+
+	if err := a.accountService.SendDeletionCode(ctx.Request().Context()); err != nil {
+		var attemptBlockedError *domain.AttemptBlockedError
+		if errors.As(err, &attemptBlockedError) {
+			return attemptBlockedResponse(ctx, log, attemptBlockedError)
+		}
+
+		switch {
+		case errors.Is(err, domain.ErrUserNotFoundInContext):
+			log.Warn("User not found in context", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Unauthorized",
+				Detail: "You need to be logged in to access this resource.",
+			})
+		default:
+			log.Error("Failed to send account deletion code", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("Account deletion code sent successfully")
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (a *accountHandler) Delete(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "account"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing account delete process")
+
+	var deleteAccountPayload domain.DeleteAccountPayload
+	if err := ctx.Bind(&deleteAccountPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := deleteAccountPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := a.accountService.Delete(ctx.Request().Context(), deleteAccountPayload); err != nil {
+		var attemptBlockedError *domain.AttemptBlockedError
+		if errors.As(err, &attemptBlockedError) {
+			return attemptBlockedResponse(ctx, log, attemptBlockedError)
+		}
+
+		switch {
+		case errors.Is(err, domain.ErrUserNotFoundInContext):
+			log.Warn("User not found in context", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Unauthorized",
+				Detail: "You need to be logged in to access this resource.",
+			})
+		case errors.Is(err, domain.ErrInvalidPassword):
+			log.Warn("Invalid password")
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "Unauthorized",
+				Detail: "Invalid password. Please verify your password and try again.",
+			})
+		case errors.Is(err, domain.ErrOTPExpires):
+			log.Warn("OTP has expired", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "OTP Expired",
+				Detail: "The OTP code has expired. Please request a new code.",
+			})
+		case errors.Is(err, domain.ErrOTPInvalid):
+			log.Warn("Invalid OTP", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "Invalid OTP",
+				Detail: "The OTP code provided is invalid. Please check the code and try again.",
+			})
+		case errors.Is(err, domain.ErrReauthenticationRequired):
+			log.Warn("Reauthentication required")
+			return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+				Status: http.StatusBadRequest,
+				Title:  "Invalid Request",
+				Detail: "Please confirm the deletion with your password or with the code sent to your email.",
+			})
+		default:
+			log.Error("Failed to delete account", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("Account deleted successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (a *accountHandler) Export(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "account"),
+		slog.String("func", "Export"),
+	)
+
+	log.Info("Initializing account export process")
+
+	format := ctx.QueryParam("format")
+	if format != "" && format != "json" && format != "zip" {
+		log.Warn("Invalid export format", slog.String("format", format))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The export format must be 'json' or 'zip'.",
+		})
+	}
+
+	accountExport, err := a.accountService.Export(ctx.Request().Context())
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserNotFoundInContext), errors.Is(err, domain.ErrUserNotFound):
+			log.Warn("User not found", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Unauthorized",
+				Detail: "You need to be logged in to access this resource.",
+			})
+		default:
+			log.Error("Failed to export account", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	fileName := fmt.Sprintf("account-%s-%s", accountExport.Profile.ID, accountExport.ExportedAt.Format("20060102150405"))
+
+	if format == "zip" {
+		archive, err := a.createArchive(accountExport)
+		if err != nil {
+			log.Error("Failed to create export archive", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+
+		log.Info("Account export executed successfully")
+		ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName+".zip"))
+		return ctx.Blob(http.StatusOK, "application/zip", archive)
+	}
+
+	log.Info("Account export executed successfully")
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName+".json"))
+	return ctx.JSON(http.StatusOK, accountExport)
+}
+
+func (a *accountHandler) createArchive(accountExport *domain.AccountExport) ([]byte, error) {
+	files := []struct {
+		name string
+		data any
+	}{
+		{name: "profile.json", data: accountExport.Profile},
+		{name: "identities.json", data: accountExport.Identities},
+		{name: "stores.json", data: accountExport.Stores},
+		{name: "api_keys.json", data: accountExport.APIKeys},
+		{name: "sessions.json", data: accountExport.Sessions},
+	}
+
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+
+	for _, file := range files {
+		content, err := jsoniter.MarshalIndent(file.data, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		fileWriter, err := writer.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := fileWriter.Write(content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,313 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/meysamhadeli/problem-details"
+	"github.com/samber/do"
+)
+
+type adminHandler struct {
+	i            *do.Injector
+	adminService domain.AdminService
+}
+
+func NewAdminHandler(i *do.Injector) (domain.AdminHandler, error) {
+	adminService, err := do.Invoke[domain.AdminService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &adminHandler{
+		i:            i,
+		adminService: adminService,
+	}, nil
+}
+
+func (a *adminHandler) GetUsers(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "admin"),
+		slog.String("func", "GetUsers"),
+	)
+
+	log.Info("Initializing get users process")
+
+	var adminSearchPayload domain.AdminSearchPayload
+	if err := ctx.Bind(&adminSearchPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := adminSearchPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	adminUsersResponse, err := a.adminService.GetUsers(ctx.Request().Context(), adminSearchPayload)
+	if err != nil {
+		return a.handleError(ctx, log, err)
+	}
+
+	log.Info("Get users executed successfully")
+	return ctx.JSON(http.StatusOK, adminUsersResponse)
+}
+
+func (a *adminHandler) GetStores(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "admin"),
+		slog.String("func", "GetStores"),
+	)
+
+	log.Info("Initializing get stores process")
+
+	var adminStoreSearchPayload domain.AdminStoreSearchPayload
+	if err := ctx.Bind(&adminStoreSearchPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := adminStoreSearchPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	adminStoresResponse, err := a.adminService.GetStores(ctx.Request().Context(), adminStoreSearchPayload)
+	if err != nil {
+		return a.handleError(ctx, log, err)
+	}
+
+	log.Info("Get stores executed successfully")
+	return ctx.JSON(http.StatusOK, adminStoresResponse)
+}
+
+func (a *adminHandler) SuspendUser(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "admin"),
+		slog.String("func", "SuspendUser"),
+	)
+
+	log.Info("Initializing suspend user process")
+
+	userID, err := uuid.Parse(ctx.Param("userId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := a.adminService.SuspendUser(ctx.Request().Context(), userID); err != nil {
+		return a.handleError(ctx, log, err)
+	}
+
+	log.Info("User suspended successfully")
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (a *adminHandler) UnsuspendUser(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "admin"),
+		slog.String("func", "UnsuspendUser"),
+	)
+
+	log.Info("Initializing unsuspend user process")
+
+	userID, err := uuid.Parse(ctx.Param("userId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := a.adminService.UnsuspendUser(ctx.Request().Context(), userID); err != nil {
+		return a.handleError(ctx, log, err)
+	}
+
+	log.Info("User unsuspended successfully")
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (a *adminHandler) ConfirmUserEmail(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "admin"),
+		slog.String("func", "ConfirmUserEmail"),
+	)
+
+	log.Info("Initializing force email confirmation process")
+
+	userID, err := uuid.Parse(ctx.Param("userId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := a.adminService.ConfirmUserEmail(ctx.Request().Context(), userID); err != nil {
+		return a.handleError(ctx, log, err)
+	}
+
+	log.Info("User email confirmed successfully")
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (a *adminHandler) UpdateUserRole(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "admin"),
+		slog.String("func", "UpdateUserRole"),
+	)
+
+	log.Info("Initializing user role update process")
+
+	userID, err := uuid.Parse(ctx.Param("userId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	var adminUserRolePayload domain.AdminUserRolePayload
+	if err := ctx.Bind(&adminUserRolePayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := adminUserRolePayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := a.adminService.UpdateUserRole(ctx.Request().Context(), userID, adminUserRolePayload); err != nil {
+		return a.handleError(ctx, log, err)
+	}
+
+	log.Info("User role updated successfully")
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (a *adminHandler) RestoreStore(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "admin"),
+		slog.String("func", "RestoreStore"),
+	)
+
+	log.Info("Initializing store restore process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	adminStoreResponse, err := a.adminService.RestoreStore(ctx.Request().Context(), storeID)
+	if err != nil {
+		return a.handleError(ctx, log, err)
+	}
+
+	log.Info("Store restored successfully")
+	return ctx.JSON(http.StatusOK, adminStoreResponse)
+}
+
+func (a *adminHandler) handleError(ctx echo.Context, log *slog.Logger, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		log.Warn("User not found", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+			Status: http.StatusNotFound,
+			Title:  "User Not Found",
+			Detail: "The specified user was not found.",
+		})
+	case errors.Is(err, domain.ErrStoreNotFound):
+		log.Warn("Store not found", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+			Status: http.StatusNotFound,
+			Title:  "Store Not Found",
+			Detail: "The specified store was not found.",
+		})
+	case errors.Is(err, domain.ErrCannotModerateSelf):
+		log.Warn("Cannot moderate own account", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+			Status: http.StatusForbidden,
+			Title:  "Forbidden",
+			Detail: "You cannot perform this action on your own account.",
+		})
+	case errors.Is(err, domain.ErrUserAlreadySuspended):
+		log.Warn("User already suspended", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+			Status: http.StatusConflict,
+			Title:  "Conflict",
+			Detail: "The specified user is already suspended.",
+		})
+	case errors.Is(err, domain.ErrUserNotSuspended):
+		log.Warn("User not suspended", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+			Status: http.StatusConflict,
+			Title:  "Conflict",
+			Detail: "The specified user is not suspended.",
+		})
+	case errors.Is(err, domain.ErrEmailAlreadyConfirmed):
+		log.Warn("Email already confirmed", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+			Status: http.StatusConflict,
+			Title:  "Conflict",
+			Detail: "The email of the specified user is already confirmed.",
+		})
+	case errors.Is(err, domain.ErrStoreNotDeleted):
+		log.Warn("Store not deleted", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+			Status: http.StatusConflict,
+			Title:  "Conflict",
+			Detail: "The specified store is not deleted.",
+		})
+	default:
+		log.Error("Failed to process admin request", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/meysamhadeli/problem-details"
+	"github.com/samber/do"
+)
+
+type apiKeyHandler struct {
+	i             *do.Injector
+	apiKeyService domain.APIKeyService
+}
+
+func NewAPIKeyHandler(i *do.Injector) (domain.APIKeyHandler, error) {
+	apiKeyService, err := do.Invoke[domain.APIKeyService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiKeyHandler{
+		i:             i,
+		apiKeyService: apiKeyService,
+	}, nil
+}
+
+func (a *apiKeyHandler) Create(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "apiKey"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing api key creation process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	var apiKeyPayload domain.APIKeyPayload
+	if err := ctx.Bind(&apiKeyPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := apiKeyPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	apiKeyResponse, err := a.apiKeyService.Create(ctx.Request().Context(), storeID, apiKeyPayload)
+	if err != nil {
+		return a.handleError(ctx, log, err)
+	}
+
+	log.Info("API key created successfully")
+	return ctx.JSON(http.StatusCreated, apiKeyResponse)
+}
+
+func (a *apiKeyHandler) GetAll(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "apiKey"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all api keys process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	apiKeysResponse, err := a.apiKeyService.GetAll(ctx.Request().Context(), storeID)
+	if err != nil {
+		return a.handleError(ctx, log, err)
+	}
+
+	log.Info("Successfully retrieved all api keys")
+	return ctx.JSON(http.StatusOK, apiKeysResponse)
+}
+
+func (a *apiKeyHandler) Revoke(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "apiKey"),
+		slog.String("func", "Revoke"),
+	)
+
+	log.Info("Initializing api key revoke process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	apiKeyID, err := uuid.Parse(ctx.Param("apiKeyId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := a.apiKeyService.Revoke(ctx.Request().Context(), storeID, apiKeyID); err != nil {
+		return a.handleError(ctx, log, err)
+	}
+
+	log.Info("API key revoked successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (a *apiKeyHandler) handleError(ctx echo.Context, log *slog.Logger, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFoundInContext):
+		log.Warn("User not found in context", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+			Status: http.StatusForbidden,
+			Title:  "Forbidden",
+			Detail: "User not found in context. Please log in again.",
+		})
+	case errors.Is(err, domain.ErrAPIKeyNotAllowed):
+		log.Warn("API key used to manage api keys", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+			Status: http.StatusForbidden,
+			Title:  "Forbidden",
+			Detail: "API keys can only be managed by a logged in user.",
+		})
+	case errors.Is(err, domain.ErrStoreNotFound):
+		log.Warn("Store not found", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+			Status: http.StatusNotFound,
+			Title:  "Store Not Found",
+			Detail: "The specified store was not found.",
+		})
+	case errors.Is(err, domain.ErrUnauthorizedAction):
+		log.Warn("Unauthorized action", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+			Status: http.StatusForbidden,
+			Title:  "Forbidden",
+			Detail: "You are not allowed to manage the api keys of this store.",
+		})
+	case errors.Is(err, domain.ErrAPIKeyNotFound):
+		log.Warn("API key not found", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+			Status: http.StatusNotFound,
+			Title:  "API Key Not Found",
+			Detail: "The specified api key was not found.",
+		})
+	default:
+		log.Error("Failed to process api key request", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/meysamhadeli/problem-details"
+	"github.com/samber/do"
+)
+
+type attemptHandler struct {
+	i              *do.Injector
+	attemptService domain.AttemptService
+}
+
+func NewAttemptHandler(i *do.Injector) (domain.AttemptHandler, error) {
+	attemptService, err := do.Invoke[domain.AttemptService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &attemptHandler{
+		i:              i,
+		attemptService: attemptService,
+	}, nil
+}
+
+func (a *attemptHandler) Unlock(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "attempt"),
+		slog.String("func", "Unlock"),
+	)
+
+	log.Info("Initializing account unlock process")
+
+	var unlockAccountPayload domain.UnlockAccountPayload
+	if err := ctx.Bind(&unlockAccountPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := unlockAccountPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := a.attemptService.Unlock(ctx.Request().Context(), unlockAccountPayload); err != nil {
+		if errors.Is(err, domain.ErrUnlockTokenInvalid) {
+			log.Warn("Invalid unlock token", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+				Status: http.StatusBadRequest,
+				Title:  "Invalid Token",
+				Detail: "The unlock link is invalid or has expired.",
+			})
+		}
+
+		log.Error("Failed to unlock account", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	log.Info("Account unlocked successfully")
+	return ctx.NoContent(http.StatusOK)
+}
+
+func attemptBlockedResponse(ctx echo.Context, log *slog.Logger, attemptBlockedError *domain.AttemptBlockedError) error {
+	retryAfter := int(math.Ceil(attemptBlockedError.RetryAfter.Seconds()))
+	ctx.Response().Header().Set("Retry-After", strconv.Itoa(retryAfter))
+
+	switch {
+	case errors.Is(attemptBlockedError, domain.ErrAccountLocked):
+		log.Warn("Account locked", slog.Int("retryAfter", retryAfter))
+		return ctx.JSON(http.StatusLocked, &problem.ProblemDetail{
+			Status: http.StatusLocked,
+			Title:  "Account Locked",
+			Detail: "Too many failed attempts. Your account has been temporarily locked. Check your email for an unlock link or try again later.",
+		})
+	case errors.Is(attemptBlockedError, domain.ErrIPLocked):
+		log.Warn("IP locked", slog.Int("retryAfter", retryAfter))
+		return ctx.JSON(http.StatusTooManyRequests, &problem.ProblemDetail{
+			Status: http.StatusTooManyRequests,
+			Title:  "Too Many Attempts",
+			Detail: "Too many failed attempts from your network. Please try again later.",
+		})
+	case errors.Is(attemptBlockedError, domain.ErrRequestRateLimited):
+		log.Warn("Request rate limited", slog.Int("retryAfter", retryAfter))
+		return ctx.JSON(http.StatusTooManyRequests, &problem.ProblemDetail{
+			Status: http.StatusTooManyRequests,
+			Title:  "Too Many Requests",
+			Detail: "Too many requests. Please try again later.",
+		})
+	default:
+		log.Warn("Attempt throttled", slog.Int("retryAfter", retryAfter))
+		return ctx.JSON(http.StatusTooManyRequests, &problem.ProblemDetail{
+			Status: http.StatusTooManyRequests,
+			Title:  "Slow Down",
+			Detail: "Please wait a few seconds before trying again.",
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/meysamhadeli/problem-details"
+	"github.com/samber/do"
+)
+
+type auditHandler struct {
+	i            *do.Injector
+	auditService domain.AuditService
+}
+
+func NewAuditHandler(i *do.Injector) (domain.AuditHandler, error) {
+	auditService, err := do.Invoke[domain.AuditService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auditHandler{
+		i:            i,
+		auditService: auditService,
+	}, nil
+}
+
+func (a *auditHandler) GetAll(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "audit"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all audit events process")
+
+	storeID, auditQueryPayload, problemDetail := a.bindQuery(ctx, log)
+	if problemDetail != nil {
+		return ctx.JSON(problemDetail.Status, problemDetail)
+	}
+
+	auditEventsResponse, err := a.auditService.GetAll(ctx.Request().Context(), storeID, *auditQueryPayload)
+	if err != nil {
+		return a.handleError(ctx, log, err)
+	}
+
+	log.Info("Get all audit events executed successfully")
+	return ctx.JSON(http.StatusOK, auditEventsResponse)
+}
+
+func (a *auditHandler) Export(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "audit"),
+		slog.String("func", "Export"),
+	)
+
+	log.Info("Initializing audit export process")
+
+	storeID, auditQueryPayload, problemDetail := a.bindQuery(ctx, log)
+	if problemDetail != nil {
+		return ctx.JSON(problemDetail.Status, problemDetail)
+	}
+
+	auditEventsResponse, err := a.auditService.Export(ctx.Request().Context(), storeID, *auditQueryPayload)
+	if err != nil {
+		return a.handleError(ctx, log, err)
+	}
+
+	content, err := a.createCSV(auditEventsResponse)
+	if err != nil {
+		log.Error("Failed to create audit csv", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	fileName := fmt.Sprintf("audit-%s-%s.csv", storeID.String(), time.Now().UTC().Format("20060102150405"))
+
+	log.Info("Audit export executed successfully")
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName))
+	return ctx.Blob(http.StatusOK, "text/csv; charset=utf-8", content)
+}
+
+func (a *auditHandler) bindQuery(ctx echo.Context, log *slog.Logger) (uuid.UUID, *domain.AuditQueryPayload, *problem.ProblemDetail) {
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return uuid.Nil, nil, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		}
+	}
+
+	var auditQueryPayload domain.AuditQueryPayload
+	if err := ctx.Bind(&auditQueryPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return uuid.Nil, nil, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		}
+	}
+
+	if err := auditQueryPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return uuid.Nil, nil, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		}
+	}
+
+	return storeID, &auditQueryPayload, nil
+}
+
+func (a *auditHandler) createCSV(auditEventsResponse []*domain.AuditEventResponse) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+
+	header := []string{"id", "createdAt", "actorType", "actorId", "action", "targetType", "targetId", "before", "after", "ip", "requestId"}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, auditEvent := range auditEventsResponse {
+		record := []string{
+			auditEvent.ID,
+			auditEvent.CreatedAt.Format(time.RFC3339Nano),
+			string(auditEvent.ActorType),
+			auditEvent.ActorID,
+			string(auditEvent.Action),
+			auditEvent.TargetType,
+			auditEvent.TargetID,
+			string(auditEvent.Before),
+			string(auditEvent.After),
+			auditEvent.IP,
+			auditEvent.RequestID,
+		}
+
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (a *auditHandler) handleError(ctx echo.Context, log *slog.Logger, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFoundInContext):
+		log.Warn("User not found in context", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+			Status: http.StatusForbidden,
+			Title:  "Forbidden",
+			Detail: "User not found in context. Please log in again.",
+		})
+	case errors.Is(err, domain.ErrStoreNotFound):
+		log.Warn("Store not found", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+			Status: http.StatusNotFound,
+			Title:  "Store Not Found",
+			Detail: "The specified store was not found.",
+		})
+	case errors.Is(err, domain.ErrAuditCursorInvalid):
+		log.Warn("Invalid audit cursor", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The cursor provided is invalid.",
+		})
+	case errors.Is(err, domain.ErrUnauthorizedAction), errors.Is(err, domain.ErrAPIKeyNotAllowed):
+		log.Warn("Unauthorized action", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+			Status: http.StatusForbidden,
+			Title:  "Forbidden",
+			Detail: "You are not allowed to perform this action.",
+		})
+	default:
+		log.Error("Failed to process audit request", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+}
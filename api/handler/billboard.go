@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 
@@ -81,22 +82,22 @@ func (b *billboardHandler) Create(ctx echo.Context) error {
 
 	billboardResponse, err := b.billboardService.Create(ctx.Request().Context(), storeID, *billboardPayload)
 	if err != nil {
-		switch err {
-		case domain.ErrUserNotFoundInContext:
+		switch {
+		case errors.Is(err, domain.ErrUserNotFoundInContext):
 			log.Warn("User not found in context", slog.String("error", err.Error()))
 			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
 				Status: http.StatusUnauthorized,
 				Title:  "Unauthorized",
 				Detail: "User not authorized to perform this action.",
 			})
-		case domain.ErrStoreNotFound:
+		case errors.Is(err, domain.ErrStoreNotFound):
 			log.Warn("Store not found", slog.String("error", err.Error()))
 			return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
 				Status: http.StatusNotFound,
 				Title:  "Store Not Found",
 				Detail: "The specified store was not found.",
 			})
-		case domain.ErrUnauthorizedAction:
+		case errors.Is(err, domain.ErrUnauthorizedAction), errors.Is(err, domain.ErrAPIKeyNotAllowed):
 			log.Warn("Unauthorized action attempted", slog.String("error", err.Error()))
 			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
 				Status: http.StatusForbidden,
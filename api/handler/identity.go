@@ -0,0 +1,309 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/meysamhadeli/problem-details"
+	"github.com/samber/do"
+)
+
+type identityHandler struct {
+	i               *do.Injector
+	identityService domain.IdentityService
+}
+
+func NewIdentityHandler(i *do.Injector) (domain.IdentityHandler, error) {
+	identityService, err := do.Invoke[domain.IdentityService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &identityHandler{
+		i:               i,
+		identityService: identityService,
+	}, nil
+}
+
+func (h *identityHandler) Authorize(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "identity"),
+		slog.String("func", "Authorize"),
+	)
+
+	log.Info("Initializing oidc authorize process")
+
+	authorizeResponse, err := h.identityService.Authorize(ctx.Request().Context())
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrOIDCNotConfigured):
+			log.Warn("OIDC not configured", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusNotImplemented, &problem.ProblemDetail{
+				Status: http.StatusNotImplemented,
+				Title:  "Not Available",
+				Detail: "Single sign-on is not available at the moment.",
+			})
+		case errors.Is(err, domain.ErrIdentityProviderFailed):
+			log.Error("Identity provider unavailable", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusBadGateway, &problem.ProblemDetail{
+				Status: http.StatusBadGateway,
+				Title:  "Bad Gateway",
+				Detail: "The identity provider could not be reached. Please try again later.",
+			})
+		default:
+			log.Error("Failed to authorize with oidc", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("OIDC authorize executed successfully")
+	return ctx.JSON(http.StatusOK, authorizeResponse)
+}
+
+func (h *identityHandler) Callback(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "identity"),
+		slog.String("func", "Callback"),
+	)
+
+	log.Info("Initializing oidc callback process")
+
+	var oidcCallbackPayload domain.OIDCCallbackPayload
+	if err := ctx.Bind(&oidcCallbackPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := oidcCallbackPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	sessionResponse, err := h.identityService.Callback(ctx.Request().Context(), oidcCallbackPayload)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTwoFactorRequired):
+			log.Info("Two factor authentication required")
+			return ctx.JSON(http.StatusAccepted, sessionResponse)
+		case errors.Is(err, domain.ErrOIDCNotConfigured):
+			log.Warn("OIDC not configured", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusNotImplemented, &problem.ProblemDetail{
+				Status: http.StatusNotImplemented,
+				Title:  "Not Available",
+				Detail: "Single sign-on is not available at the moment.",
+			})
+		case errors.Is(err, domain.ErrOIDCStateInvalid):
+			log.Warn("Invalid oidc state", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+				Status: http.StatusBadRequest,
+				Title:  "Invalid State",
+				Detail: "Your sign-in request is invalid or has expired. Please try again.",
+			})
+		case errors.Is(err, domain.ErrOIDCTokenInvalid):
+			log.Warn("Invalid oidc token", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "Invalid Credentials",
+				Detail: "The identity provider response could not be verified. Please try again.",
+			})
+		case errors.Is(err, domain.ErrOIDCEmailNotVerified):
+			log.Warn("OIDC email not verified", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Email Not Verified",
+				Detail: "Your email must be verified by the identity provider to sign in.",
+			})
+		case errors.Is(err, domain.ErrOIDCLinkRequired):
+			log.Warn("OIDC identity must be linked", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+				Status: http.StatusConflict,
+				Title:  "Link Required",
+				Detail: "An account with this email already exists. Sign in with your password and link single sign-on from your account.",
+			})
+		case errors.Is(err, domain.ErrUserAlreadyExists):
+			log.Warn("Email belongs to a deleted account")
+			return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+				Status: http.StatusConflict,
+				Title:  "Conflict",
+				Detail: "This email belongs to an account that is scheduled for deletion.",
+			})
+		case errors.Is(err, domain.ErrIdentityProviderFailed):
+			log.Error("Identity provider unavailable", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusBadGateway, &problem.ProblemDetail{
+				Status: http.StatusBadGateway,
+				Title:  "Bad Gateway",
+				Detail: "The identity provider could not be reached. Please try again later.",
+			})
+		case errors.Is(err, domain.ErrUserSuspended):
+			log.Warn("User suspended", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Account Suspended",
+				Detail: "Your account has been suspended. Please contact support.",
+			})
+		case errors.Is(err, domain.ErrPasswordResetRequired):
+			log.Warn("Password reset required", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Password Reset Required",
+				Detail: "You must reset your password before signing in.",
+			})
+		default:
+			log.Error("Failed to sign in with oidc", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("OIDC callback executed successfully")
+	return ctx.JSON(http.StatusOK, sessionResponse)
+}
+
+func (h *identityHandler) AuthorizeLink(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "identity"),
+		slog.String("func", "AuthorizeLink"),
+	)
+
+	log.Info("Initializing oidc authorize link process")
+
+	authorizeResponse, err := h.identityService.AuthorizeLink(ctx.Request().Context())
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserNotFoundInContext):
+			log.Warn("User not found in context", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Unauthorized",
+				Detail: "You need to be logged in to access this resource.",
+			})
+		case errors.Is(err, domain.ErrOIDCNotConfigured):
+			log.Warn("OIDC not configured", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusNotImplemented, &problem.ProblemDetail{
+				Status: http.StatusNotImplemented,
+				Title:  "Not Available",
+				Detail: "Single sign-on is not available at the moment.",
+			})
+		case errors.Is(err, domain.ErrIdentityProviderFailed):
+			log.Error("Identity provider unavailable", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusBadGateway, &problem.ProblemDetail{
+				Status: http.StatusBadGateway,
+				Title:  "Bad Gateway",
+				Detail: "The identity provider could not be reached. Please try again later.",
+			})
+		default:
+			log.Error("Failed to authorize oidc link", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("OIDC authorize link executed successfully")
+	return ctx.JSON(http.StatusOK, authorizeResponse)
+}
+
+func (h *identityHandler) Link(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "identity"),
+		slog.String("func", "Link"),
+	)
+
+	log.Info("Initializing oidc link process")
+
+	var oidcCallbackPayload domain.OIDCCallbackPayload
+	if err := ctx.Bind(&oidcCallbackPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := oidcCallbackPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := h.identityService.Link(ctx.Request().Context(), oidcCallbackPayload); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserNotFoundInContext), errors.Is(err, domain.ErrUserNotFound):
+			log.Warn("User not found", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Unauthorized",
+				Detail: "You need to be logged in to access this resource.",
+			})
+		case errors.Is(err, domain.ErrOIDCNotConfigured):
+			log.Warn("OIDC not configured", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusNotImplemented, &problem.ProblemDetail{
+				Status: http.StatusNotImplemented,
+				Title:  "Not Available",
+				Detail: "Single sign-on is not available at the moment.",
+			})
+		case errors.Is(err, domain.ErrOIDCStateInvalid):
+			log.Warn("Invalid oidc state", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+				Status: http.StatusBadRequest,
+				Title:  "Invalid State",
+				Detail: "Your link request is invalid or has expired. Please try again.",
+			})
+		case errors.Is(err, domain.ErrOIDCTokenInvalid):
+			log.Warn("Invalid oidc token", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "Invalid Credentials",
+				Detail: "The identity provider response could not be verified. Please try again.",
+			})
+		case errors.Is(err, domain.ErrIdentityAlreadyLinked):
+			log.Warn("Identity already linked", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+				Status: http.StatusConflict,
+				Title:  "Conflict",
+				Detail: "This single sign-on account is already linked to another user.",
+			})
+		case errors.Is(err, domain.ErrIdentityProviderFailed):
+			log.Error("Identity provider unavailable", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusBadGateway, &problem.ProblemDetail{
+				Status: http.StatusBadGateway,
+				Title:  "Bad Gateway",
+				Detail: "The identity provider could not be reached. Please try again later.",
+			})
+		default:
+			log.Error("Failed to link oidc identity", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("OIDC link executed successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
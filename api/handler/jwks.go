@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+type jwksHandler struct {
+	i *do.Injector
+}
+
+func NewJWKSHandler(i *do.Injector) (domain.JWKSHandler, error) {
+	return &jwksHandler{
+		i: i,
+	}, nil
+}
+
+func (j *jwksHandler) GetJWKS(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "jwks"),
+		slog.String("func", "GetJWKS"),
+	)
+
+	log.Info("Initializing get jwks process")
+
+	ctx.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return ctx.JSON(http.StatusOK, config.Env.KeyRing.JSONWebKeySet())
+}
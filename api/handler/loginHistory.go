@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/meysamhadeli/problem-details"
+	"github.com/samber/do"
+)
+
+type loginHistoryHandler struct {
+	i                   *do.Injector
+	loginHistoryService domain.LoginHistoryService
+}
+
+func NewLoginHistoryHandler(i *do.Injector) (domain.LoginHistoryHandler, error) {
+	loginHistoryService, err := do.Invoke[domain.LoginHistoryService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loginHistoryHandler{
+		i:                   i,
+		loginHistoryService: loginHistoryService,
+	}, nil
+}
+
+func (l *loginHistoryHandler) GetAll(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "loginHistory"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all login history process")
+
+	var loginHistoryQueryPayload domain.LoginHistoryQueryPayload
+	if err := ctx.Bind(&loginHistoryQueryPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := loginHistoryQueryPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	loginHistoriesResponse, err := l.loginHistoryService.GetAll(ctx.Request().Context(), loginHistoryQueryPayload)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFoundInContext) {
+			log.Warn("User not found in context", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Forbidden",
+				Detail: "User not found in context. Please log in again.",
+			})
+		}
+
+		log.Error("Failed to get login history", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	log.Info("Get all login history executed successfully")
+	return ctx.JSON(http.StatusOK, loginHistoriesResponse)
+}
+
+func (l *loginHistoryHandler) Report(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "loginHistory"),
+		slog.String("func", "Report"),
+	)
+
+	log.Info("Initializing login report process")
+
+	var loginReportPayload domain.LoginReportPayload
+	if err := ctx.Bind(&loginReportPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := loginReportPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := l.loginHistoryService.Report(ctx.Request().Context(), loginReportPayload); err != nil {
+		if errors.Is(err, domain.ErrLoginReportInvalid) {
+			log.Warn("Invalid login report", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+				Status: http.StatusBadRequest,
+				Title:  "Invalid Link",
+				Detail: "This link is invalid, has expired or was already used.",
+			})
+		}
+
+		log.Error("Failed to report login", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	log.Info("Login report executed successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/meysamhadeli/problem-details"
+	"github.com/samber/do"
+)
+
+type magicLinkHandler struct {
+	i                *do.Injector
+	magicLinkService domain.MagicLinkService
+}
+
+func NewMagicLinkHandler(i *do.Injector) (domain.MagicLinkHandler, error) {
+	magicLinkService, err := do.Invoke[domain.MagicLinkService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &magicLinkHandler{
+		i:                i,
+		magicLinkService: magicLinkService,
+	}, nil
+}
+
+func (m *magicLinkHandler) Request(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "magicLink"),
+		slog.String("func", "Request"),
+	)
+
+	log.Info("Initializing magic link request process")
+
+	var magicLinkPayload domain.MagicLinkPayload
+	if err := ctx.Bind(&magicLinkPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := magicLinkPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	magicLinkResponse, err := m.magicLinkService.Request(ctx.Request().Context(), magicLinkPayload)
+	if err != nil {
+		var attemptBlockedError *domain.AttemptBlockedError
+		if errors.As(err, &attemptBlockedError) {
+			return attemptBlockedResponse(ctx, log, attemptBlockedError)
+		}
+
+		log.Error("Failed to request magic link", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	log.Info("Magic link request executed successfully")
+	return ctx.JSON(http.StatusAccepted, magicLinkResponse)
+}
+
+func (m *magicLinkHandler) SignIn(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "magicLink"),
+		slog.String("func", "SignIn"),
+	)
+
+	log.Info("Initializing magic link sign in process")
+
+	var magicLinkSignInPayload domain.MagicLinkSignInPayload
+	if err := ctx.Bind(&magicLinkSignInPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := magicLinkSignInPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	sessionResponse, err := m.magicLinkService.SignIn(ctx.Request().Context(), magicLinkSignInPayload)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTwoFactorRequired):
+			log.Info("Two factor authentication required")
+			return ctx.JSON(http.StatusAccepted, sessionResponse)
+		case errors.Is(err, domain.ErrMagicLinkInvalid):
+			log.Warn("Invalid magic link", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "Invalid Link",
+				Detail: "This sign in link is invalid, has expired or was already used. Please request a new one.",
+			})
+		case errors.Is(err, domain.ErrMagicLinkDeviceMismatch):
+			log.Warn("Magic link device mismatch", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "Invalid Device",
+				Detail: "This sign in link must be opened on the device that requested it. Please request a new one.",
+			})
+		case errors.Is(err, domain.ErrUserSuspended):
+			log.Warn("User suspended", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Account Suspended",
+				Detail: "Your account has been suspended. Please contact support.",
+			})
+		case errors.Is(err, domain.ErrPasswordResetRequired):
+			log.Warn("Password reset required", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Password Reset Required",
+				Detail: "You must reset your password before signing in.",
+			})
+		default:
+			log.Error("Failed to sign in with magic link", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("Magic link sign in executed successfully")
+	return ctx.JSON(http.StatusOK, sessionResponse)
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/meysamhadeli/problem-details"
+	"github.com/samber/do"
+)
+
+type publicStoreHandler struct {
+	i                  *do.Injector
+	publicStoreService domain.PublicStoreService
+}
+
+func NewPublicStoreHandler(i *do.Injector) (domain.PublicStoreHandler, error) {
+	publicStoreService, err := do.Invoke[domain.PublicStoreService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &publicStoreHandler{
+		i:                  i,
+		publicStoreService: publicStoreService,
+	}, nil
+}
+
+func (p *publicStoreHandler) GetByID(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "publicStore"),
+		slog.String("func", "GetByID"),
+	)
+
+	log.Info("Initializing get public store process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	publicStoreCache, err := p.publicStoreService.GetByID(ctx.Request().Context(), storeID)
+	if err != nil {
+		return p.handleError(ctx, log, err)
+	}
+
+	log.Info("Get public store executed successfully")
+	return p.writeCached(ctx, publicStoreCache)
+}
+
+func (p *publicStoreHandler) GetStorefront(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "publicStore"),
+		slog.String("func", "GetStorefront"),
+	)
+
+	log.Info("Initializing get storefront process")
+
+	publicStoreCache, err := p.publicStoreService.GetStorefront(ctx.Request().Context())
+	if err != nil {
+		return p.handleError(ctx, log, err)
+	}
+
+	log.Info("Get storefront executed successfully")
+	return p.writeCached(ctx, publicStoreCache)
+}
+
+// writeCached answers conditional requests with 304 so the storefront and any
+// CDN in front of it only download the payload again when it changed.
+func (p *publicStoreHandler) writeCached(ctx echo.Context, publicStoreCache *domain.PublicStoreCache) error {
+	header := ctx.Response().Header()
+	header.Set(echo.HeaderCacheControl, fmt.Sprintf("public, max-age=%d, stale-while-revalidate=%d", int(publicStoreCache.MaxAge.Seconds()), int(publicStoreCache.MaxAge.Seconds())*5))
+	header.Set("ETag", publicStoreCache.ETag)
+
+	for _, etag := range strings.Split(ctx.Request().Header.Get("If-None-Match"), ",") {
+		etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+		if etag == publicStoreCache.ETag || etag == "*" {
+			return ctx.NoContent(http.StatusNotModified)
+		}
+	}
+
+	return ctx.Blob(http.StatusOK, echo.MIMEApplicationJSON, publicStoreCache.Body)
+}
+
+func (p *publicStoreHandler) handleError(ctx echo.Context, log *slog.Logger, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrStoreNotFound):
+		log.Warn("Store not found", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+			Status: http.StatusNotFound,
+			Title:  "Store Not Found",
+			Detail: "The specified store was not found.",
+		})
+	default:
+		log.Error("Failed to process public store request", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+}
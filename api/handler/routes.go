@@ -4,15 +4,26 @@ import (
 	"github.com/GSVillas/e-commercer-api/domain"
 	Middleware "github.com/GSVillas/e-commercer-api/middleware"
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
 	"github.com/samber/do"
 )
 
 func SetupRoutes(e *echo.Echo, i *do.Injector) {
+	e.Use(Middleware.ClientInfo())
 	setupHealthCheckRoutes(e, i)
+	setupJWKSRoutes(e, i)
 	setupUserRoutes(e, i)
+	setupAccountRoutes(e, i)
+	setupAttemptRoutes(e, i)
+	setupSessionRoutes(e, i)
+	setupTwoFactorRoutes(e, i)
+	setupIdentityRoutes(e, i)
+	setupMagicLinkRoutes(e, i)
+	setupLoginHistoryRoutes(e, i)
 	setupStoreRoutes(e, i)
+	setupPublicRoutes(e, i)
+	setupStoreInviteRoutes(e, i)
 	setupBillboardRoutes(e, i)
+	setupAdminRoutes(e, i)
 }
 
 func setupHealthCheckRoutes(e *echo.Echo, i *do.Injector) {
@@ -20,33 +31,158 @@ func setupHealthCheckRoutes(e *echo.Echo, i *do.Injector) {
 	e.GET("/health", healthCheckHandler.HealthCheck)
 }
 
+func setupJWKSRoutes(e *echo.Echo, i *do.Injector) {
+	jwksHandler := do.MustInvoke[domain.JWKSHandler](i)
+	e.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+}
+
 func setupUserRoutes(e *echo.Echo, i *do.Injector) {
 	userHandler := do.MustInvoke[domain.UserHandler](i)
 	group := e.Group("/v1/users")
 	group.POST("", userHandler.Create)
 	group.POST("/signIn", userHandler.SignIn)
+	group.POST("/token/refresh", userHandler.RefreshToken)
+	group.POST("/signOut", userHandler.SignOut, Middleware.CheckLoggedIn(i))
 	group.PATCH("/name", userHandler.UpdateName, Middleware.CheckLoggedIn(i))
+	group.PATCH("/username", userHandler.UpdateUsername, Middleware.CheckLoggedIn(i))
 	group.PATCH("/password", userHandler.UpdatePassword, Middleware.CheckLoggedIn(i))
+	group.POST("/password/forgot", userHandler.ForgotPassword)
+	group.POST("/password/reset", userHandler.ResetPassword)
 	group.GET("/me", userHandler.GetUserInfo, Middleware.CheckLoggedIn(i))
 	group.PATCH("/email/confirm", userHandler.ConfirmEmail, Middleware.CheckLoggedIn(i))
-	group.POST("/resend-code", userHandler.ResendCode, middleware.RateLimiterWithConfig(
-		middleware.RateLimiterConfig{
-			Skipper: middleware.DefaultSkipper,
-			Store:   middleware.NewRateLimiterMemoryStore(20),
-		}))
+	group.POST("/resend-code", userHandler.ResendCode)
+	group.POST("/email/change", userHandler.ChangeEmail, Middleware.CheckLoggedIn(i))
+	group.POST("/email/change/confirm", userHandler.ConfirmEmailChange, Middleware.CheckLoggedIn(i))
+	group.POST("/email/change/cancel", userHandler.CancelEmailChange)
+	group.PUT("/avatar", userHandler.UpdateAvatar, Middleware.CheckLoggedIn(i))
+	group.DELETE("/avatar", userHandler.DeleteAvatar, Middleware.CheckLoggedIn(i))
+	e.GET("/v1/profiles/:username", userHandler.GetProfile)
+}
+
+func setupAccountRoutes(e *echo.Echo, i *do.Injector) {
+	accountHandler := do.MustInvoke[domain.AccountHandler](i)
+	group := e.Group("/v1/users/me", Middleware.CheckLoggedIn(i))
+	group.DELETE("", accountHandler.Delete)
+	group.POST("/delete/code", accountHandler.SendDeletionCode)
+	group.GET("/export", accountHandler.Export)
+}
+
+func setupAttemptRoutes(e *echo.Echo, i *do.Injector) {
+	attemptHandler := do.MustInvoke[domain.AttemptHandler](i)
+	e.POST("/v1/users/unlock", attemptHandler.Unlock)
+}
+
+func setupSessionRoutes(e *echo.Echo, i *do.Injector) {
+	sessionHandler := do.MustInvoke[domain.SessionHandler](i)
+	group := e.Group("/v1/users/sessions", Middleware.CheckLoggedIn(i))
+	group.GET("", sessionHandler.GetAll)
+	group.DELETE("", sessionHandler.DeleteOthers)
+	group.DELETE("/:id", sessionHandler.Delete)
+}
+
+func setupTwoFactorRoutes(e *echo.Echo, i *do.Injector) {
+	twoFactorHandler := do.MustInvoke[domain.TwoFactorHandler](i)
+	e.POST("/v1/users/signIn/2fa", twoFactorHandler.SignIn)
+	group := e.Group("/v1/users/2fa", Middleware.CheckLoggedIn(i))
+	group.POST("/enroll", twoFactorHandler.Enroll)
+	group.POST("/confirm", twoFactorHandler.Confirm)
+	group.DELETE("", twoFactorHandler.Disable)
+}
+
+func setupIdentityRoutes(e *echo.Echo, i *do.Injector) {
+	identityHandler := do.MustInvoke[domain.IdentityHandler](i)
+	group := e.Group("/v1/users/oidc")
+	group.GET("/authorize", identityHandler.Authorize)
+	group.POST("/callback", identityHandler.Callback)
+	group.GET("/link/authorize", identityHandler.AuthorizeLink, Middleware.CheckLoggedIn(i))
+	group.POST("/link", identityHandler.Link, Middleware.CheckLoggedIn(i))
+}
+
+func setupMagicLinkRoutes(e *echo.Echo, i *do.Injector) {
+	magicLinkHandler := do.MustInvoke[domain.MagicLinkHandler](i)
+	group := e.Group("/v1/users/signIn/magic-link")
+	group.POST("", magicLinkHandler.Request)
+	group.POST("/verify", magicLinkHandler.SignIn)
+}
+
+func setupLoginHistoryRoutes(e *echo.Echo, i *do.Injector) {
+	loginHistoryHandler := do.MustInvoke[domain.LoginHistoryHandler](i)
+	e.GET("/v1/users/me/logins", loginHistoryHandler.GetAll, Middleware.CheckLoggedIn(i))
+	e.POST("/v1/users/logins/report", loginHistoryHandler.Report)
+}
+
+func setupAdminRoutes(e *echo.Echo, i *do.Injector) {
+	sessionHandler := do.MustInvoke[domain.SessionHandler](i)
+	adminHandler := do.MustInvoke[domain.AdminHandler](i)
+	group := e.Group("/v1/admin", Middleware.CheckAdminKey())
+	group.POST("/sessions/revoke", sessionHandler.RevokeToken)
+	group.DELETE("/users/:userId/sessions", sessionHandler.RevokeUserSessions)
+	group.PATCH("/users/:userId/role", adminHandler.UpdateUserRole)
+
+	superAdminGroup := e.Group("/v1/admin", Middleware.CheckLoggedIn(i), Middleware.RequireSuperAdmin())
+	superAdminGroup.GET("/users", adminHandler.GetUsers)
+	superAdminGroup.POST("/users/:userId/suspend", adminHandler.SuspendUser)
+	superAdminGroup.POST("/users/:userId/unsuspend", adminHandler.UnsuspendUser)
+	superAdminGroup.POST("/users/:userId/email/confirm", adminHandler.ConfirmUserEmail)
+	superAdminGroup.GET("/stores", adminHandler.GetStores)
+	superAdminGroup.POST("/stores/:storeId/restore", adminHandler.RestoreStore)
 }
 
 func setupStoreRoutes(e *echo.Echo, i *do.Injector) {
 	storeHandler := do.MustInvoke[domain.StoreHandler](i)
-	group := e.Group("/v1/stores", Middleware.CheckLoggedIn(i))
-	group.POST("", storeHandler.Create)
-	group.GET("", storeHandler.GetAll)
-	group.PATCH("/:storeId/name", storeHandler.UpdateName)
-	group.DELETE("/:storeId", storeHandler.Delete)
+	apiKeyHandler := do.MustInvoke[domain.APIKeyHandler](i)
+	storeMemberHandler := do.MustInvoke[domain.StoreMemberHandler](i)
+	storeInviteHandler := do.MustInvoke[domain.StoreInviteHandler](i)
+	auditHandler := do.MustInvoke[domain.AuditHandler](i)
+	storeDomainHandler := do.MustInvoke[domain.StoreDomainHandler](i)
+	group := e.Group("/v1/stores", Middleware.CheckLoggedInOrAPIKey(i))
+	group.POST("", storeHandler.Create, Middleware.RejectAPIKey())
+	group.GET("", storeHandler.GetAll, Middleware.RequireScope(domain.ScopeStoresRead))
+	group.GET("/trash", storeHandler.GetTrash, Middleware.RejectAPIKey())
+	group.GET("/:storeId", storeHandler.GetByID, Middleware.RequireScope(domain.ScopeStoresRead))
+	group.PATCH("/:storeId/name", storeHandler.UpdateName, Middleware.RequireScope(domain.ScopeStoresWrite))
+	group.PATCH("/:storeId/slug", storeHandler.UpdateSlug, Middleware.RequireScope(domain.ScopeStoresWrite))
+	group.PATCH("/:storeId/visibility", storeHandler.UpdateVisibility, Middleware.RequireScope(domain.ScopeStoresWrite))
+	group.GET("/:storeId/settings", storeHandler.GetSettings, Middleware.RequireScope(domain.ScopeStoresRead))
+	group.PATCH("/:storeId/settings", storeHandler.UpdateSettings, Middleware.RequireScope(domain.ScopeStoresWrite))
+	group.DELETE("/:storeId", storeHandler.Delete, Middleware.RejectAPIKey())
+	group.POST("/:storeId/restore", storeHandler.Restore, Middleware.RejectAPIKey())
+	group.POST("/:storeId/api-keys", apiKeyHandler.Create, Middleware.RejectAPIKey())
+	group.GET("/:storeId/api-keys", apiKeyHandler.GetAll, Middleware.RejectAPIKey())
+	group.DELETE("/:storeId/api-keys/:apiKeyId", apiKeyHandler.Revoke, Middleware.RejectAPIKey())
+	group.GET("/:storeId/members", storeMemberHandler.GetAll, Middleware.RejectAPIKey())
+	group.PATCH("/:storeId/members/:userId", storeMemberHandler.UpdateRole, Middleware.RejectAPIKey())
+	group.DELETE("/:storeId/members/:userId", storeMemberHandler.Delete, Middleware.RejectAPIKey())
+	group.POST("/:storeId/invites", storeInviteHandler.Create, Middleware.RejectAPIKey())
+	group.GET("/:storeId/invites", storeInviteHandler.GetAll, Middleware.RejectAPIKey())
+	group.POST("/:storeId/invites/:inviteId/resend", storeInviteHandler.Resend, Middleware.RejectAPIKey())
+	group.DELETE("/:storeId/invites/:inviteId", storeInviteHandler.Revoke, Middleware.RejectAPIKey())
+	group.GET("/:storeId/audit", auditHandler.GetAll, Middleware.RejectAPIKey())
+	group.GET("/:storeId/audit/export", auditHandler.Export, Middleware.RejectAPIKey())
+	group.POST("/:storeId/domains", storeDomainHandler.Create, Middleware.RejectAPIKey())
+	group.GET("/:storeId/domains", storeDomainHandler.GetAll, Middleware.RejectAPIKey())
+	group.POST("/:storeId/domains/:domainId/verify", storeDomainHandler.Verify, Middleware.RejectAPIKey())
+	group.DELETE("/:storeId/domains/:domainId", storeDomainHandler.Delete, Middleware.RejectAPIKey())
+}
+
+func setupPublicRoutes(e *echo.Echo, i *do.Injector) {
+	publicStoreHandler := do.MustInvoke[domain.PublicStoreHandler](i)
+	group := e.Group(Middleware.PublicPathPrefix, Middleware.PublicCORS())
+	group.GET("/stores/:storeId", publicStoreHandler.GetByID)
+	group.GET("/storefront", publicStoreHandler.GetStorefront, Middleware.ResolveStore(i))
+	group.GET("/storefront/:storeSlug", publicStoreHandler.GetStorefront, Middleware.ResolveStore(i))
+}
+
+func setupStoreInviteRoutes(e *echo.Echo, i *do.Injector) {
+	storeInviteHandler := do.MustInvoke[domain.StoreInviteHandler](i)
+	group := e.Group("/v1/invites")
+	group.POST("/accept", storeInviteHandler.Accept, Middleware.CheckLoggedIn(i))
+	group.POST("/decline", storeInviteHandler.Decline)
+	group.POST("/signUp", storeInviteHandler.SignUp)
 }
 
 func setupBillboardRoutes(e *echo.Echo, i *do.Injector) {
 	billboardHandler := do.MustInvoke[domain.BillboardHandler](i)
-	group := e.Group("/v1/:storeId/billboard", Middleware.CheckLoggedIn(i))
-	group.POST("", billboardHandler.Create)
+	group := e.Group("/v1/:storeId/billboard", Middleware.CheckLoggedInOrAPIKey(i))
+	group.POST("", billboardHandler.Create, Middleware.RequireScope(domain.ScopeBillboardsWrite))
 }
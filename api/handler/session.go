@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/meysamhadeli/problem-details"
+	"github.com/samber/do"
+)
+
+type sessionHandler struct {
+	i              *do.Injector
+	sessionService domain.SessionService
+}
+
+func NewSessionHandler(i *do.Injector) (domain.SessionHandler, error) {
+	sessionService, err := do.Invoke[domain.SessionService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sessionHandler{
+		i:              i,
+		sessionService: sessionService,
+	}, nil
+}
+
+func (s *sessionHandler) GetAll(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "session"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all sessions process")
+
+	sessionsResponse, err := s.sessionService.GetAll(ctx.Request().Context())
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFoundInContext) {
+			log.Warn("User not found in context", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Forbidden",
+				Detail: "User not found in context. Please log in again.",
+			})
+		}
+
+		log.Error("Failed to get all sessions", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	log.Info("Successfully retrieved all sessions")
+	return ctx.JSON(http.StatusOK, sessionsResponse)
+}
+
+func (s *sessionHandler) Delete(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "session"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing revoke session process")
+
+	param := ctx.Param("id")
+
+	sessionID, err := uuid.Parse(param)
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := s.sessionService.Delete(ctx.Request().Context(), sessionID); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserNotFoundInContext):
+			log.Warn("User not found in context", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Forbidden",
+				Detail: "User not found in context. Please log in again.",
+			})
+		case errors.Is(err, domain.ErrSessionNotFound):
+			log.Warn("Session not found", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+				Status: http.StatusNotFound,
+				Title:  "Session Not Found",
+				Detail: "The specified session was not found.",
+			})
+		default:
+			log.Error("Failed to revoke session", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("Session revoked successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (s *sessionHandler) DeleteOthers(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "session"),
+		slog.String("func", "DeleteOthers"),
+	)
+
+	log.Info("Initializing revoke other sessions process")
+
+	if err := s.sessionService.DeleteOthers(ctx.Request().Context()); err != nil {
+		if errors.Is(err, domain.ErrUserNotFoundInContext) {
+			log.Warn("User not found in context", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Forbidden",
+				Detail: "User not found in context. Please log in again.",
+			})
+		}
+
+		log.Error("Failed to revoke other sessions", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	log.Info("Other sessions revoked successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (s *sessionHandler) RevokeToken(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "session"),
+		slog.String("func", "RevokeToken"),
+	)
+
+	log.Info("Initializing revoke token process")
+
+	var revokeTokenPayload domain.RevokeTokenPayload
+	if err := ctx.Bind(&revokeTokenPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := revokeTokenPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := s.sessionService.RevokeToken(ctx.Request().Context(), revokeTokenPayload.Token); err != nil {
+		if errors.Is(err, domain.ErrTokenInvalid) {
+			log.Warn("Invalid token", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+				Status: http.StatusBadRequest,
+				Title:  "Invalid Token",
+				Detail: "The token provided is not a token issued by this API.",
+			})
+		}
+
+		log.Error("Failed to revoke token", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	log.Info("Token revoked successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (s *sessionHandler) RevokeUserSessions(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "session"),
+		slog.String("func", "RevokeUserSessions"),
+	)
+
+	log.Info("Initializing revoke user sessions process")
+
+	param := ctx.Param("userId")
+
+	userID, err := uuid.Parse(param)
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := s.sessionService.RevokeUserSessions(ctx.Request().Context(), userID); err != nil {
+		log.Error("Failed to revoke user sessions", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	log.Info("User sessions revoked successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 
@@ -55,6 +56,10 @@ func (s *storeHandler) Create(ctx echo.Context) error {
 	}
 
 	if err := storePayload.Validate(); err != nil {
+		if errors.Is(err, domain.ErrStoreSlugInvalid) || errors.Is(err, domain.ErrStoreSlugReserved) {
+			return storeSlugInvalidResponse(ctx, log, err)
+		}
+
 		log.Warn("Invalid payload", slog.String("error", err.Error()))
 		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
 			Status: http.StatusBadRequest,
@@ -65,11 +70,7 @@ func (s *storeHandler) Create(ctx echo.Context) error {
 
 	storeResponse, err := s.storeService.Create(ctx.Request().Context(), storePayload)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
-			Status: http.StatusInternalServerError,
-			Title:  "Internal Server Error",
-			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
-		})
+		return s.handleError(ctx, log, err)
 	}
 
 	log.Info("Store created successfully")
@@ -111,6 +112,33 @@ func (s *storeHandler) GetAll(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, storeResponse)
 }
 
+func (s *storeHandler) GetByID(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "store"),
+		slog.String("func", "GetByID"),
+	)
+
+	log.Info("Initializing get store by id process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	storeDetailResponse, err := s.storeService.GetByID(ctx.Request().Context(), storeID)
+	if err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Get store by id executed successfully")
+	return ctx.JSON(http.StatusOK, storeDetailResponse)
+}
+
 func (s *storeHandler) UpdateName(ctx echo.Context) error {
 	log := slog.With(
 		slog.String("handler", "store"),
@@ -168,17 +196,237 @@ func (s *storeHandler) UpdateName(ctx echo.Context) error {
 	}
 
 	if err := s.storeService.UpdateName(ctx.Request().Context(), storeID, storeNameUpdatePayload); err != nil {
-		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
-			Status: http.StatusInternalServerError,
-			Title:  "Internal Server Error",
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Store name updated successfully")
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (s *storeHandler) UpdateSlug(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "store"),
+		slog.String("func", "UpdateSlug"),
+	)
+
+	log.Info("Initializing store slug update process")
+
+	param := ctx.Param("storeId")
+
+	storeID, err := uuid.Parse(param)
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	var storeSlugUpdatePayload domain.StoreSlugUpdatePayload
+	if err := ctx.Bind(&storeSlugUpdatePayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
 			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
 		})
 	}
 
-	log.Info("Store name updated successfully")
+	if err := storeSlugUpdatePayload.Validate(); err != nil {
+		if errors.Is(err, domain.ErrStoreSlugInvalid) || errors.Is(err, domain.ErrStoreSlugReserved) {
+			return storeSlugInvalidResponse(ctx, log, err)
+		}
+
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := s.userService.CheckStatus(ctx.Request().Context()); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEmailNotConfirmed):
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "unauthorized",
+				Detail: "You need to confirm your email to use this feature",
+			})
+		default:
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	if err := s.storeService.UpdateSlug(ctx.Request().Context(), storeID, storeSlugUpdatePayload); err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Store slug updated successfully")
 	return ctx.NoContent(http.StatusOK)
 }
 
+func (s *storeHandler) UpdateVisibility(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "store"),
+		slog.String("func", "UpdateVisibility"),
+	)
+
+	log.Info("Initializing store visibility update process")
+
+	param := ctx.Param("storeId")
+
+	storeID, err := uuid.Parse(param)
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	var storeVisibilityUpdatePayload domain.StoreVisibilityUpdatePayload
+	if err := ctx.Bind(&storeVisibilityUpdatePayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := storeVisibilityUpdatePayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := s.userService.CheckStatus(ctx.Request().Context()); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEmailNotConfirmed):
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "unauthorized",
+				Detail: "You need to confirm your email to use this feature",
+			})
+		default:
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	if err := s.storeService.UpdateVisibility(ctx.Request().Context(), storeID, storeVisibilityUpdatePayload); err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Store visibility updated successfully")
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (s *storeHandler) GetSettings(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "store"),
+		slog.String("func", "GetSettings"),
+	)
+
+	log.Info("Initializing get store settings process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	storeSettingsResponse, err := s.storeService.GetSettings(ctx.Request().Context(), storeID)
+	if err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Get store settings executed successfully")
+	return ctx.JSON(http.StatusOK, storeSettingsResponse)
+}
+
+func (s *storeHandler) UpdateSettings(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "store"),
+		slog.String("func", "UpdateSettings"),
+	)
+
+	log.Info("Initializing store settings update process")
+
+	param := ctx.Param("storeId")
+
+	storeID, err := uuid.Parse(param)
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	var storeSettingsPayload domain.StoreSettingsPayload
+	if err := ctx.Bind(&storeSettingsPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := storeSettingsPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := s.userService.CheckStatus(ctx.Request().Context()); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEmailNotConfirmed):
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "unauthorized",
+				Detail: "You need to confirm your email to use this feature",
+			})
+		default:
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	storeSettingsResponse, err := s.storeService.UpdateSettings(ctx.Request().Context(), storeID, storeSettingsPayload)
+	if err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Store settings updated successfully")
+	return ctx.JSON(http.StatusOK, storeSettingsResponse)
+}
+
 func (s *storeHandler) Delete(ctx echo.Context) error {
 	log := slog.With(
 		slog.String("handler", "store"),
@@ -217,13 +465,132 @@ func (s *storeHandler) Delete(ctx echo.Context) error {
 	}
 
 	if err := s.storeService.Delete(ctx.Request().Context(), storeID); err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Store deleted successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (s *storeHandler) GetTrash(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "store"),
+		slog.String("func", "GetTrash"),
+	)
+
+	log.Info("Initializing get store trash process")
+
+	trashedStoresResponse, err := s.storeService.GetTrash(ctx.Request().Context())
+	if err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Get store trash executed successfully")
+	return ctx.JSON(http.StatusOK, trashedStoresResponse)
+}
+
+func (s *storeHandler) Restore(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "store"),
+		slog.String("func", "Restore"),
+	)
+
+	log.Info("Initializing store restore process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := s.userService.CheckStatus(ctx.Request().Context()); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEmailNotConfirmed):
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Unauthorized",
+				Detail: "You need to confirm your email to use this feature",
+			})
+		default:
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	storeResponse, err := s.storeService.Restore(ctx.Request().Context(), storeID)
+	if err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Store restored successfully")
+	return ctx.JSON(http.StatusOK, storeResponse)
+}
+
+func (s *storeHandler) handleError(ctx echo.Context, log *slog.Logger, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFoundInContext):
+		log.Warn("User not found in context", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+			Status: http.StatusForbidden,
+			Title:  "Forbidden",
+			Detail: "User not found in context. Please log in again.",
+		})
+	case errors.Is(err, domain.ErrStoreNotFound):
+		log.Warn("Store not found", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+			Status: http.StatusNotFound,
+			Title:  "Store Not Found",
+			Detail: "The specified store was not found.",
+		})
+	case errors.Is(err, domain.ErrStoreSlugIsSame):
+		log.Warn("Store slug is same as before", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+			Status: http.StatusConflict,
+			Title:  "Conflict",
+			Detail: "The slug provided is same as before. Please try again with a different slug.",
+		})
+	case errors.Is(err, domain.ErrStoreSlugAlreadyInUse):
+		log.Warn("Store slug already in use", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+			Status: http.StatusConflict,
+			Title:  "Slug Already In Use",
+			Detail: "The slug provided is already in use. Please choose a different one.",
+		})
+	case errors.Is(err, domain.ErrUnauthorizedAction), errors.Is(err, domain.ErrAPIKeyNotAllowed):
+		log.Warn("Unauthorized action", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+			Status: http.StatusForbidden,
+			Title:  "Forbidden",
+			Detail: "You are not allowed to perform this action.",
+		})
+	default:
+		log.Error("Failed to process store request", slog.String("error", err.Error()))
 		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
 			Status: http.StatusInternalServerError,
 			Title:  "Internal Server Error",
 			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
 		})
 	}
+}
 
-	log.Info("Store deleted successfully")
-	return ctx.NoContent(http.StatusNoContent)
+func storeSlugInvalidResponse(ctx echo.Context, log *slog.Logger, err error) error {
+	log.Warn("Invalid store slug", slog.String("error", err.Error()))
+
+	detail := fmt.Sprintf("Slugs must be %d to %d characters long and may only contain lowercase letters, digits and single hyphens, starting and ending with a letter or digit.", domain.MinStoreSlugLength, domain.MaxStoreSlugLength)
+	if errors.Is(err, domain.ErrStoreSlugReserved) {
+		detail = "This slug is reserved. Please choose a different one."
+	}
+
+	return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+		Status: http.StatusBadRequest,
+		Title:  "Invalid Slug",
+		Detail: detail,
+	})
 }
@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/e-commercer-api/client"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/meysamhadeli/problem-details"
+	"github.com/samber/do"
+)
+
+type storeDomainHandler struct {
+	i                  *do.Injector
+	storeDomainService domain.StoreDomainService
+	userService        domain.UserService
+}
+
+func NewStoreDomainHandler(i *do.Injector) (domain.StoreDomainHandler, error) {
+	storeDomainService, err := do.Invoke[domain.StoreDomainService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	userService, err := do.Invoke[domain.UserService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storeDomainHandler{
+		i:                  i,
+		storeDomainService: storeDomainService,
+		userService:        userService,
+	}, nil
+}
+
+func (s *storeDomainHandler) Create(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "storeDomain"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing store domain creation process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	var storeDomainPayload domain.StoreDomainPayload
+	if err := ctx.Bind(&storeDomainPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := storeDomainPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := s.userService.CheckStatus(ctx.Request().Context()); err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	storeDomainResponse, err := s.storeDomainService.Create(ctx.Request().Context(), storeID, storeDomainPayload)
+	if err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Store domain created successfully")
+	return ctx.JSON(http.StatusCreated, storeDomainResponse)
+}
+
+func (s *storeDomainHandler) GetAll(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "storeDomain"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all store domains process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	storeDomainsResponse, err := s.storeDomainService.GetAll(ctx.Request().Context(), storeID)
+	if err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Get all store domains executed successfully")
+	return ctx.JSON(http.StatusOK, storeDomainsResponse)
+}
+
+func (s *storeDomainHandler) Verify(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "storeDomain"),
+		slog.String("func", "Verify"),
+	)
+
+	log.Info("Initializing store domain verification process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	storeDomainID, err := uuid.Parse(ctx.Param("domainId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := s.userService.CheckStatus(ctx.Request().Context()); err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	storeDomainResponse, err := s.storeDomainService.Verify(ctx.Request().Context(), storeID, storeDomainID)
+	if err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Store domain verified successfully")
+	return ctx.JSON(http.StatusOK, storeDomainResponse)
+}
+
+func (s *storeDomainHandler) Delete(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "storeDomain"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing store domain delete process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	storeDomainID, err := uuid.Parse(ctx.Param("domainId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := s.userService.CheckStatus(ctx.Request().Context()); err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	if err := s.storeDomainService.Delete(ctx.Request().Context(), storeID, storeDomainID); err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Store domain deleted successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (s *storeDomainHandler) handleError(ctx echo.Context, log *slog.Logger, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFoundInContext):
+		log.Warn("User not found in context", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+			Status: http.StatusForbidden,
+			Title:  "Forbidden",
+			Detail: "User not found in context. Please log in again.",
+		})
+	case errors.Is(err, domain.ErrEmailNotConfirmed):
+		log.Warn("Email not confirmed", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+			Status: http.StatusForbidden,
+			Title:  "unauthorized",
+			Detail: "You need to confirm your email to use this feature",
+		})
+	case errors.Is(err, domain.ErrStoreNotFound):
+		log.Warn("Store not found", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+			Status: http.StatusNotFound,
+			Title:  "Store Not Found",
+			Detail: "The specified store was not found.",
+		})
+	case errors.Is(err, domain.ErrStoreDomainNotFound):
+		log.Warn("Store domain not found", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+			Status: http.StatusNotFound,
+			Title:  "Domain Not Found",
+			Detail: "The specified domain was not found for this store.",
+		})
+	case errors.Is(err, domain.ErrStoreDomainAlreadyInUse):
+		log.Warn("Hostname already in use", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+			Status: http.StatusConflict,
+			Title:  "Domain Already In Use",
+			Detail: "The hostname provided is already in use by a store.",
+		})
+	case errors.Is(err, domain.ErrStoreDomainNotVerified):
+		log.Warn("Hostname verification failed", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Verification Failed",
+			Detail: "The verification TXT record was not found. DNS changes can take a while to propagate, please try again later.",
+		})
+	case errors.Is(err, client.ErrDNSLookupFailed):
+		log.Error("DNS lookup failed", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadGateway, &problem.ProblemDetail{
+			Status: http.StatusBadGateway,
+			Title:  "DNS Lookup Failed",
+			Detail: "We could not check the DNS records for this hostname. Please try again later.",
+		})
+	case errors.Is(err, domain.ErrUnauthorizedAction), errors.Is(err, domain.ErrAPIKeyNotAllowed):
+		log.Warn("Unauthorized action", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+			Status: http.StatusForbidden,
+			Title:  "Forbidden",
+			Detail: "You are not allowed to perform this action.",
+		})
+	default:
+		log.Error("Failed to process store domain request", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+}
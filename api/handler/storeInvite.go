@@ -0,0 +1,363 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/meysamhadeli/problem-details"
+	"github.com/samber/do"
+)
+
+type storeInviteHandler struct {
+	i                  *do.Injector
+	storeInviteService domain.StoreInviteService
+}
+
+func NewStoreInviteHandler(i *do.Injector) (domain.StoreInviteHandler, error) {
+	storeInviteService, err := do.Invoke[domain.StoreInviteService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storeInviteHandler{
+		i:                  i,
+		storeInviteService: storeInviteService,
+	}, nil
+}
+
+func (s *storeInviteHandler) Create(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "storeInvite"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing store invite creation process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	var storeInvitePayload domain.StoreInvitePayload
+	if err := ctx.Bind(&storeInvitePayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := storeInvitePayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	storeInviteResponse, err := s.storeInviteService.Create(ctx.Request().Context(), storeID, storeInvitePayload)
+	if err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Store invite created successfully")
+	return ctx.JSON(http.StatusCreated, storeInviteResponse)
+}
+
+func (s *storeInviteHandler) GetAll(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "storeInvite"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all store invites process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	storeInvitesResponse, err := s.storeInviteService.GetAll(ctx.Request().Context(), storeID)
+	if err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Get all store invites executed successfully")
+	return ctx.JSON(http.StatusOK, storeInvitesResponse)
+}
+
+func (s *storeInviteHandler) Resend(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "storeInvite"),
+		slog.String("func", "Resend"),
+	)
+
+	log.Info("Initializing store invite resend process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	inviteID, err := uuid.Parse(ctx.Param("inviteId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := s.storeInviteService.Resend(ctx.Request().Context(), storeID, inviteID); err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Store invite resent successfully")
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (s *storeInviteHandler) Revoke(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "storeInvite"),
+		slog.String("func", "Revoke"),
+	)
+
+	log.Info("Initializing store invite revoke process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	inviteID, err := uuid.Parse(ctx.Param("inviteId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := s.storeInviteService.Revoke(ctx.Request().Context(), storeID, inviteID); err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Store invite revoked successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (s *storeInviteHandler) Accept(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "storeInvite"),
+		slog.String("func", "Accept"),
+	)
+
+	log.Info("Initializing store invite accept process")
+
+	var storeInviteTokenPayload domain.StoreInviteTokenPayload
+	if err := ctx.Bind(&storeInviteTokenPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := storeInviteTokenPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	storeResponse, err := s.storeInviteService.Accept(ctx.Request().Context(), storeInviteTokenPayload)
+	if err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Store invite accepted successfully")
+	return ctx.JSON(http.StatusOK, storeResponse)
+}
+
+func (s *storeInviteHandler) Decline(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "storeInvite"),
+		slog.String("func", "Decline"),
+	)
+
+	log.Info("Initializing store invite decline process")
+
+	var storeInviteTokenPayload domain.StoreInviteTokenPayload
+	if err := ctx.Bind(&storeInviteTokenPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := storeInviteTokenPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := s.storeInviteService.Decline(ctx.Request().Context(), storeInviteTokenPayload); err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Store invite declined successfully")
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (s *storeInviteHandler) SignUp(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "storeInvite"),
+		slog.String("func", "SignUp"),
+	)
+
+	log.Info("Initializing store invite sign up process")
+
+	var storeInviteSignUpPayload domain.StoreInviteSignUpPayload
+	if err := ctx.Bind(&storeInviteSignUpPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := storeInviteSignUpPayload.Validate(config.Env.PasswordPolicy); err != nil {
+		var passwordPolicyError *domain.PasswordPolicyError
+		if errors.As(err, &passwordPolicyError) {
+			return passwordPolicyResponse(ctx, log, passwordPolicyError)
+		}
+
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	sessionResponse, err := s.storeInviteService.SignUp(ctx.Request().Context(), storeInviteSignUpPayload)
+	if err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("User signed up through store invite successfully")
+	return ctx.JSON(http.StatusCreated, sessionResponse)
+}
+
+func (s *storeInviteHandler) handleError(ctx echo.Context, log *slog.Logger, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFoundInContext):
+		log.Warn("User not found in context", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+			Status: http.StatusForbidden,
+			Title:  "Forbidden",
+			Detail: "User not found in context. Please log in again.",
+		})
+	case errors.Is(err, domain.ErrStoreNotFound):
+		log.Warn("Store not found", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+			Status: http.StatusNotFound,
+			Title:  "Store Not Found",
+			Detail: "The specified store was not found.",
+		})
+	case errors.Is(err, domain.ErrStoreInviteNotFound):
+		log.Warn("Store invite not found", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+			Status: http.StatusNotFound,
+			Title:  "Invite Not Found",
+			Detail: "The specified invite was not found.",
+		})
+	case errors.Is(err, domain.ErrStoreInviteInvalid):
+		log.Warn("Invalid store invite", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Invite",
+			Detail: "This invite is invalid, expired or was already used.",
+		})
+	case errors.Is(err, domain.ErrStoreInviteEmailMismatch):
+		log.Warn("Store invite email mismatch", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+			Status: http.StatusForbidden,
+			Title:  "Forbidden",
+			Detail: "This invite was sent to a different email. Please sign in with the invited account.",
+		})
+	case errors.Is(err, domain.ErrStoreInviteAlreadyExists):
+		log.Warn("Store invite already exists", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+			Status: http.StatusConflict,
+			Title:  "Conflict",
+			Detail: "A pending invite already exists for this email.",
+		})
+	case errors.Is(err, domain.ErrStoreMemberAlreadyExists):
+		log.Warn("Store member already exists", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+			Status: http.StatusConflict,
+			Title:  "Conflict",
+			Detail: "This user is already a member of the store.",
+		})
+	case errors.Is(err, domain.ErrUserAlreadyExists):
+		log.Warn("User already exists", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+			Status: http.StatusConflict,
+			Title:  "Conflict",
+			Detail: "An account already exists for this email. Please sign in to accept the invite.",
+		})
+	case errors.Is(err, domain.ErrUnauthorizedAction), errors.Is(err, domain.ErrAPIKeyNotAllowed):
+		log.Warn("Unauthorized action", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+			Status: http.StatusForbidden,
+			Title:  "Forbidden",
+			Detail: "You are not allowed to perform this action.",
+		})
+	default:
+		log.Error("Failed to process store invite request", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+}
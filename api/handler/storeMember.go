@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/meysamhadeli/problem-details"
+	"github.com/samber/do"
+)
+
+type storeMemberHandler struct {
+	i                  *do.Injector
+	storeMemberService domain.StoreMemberService
+}
+
+func NewStoreMemberHandler(i *do.Injector) (domain.StoreMemberHandler, error) {
+	storeMemberService, err := do.Invoke[domain.StoreMemberService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storeMemberHandler{
+		i:                  i,
+		storeMemberService: storeMemberService,
+	}, nil
+}
+
+func (s *storeMemberHandler) GetAll(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "storeMember"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all store members process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	storeMembersResponse, err := s.storeMemberService.GetAll(ctx.Request().Context(), storeID)
+	if err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Get all store members executed successfully")
+	return ctx.JSON(http.StatusOK, storeMembersResponse)
+}
+
+func (s *storeMemberHandler) UpdateRole(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "storeMember"),
+		slog.String("func", "UpdateRole"),
+	)
+
+	log.Info("Initializing store member role update process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	userID, err := uuid.Parse(ctx.Param("userId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	var storeMemberRolePayload domain.StoreMemberRolePayload
+	if err := ctx.Bind(&storeMemberRolePayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := storeMemberRolePayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := s.storeMemberService.UpdateRole(ctx.Request().Context(), storeID, userID, storeMemberRolePayload); err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Store member role updated successfully")
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (s *storeMemberHandler) Delete(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "storeMember"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing store member delete process")
+
+	storeID, err := uuid.Parse(ctx.Param("storeId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	userID, err := uuid.Parse(ctx.Param("userId"))
+	if err != nil {
+		log.Warn("Invalid params", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := s.storeMemberService.Delete(ctx.Request().Context(), storeID, userID); err != nil {
+		return s.handleError(ctx, log, err)
+	}
+
+	log.Info("Store member deleted successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (s *storeMemberHandler) handleError(ctx echo.Context, log *slog.Logger, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFoundInContext):
+		log.Warn("User not found in context", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+			Status: http.StatusForbidden,
+			Title:  "Forbidden",
+			Detail: "User not found in context. Please log in again.",
+		})
+	case errors.Is(err, domain.ErrStoreNotFound):
+		log.Warn("Store not found", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+			Status: http.StatusNotFound,
+			Title:  "Store Not Found",
+			Detail: "The specified store was not found.",
+		})
+	case errors.Is(err, domain.ErrStoreMemberNotFound):
+		log.Warn("Store member not found", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+			Status: http.StatusNotFound,
+			Title:  "Member Not Found",
+			Detail: "The specified user is not a member of this store.",
+		})
+	case errors.Is(err, domain.ErrStoreOwnerNotEditable):
+		log.Warn("Store owner cannot be edited", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+			Status: http.StatusConflict,
+			Title:  "Conflict",
+			Detail: "The store owner cannot be changed or removed.",
+		})
+	case errors.Is(err, domain.ErrUnauthorizedAction), errors.Is(err, domain.ErrAPIKeyNotAllowed):
+		log.Warn("Unauthorized action", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+			Status: http.StatusForbidden,
+			Title:  "Forbidden",
+			Detail: "You are not allowed to perform this action.",
+		})
+	default:
+		log.Error("Failed to process store member request", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+}
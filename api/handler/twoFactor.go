@@ -0,0 +1,298 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/meysamhadeli/problem-details"
+	"github.com/samber/do"
+)
+
+type twoFactorHandler struct {
+	i                *do.Injector
+	twoFactorService domain.TwoFactorService
+}
+
+func NewTwoFactorHandler(i *do.Injector) (domain.TwoFactorHandler, error) {
+	twoFactorService, err := do.Invoke[domain.TwoFactorService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &twoFactorHandler{
+		i:                i,
+		twoFactorService: twoFactorService,
+	}, nil
+}
+
+func (t *twoFactorHandler) Enroll(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "twoFactor"),
+		slog.String("func", "Enroll"),
+	)
+
+	log.Info("Initializing two factor enroll process")
+
+	enrollResponse, err := t.twoFactorService.Enroll(ctx.Request().Context())
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserNotFoundInContext):
+			log.Warn("User not found in context", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Unauthorized",
+				Detail: "You need to be logged in to access this resource.",
+			})
+		case errors.Is(err, domain.ErrEmailNotConfirmed):
+			log.Warn("Email not confirmed", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Email Not Confirmed",
+				Detail: "You need to confirm your email to use this feature",
+			})
+		case errors.Is(err, domain.ErrTwoFactorAlreadyEnabled):
+			log.Warn("Two factor already enabled", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+				Status: http.StatusConflict,
+				Title:  "Conflict",
+				Detail: "Two-factor authentication is already enabled for this account.",
+			})
+		default:
+			log.Error("Failed to enroll two factor", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("Two factor enroll executed successfully")
+	return ctx.JSON(http.StatusOK, enrollResponse)
+}
+
+func (t *twoFactorHandler) Confirm(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "twoFactor"),
+		slog.String("func", "Confirm"),
+	)
+
+	log.Info("Initializing two factor confirm process")
+
+	var twoFactorCodePayload domain.TwoFactorCodePayload
+	if err := ctx.Bind(&twoFactorCodePayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := twoFactorCodePayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	recoveryCodesResponse, err := t.twoFactorService.Confirm(ctx.Request().Context(), twoFactorCodePayload)
+	if err != nil {
+		var attemptBlockedError *domain.AttemptBlockedError
+		if errors.As(err, &attemptBlockedError) {
+			return attemptBlockedResponse(ctx, log, attemptBlockedError)
+		}
+
+		switch {
+		case errors.Is(err, domain.ErrUserNotFoundInContext):
+			log.Warn("User not found in context", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Unauthorized",
+				Detail: "You need to be logged in to access this resource.",
+			})
+		case errors.Is(err, domain.ErrTwoFactorAlreadyEnabled):
+			log.Warn("Two factor already enabled", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+				Status: http.StatusConflict,
+				Title:  "Conflict",
+				Detail: "Two-factor authentication is already enabled for this account.",
+			})
+		case errors.Is(err, domain.ErrTwoFactorNotEnrolled):
+			log.Warn("Two factor enrollment not started", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+				Status: http.StatusConflict,
+				Title:  "Conflict",
+				Detail: "Start the two-factor enrollment before confirming it.",
+			})
+		case errors.Is(err, domain.ErrTwoFactorCodeInvalid):
+			log.Warn("Invalid two factor code", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "Invalid Code",
+				Detail: "The code provided is invalid. Please check your authenticator app and try again.",
+			})
+		default:
+			log.Error("Failed to confirm two factor", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("Two factor confirmed successfully")
+	return ctx.JSON(http.StatusOK, recoveryCodesResponse)
+}
+
+func (t *twoFactorHandler) Disable(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "twoFactor"),
+		slog.String("func", "Disable"),
+	)
+
+	log.Info("Initializing two factor disable process")
+
+	var twoFactorCodePayload domain.TwoFactorCodePayload
+	if err := ctx.Bind(&twoFactorCodePayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := twoFactorCodePayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := t.twoFactorService.Disable(ctx.Request().Context(), twoFactorCodePayload); err != nil {
+		var attemptBlockedError *domain.AttemptBlockedError
+		if errors.As(err, &attemptBlockedError) {
+			return attemptBlockedResponse(ctx, log, attemptBlockedError)
+		}
+
+		switch {
+		case errors.Is(err, domain.ErrUserNotFoundInContext):
+			log.Warn("User not found in context", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Unauthorized",
+				Detail: "You need to be logged in to access this resource.",
+			})
+		case errors.Is(err, domain.ErrTwoFactorNotEnabled):
+			log.Warn("Two factor not enabled", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+				Status: http.StatusConflict,
+				Title:  "Conflict",
+				Detail: "Two-factor authentication is not enabled for this account.",
+			})
+		case errors.Is(err, domain.ErrTwoFactorCodeInvalid):
+			log.Warn("Invalid two factor code", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "Invalid Code",
+				Detail: "The code provided is invalid. Please check your authenticator app and try again.",
+			})
+		default:
+			log.Error("Failed to disable two factor", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("Two factor disabled successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (t *twoFactorHandler) SignIn(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "twoFactor"),
+		slog.String("func", "SignIn"),
+	)
+
+	log.Info("Initializing two factor sign in process")
+
+	var twoFactorSignInPayload domain.TwoFactorSignInPayload
+	if err := ctx.Bind(&twoFactorSignInPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := twoFactorSignInPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	sessionResponse, err := t.twoFactorService.SignIn(ctx.Request().Context(), twoFactorSignInPayload)
+	if err != nil {
+		var attemptBlockedError *domain.AttemptBlockedError
+		if errors.As(err, &attemptBlockedError) {
+			return attemptBlockedResponse(ctx, log, attemptBlockedError)
+		}
+
+		switch {
+		case errors.Is(err, domain.ErrTwoFactorChallengeInvalid):
+			log.Warn("Invalid two factor challenge", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "Challenge Expired",
+				Detail: "Your sign-in challenge is invalid or has expired. Please sign in again.",
+			})
+		case errors.Is(err, domain.ErrTwoFactorCodeInvalid):
+			log.Warn("Invalid two factor code", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "Invalid Code",
+				Detail: "The code provided is invalid. Please check your authenticator app and try again.",
+			})
+		case errors.Is(err, domain.ErrUserSuspended):
+			log.Warn("User suspended", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Account Suspended",
+				Detail: "Your account has been suspended. Please contact support.",
+			})
+		case errors.Is(err, domain.ErrPasswordResetRequired):
+			log.Warn("Password reset required", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Password Reset Required",
+				Detail: "You must reset your password before signing in.",
+			})
+		default:
+			log.Error("Failed to sign in with two factor", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("Two factor sign in executed successfully")
+	return ctx.JSON(http.StatusOK, sessionResponse)
+}
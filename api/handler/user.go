@@ -2,9 +2,11 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 
+	"github.com/GSVillas/e-commercer-api/config"
 	"github.com/GSVillas/e-commercer-api/domain"
 	"github.com/labstack/echo/v4"
 	"github.com/meysamhadeli/problem-details"
@@ -46,7 +48,16 @@ func (u *userHandler) Create(ctx echo.Context) error {
 		})
 	}
 
-	if err := userPayload.Validate(); err != nil {
+	if err := userPayload.Validate(config.Env.PasswordPolicy); err != nil {
+		var passwordPolicyError *domain.PasswordPolicyError
+		if errors.As(err, &passwordPolicyError) {
+			return passwordPolicyResponse(ctx, log, passwordPolicyError)
+		}
+
+		if errors.Is(err, domain.ErrUsernameInvalid) || errors.Is(err, domain.ErrUsernameReserved) {
+			return usernameInvalidResponse(ctx, log, err)
+		}
+
 		log.Warn("Invalid payload", slog.String("error", err.Error()))
 		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
 			Status: http.StatusBadRequest,
@@ -66,6 +77,15 @@ func (u *userHandler) Create(ctx echo.Context) error {
 			})
 		}
 
+		if errors.Is(err, domain.ErrUsernameAlreadyInUse) {
+			log.Warn("Username already in use")
+			return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+				Status: http.StatusConflict,
+				Title:  "Username Already In Use",
+				Detail: "The username provided is already in use. Please choose a different one.",
+			})
+		}
+
 		if errors.Is(err, domain.ErrHashingPassword) {
 			log.Error("Failed to hash password", slog.String("error", err.Error()))
 			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
@@ -116,6 +136,11 @@ func (u *userHandler) SignIn(ctx echo.Context) error {
 
 	signInResponse, err := u.userService.SignIn(ctx.Request().Context(), signInPayload)
 	if err != nil {
+		var attemptBlockedError *domain.AttemptBlockedError
+		if errors.As(err, &attemptBlockedError) {
+			return attemptBlockedResponse(ctx, log, attemptBlockedError)
+		}
+
 		if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrInvalidPassword) {
 			log.Warn("Invalid credentials")
 			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
@@ -130,6 +155,29 @@ func (u *userHandler) SignIn(ctx echo.Context) error {
 			return ctx.JSON(http.StatusConflict, signInResponse)
 		}
 
+		if errors.Is(err, domain.ErrTwoFactorRequired) {
+			log.Info("Two factor authentication required")
+			return ctx.JSON(http.StatusAccepted, signInResponse)
+		}
+
+		if errors.Is(err, domain.ErrUserSuspended) {
+			log.Warn("User suspended")
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Account Suspended",
+				Detail: "Your account has been suspended. Please contact support.",
+			})
+		}
+
+		if errors.Is(err, domain.ErrPasswordResetRequired) {
+			log.Warn("Password reset required")
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Password Reset Required",
+				Detail: "You must reset your password before signing in.",
+			})
+		}
+
 		log.Error("Failed to sign in user", slog.String("error", err.Error()))
 		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
 			Status: http.StatusInternalServerError,
@@ -200,6 +248,74 @@ func (u *userHandler) UpdateName(ctx echo.Context) error {
 	return ctx.NoContent(http.StatusOK)
 }
 
+func (u *userHandler) UpdateUsername(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "UpdateUsername"),
+	)
+
+	log.Info("Initializing user username update process")
+
+	var updateUsernamePayload domain.UpdateUsernamePayload
+	if err := ctx.Bind(&updateUsernamePayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := updateUsernamePayload.Validate(); err != nil {
+		if errors.Is(err, domain.ErrUsernameInvalid) || errors.Is(err, domain.ErrUsernameReserved) {
+			return usernameInvalidResponse(ctx, log, err)
+		}
+
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := u.userService.UpdateUsername(ctx.Request().Context(), updateUsernamePayload); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserNotFoundInContext), errors.Is(err, domain.ErrUserNotFound):
+			log.Warn("User not found", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Forbidden",
+				Detail: "User not found in context. Please log in again.",
+			})
+		case errors.Is(err, domain.ErrUsernameIsSame):
+			log.Warn("Username is same as before")
+			return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+				Status: http.StatusConflict,
+				Title:  "Conflict",
+				Detail: "The username provided is same as before. Please try again with a different username.",
+			})
+		case errors.Is(err, domain.ErrUsernameAlreadyInUse):
+			log.Warn("Username already in use")
+			return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+				Status: http.StatusConflict,
+				Title:  "Username Already In Use",
+				Detail: "The username provided is already in use. Please choose a different one.",
+			})
+		default:
+			log.Error("Failed to update user username", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("User username updated successfully")
+	return ctx.NoContent(http.StatusOK)
+}
+
 func (u *userHandler) UpdatePassword(ctx echo.Context) error {
 	log := slog.With(
 		slog.String("handler", "user"),
@@ -218,7 +334,12 @@ func (u *userHandler) UpdatePassword(ctx echo.Context) error {
 		})
 	}
 
-	if err := updatePasswordPayload.Validate(); err != nil {
+	if err := updatePasswordPayload.Validate(config.Env.PasswordPolicy); err != nil {
+		var passwordPolicyError *domain.PasswordPolicyError
+		if errors.As(err, &passwordPolicyError) {
+			return passwordPolicyResponse(ctx, log, passwordPolicyError)
+		}
+
 		log.Warn("Invalid payload", slog.String("error", err.Error()))
 		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
 			Status: http.StatusBadRequest,
@@ -228,6 +349,19 @@ func (u *userHandler) UpdatePassword(ctx echo.Context) error {
 	}
 
 	if err := u.userService.UpdatePassword(ctx.Request().Context(), updatePasswordPayload); err != nil {
+		var attemptBlockedError *domain.AttemptBlockedError
+		if errors.As(err, &attemptBlockedError) {
+			return attemptBlockedResponse(ctx, log, attemptBlockedError)
+		}
+
+		if errors.Is(err, domain.ErrUserNotFound) {
+			log.Warn("User not found")
+			return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+				Status: http.StatusNotFound,
+				Title:  "User Not Found",
+				Detail: "The user associated with this session could not be found.",
+			})
+		}
 
 		if errors.Is(err, domain.ErrEmailNotConfirmed) {
 			log.Warn("Email not confirmed")
@@ -290,6 +424,37 @@ func (u *userHandler) GetUserInfo(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, userResponse)
 }
 
+func (u *userHandler) GetProfile(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "GetProfile"),
+	)
+
+	log.Info("Initializing get public profile process")
+
+	publicProfileResponse, err := u.userService.GetProfile(ctx.Request().Context(), ctx.Param("username"))
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			log.Warn("Profile not found", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+				Status: http.StatusNotFound,
+				Title:  "Profile Not Found",
+				Detail: "The specified profile was not found.",
+			})
+		}
+
+		log.Error("Failed to get public profile", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	log.Info("Get public profile executed successfully")
+	return ctx.JSON(http.StatusOK, publicProfileResponse)
+}
+
 func (u *userHandler) ResendCode(ctx echo.Context) error {
 	log := slog.With(
 		slog.String("handler", "user"),
@@ -319,6 +484,12 @@ func (u *userHandler) ResendCode(ctx echo.Context) error {
 	}
 
 	if err := u.userService.ResendCode(ctx.Request().Context(), resendCodePayload); err != nil {
+		var attemptBlockedError *domain.AttemptBlockedError
+		if errors.As(err, &attemptBlockedError) {
+			return attemptBlockedResponse(ctx, log, attemptBlockedError)
+		}
+
+		log.Error("Failed to resend code", slog.String("error", err.Error()))
 		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
 			Status: http.StatusInternalServerError,
 			Title:  "Internal Server Error",
@@ -360,6 +531,11 @@ func (u *userHandler) ConfirmEmail(ctx echo.Context) error {
 
 	err := u.userService.ConfirmEmail(ctx.Request().Context(), confirmEmailPayload)
 	if err != nil {
+		var attemptBlockedError *domain.AttemptBlockedError
+		if errors.As(err, &attemptBlockedError) {
+			return attemptBlockedResponse(ctx, log, attemptBlockedError)
+		}
+
 		switch {
 		case errors.Is(err, domain.ErrUserNotFoundInContext):
 			log.Warn("User not found in context", slog.String("error", err.Error()))
@@ -409,3 +585,571 @@ func (u *userHandler) ConfirmEmail(ctx echo.Context) error {
 	log.Info("Email confirmed successfully")
 	return ctx.NoContent(http.StatusOK)
 }
+
+func (u *userHandler) RefreshToken(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "RefreshToken"),
+	)
+
+	log.Info("Initializing refresh token process")
+
+	var refreshTokenPayload domain.RefreshTokenPayload
+	if err := ctx.Bind(&refreshTokenPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := refreshTokenPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	sessionResponse, err := u.userService.RefreshToken(ctx.Request().Context(), refreshTokenPayload)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRefreshTokenInvalid):
+			log.Warn("Invalid refresh token", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "Invalid Refresh Token",
+				Detail: "The refresh token provided is invalid or has expired. Please log in again.",
+			})
+		case errors.Is(err, domain.ErrRefreshTokenReused):
+			log.Warn("Refresh token reused", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "Refresh Token Revoked",
+				Detail: "This refresh token was already used, so every token issued from it has been revoked. Please log in again.",
+			})
+		case errors.Is(err, domain.ErrUserSuspended):
+			log.Warn("User suspended", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Account Suspended",
+				Detail: "Your account has been suspended. Please contact support.",
+			})
+		default:
+			log.Error("Failed to refresh token", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("Token refreshed successfully")
+	return ctx.JSON(http.StatusOK, sessionResponse)
+}
+
+func (u *userHandler) SignOut(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "SignOut"),
+	)
+
+	log.Info("Initializing sign out process")
+
+	if err := u.userService.SignOut(ctx.Request().Context()); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserNotFoundInContext):
+			log.Warn("User not found in context", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Unauthorized",
+				Detail: "You need to be logged in to access this resource.",
+			})
+		case errors.Is(err, domain.ErrSessionNotFound):
+			log.Warn("Session already finished", slog.String("error", err.Error()))
+			return ctx.NoContent(http.StatusNoContent)
+		default:
+			log.Error("Failed to sign out", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("User signed out successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (u *userHandler) ForgotPassword(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "ForgotPassword"),
+	)
+
+	log.Info("Initializing forgot password process")
+
+	var forgotPasswordPayload domain.ForgotPasswordPayload
+	if err := ctx.Bind(&forgotPasswordPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := forgotPasswordPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := u.userService.ForgotPassword(ctx.Request().Context(), forgotPasswordPayload); err != nil {
+		var attemptBlockedError *domain.AttemptBlockedError
+		if errors.As(err, &attemptBlockedError) {
+			return attemptBlockedResponse(ctx, log, attemptBlockedError)
+		}
+
+		log.Error("Failed to start password reset", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+			Status: http.StatusInternalServerError,
+			Title:  "Internal Server Error",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	log.Info("Forgot password executed successfully")
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+func (u *userHandler) ResetPassword(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "ResetPassword"),
+	)
+
+	log.Info("Initializing reset password process")
+
+	var resetPasswordPayload domain.ResetPasswordPayload
+	if err := ctx.Bind(&resetPasswordPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := resetPasswordPayload.Validate(config.Env.PasswordPolicy); err != nil {
+		var passwordPolicyError *domain.PasswordPolicyError
+		if errors.As(err, &passwordPolicyError) {
+			return passwordPolicyResponse(ctx, log, passwordPolicyError)
+		}
+
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := u.userService.ResetPassword(ctx.Request().Context(), resetPasswordPayload); err != nil {
+		var attemptBlockedError *domain.AttemptBlockedError
+		if errors.As(err, &attemptBlockedError) {
+			return attemptBlockedResponse(ctx, log, attemptBlockedError)
+		}
+
+		switch {
+		case errors.Is(err, domain.ErrOTPExpires):
+			log.Warn("OTP has expired", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "OTP Expired",
+				Detail: "The OTP code has expired. Please request a new code.",
+			})
+		case errors.Is(err, domain.ErrOTPInvalid):
+			log.Warn("Invalid OTP", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "Invalid OTP",
+				Detail: "The OTP code provided is invalid. Please check the code and try again.",
+			})
+		default:
+			log.Error("Failed to reset password", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("Password reset successfully")
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (u *userHandler) ChangeEmail(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "ChangeEmail"),
+	)
+
+	log.Info("Initializing change email process")
+
+	var changeEmailPayload domain.ChangeEmailPayload
+	if err := ctx.Bind(&changeEmailPayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := changeEmailPayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := u.userService.ChangeEmail(ctx.Request().Context(), changeEmailPayload); err != nil {
+		var attemptBlockedError *domain.AttemptBlockedError
+		if errors.As(err, &attemptBlockedError) {
+			return attemptBlockedResponse(ctx, log, attemptBlockedError)
+		}
+
+		switch {
+		case errors.Is(err, domain.ErrUserNotFoundInContext):
+			log.Warn("User not found in context", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Unauthorized",
+				Detail: "You need to be logged in to access this resource.",
+			})
+		case errors.Is(err, domain.ErrInvalidPassword):
+			log.Warn("Invalid password")
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "Unauthorized",
+				Detail: "Invalid password. Please verify your password and try again.",
+			})
+		case errors.Is(err, domain.ErrEmailIsSame):
+			log.Warn("New email is same as current email")
+			return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+				Status: http.StatusConflict,
+				Title:  "Conflict",
+				Detail: "The new email is same as the current email. Please try again with a different email.",
+			})
+		case errors.Is(err, domain.ErrEmailAlreadyInUse):
+			log.Warn("Email already in use")
+			return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+				Status: http.StatusConflict,
+				Title:  "Conflict",
+				Detail: "The email is already in use. Please try again with a different email.",
+			})
+		default:
+			log.Error("Failed to change email", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("Change email process executed successfully")
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (u *userHandler) ConfirmEmailChange(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "ConfirmEmailChange"),
+	)
+
+	log.Info("Initializing confirm email change process")
+
+	var confirmEmailChangePayload domain.ConfirmEmailChangePayload
+	if err := ctx.Bind(&confirmEmailChangePayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := confirmEmailChangePayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := u.userService.ConfirmEmailChange(ctx.Request().Context(), confirmEmailChangePayload); err != nil {
+		var attemptBlockedError *domain.AttemptBlockedError
+		if errors.As(err, &attemptBlockedError) {
+			return attemptBlockedResponse(ctx, log, attemptBlockedError)
+		}
+
+		switch {
+		case errors.Is(err, domain.ErrUserNotFoundInContext):
+			log.Warn("User not found in context", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Unauthorized",
+				Detail: "You need to be logged in to access this resource.",
+			})
+		case errors.Is(err, domain.ErrEmailChangeNotFound):
+			log.Warn("No pending email change")
+			return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+				Status: http.StatusNotFound,
+				Title:  "Not Found",
+				Detail: "There is no pending email change. Please request a new email change.",
+			})
+		case errors.Is(err, domain.ErrOTPExpires):
+			log.Warn("OTP has expired", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "OTP Expired",
+				Detail: "The OTP code has expired. Please request a new code.",
+			})
+		case errors.Is(err, domain.ErrOTPInvalid):
+			log.Warn("Invalid OTP", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "Invalid OTP",
+				Detail: "The OTP code provided is invalid. Please check the code and try again.",
+			})
+		case errors.Is(err, domain.ErrEmailAlreadyInUse):
+			log.Warn("Email already in use")
+			return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+				Status: http.StatusConflict,
+				Title:  "Conflict",
+				Detail: "The email is already in use. Please try again with a different email.",
+			})
+		default:
+			log.Error("Failed to confirm email change", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("Email changed successfully")
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (u *userHandler) CancelEmailChange(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "CancelEmailChange"),
+	)
+
+	log.Info("Initializing cancel email change process")
+
+	var cancelEmailChangePayload domain.CancelEmailChangePayload
+	if err := ctx.Bind(&cancelEmailChangePayload); err != nil {
+		log.Warn("Failed to bind payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusUnprocessableEntity, &problem.ProblemDetail{
+			Status: http.StatusUnprocessableEntity,
+			Title:  "Invalid Request",
+			Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+		})
+	}
+
+	if err := cancelEmailChangePayload.Validate(); err != nil {
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	if err := u.userService.CancelEmailChange(ctx.Request().Context(), cancelEmailChangePayload); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEmailChangeCancelInvalid):
+			log.Warn("Invalid email change cancel token")
+			return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+				Status: http.StatusUnauthorized,
+				Title:  "Invalid Token",
+				Detail: "The cancel link is invalid or has expired.",
+			})
+		case errors.Is(err, domain.ErrEmailAlreadyInUse):
+			log.Warn("Email already in use")
+			return ctx.JSON(http.StatusConflict, &problem.ProblemDetail{
+				Status: http.StatusConflict,
+				Title:  "Conflict",
+				Detail: "The email is already in use. Please try again with a different email.",
+			})
+		default:
+			log.Error("Failed to cancel email change", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("Email change cancelled successfully")
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (u *userHandler) UpdateAvatar(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "UpdateAvatar"),
+	)
+
+	log.Info("Initializing user avatar update process")
+
+	file, err := ctx.FormFile("avatar")
+	if err != nil {
+		log.Warn("Avatar file is invalid", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The 'avatar' field is invalid.",
+		})
+	}
+
+	updateAvatarPayload := domain.UpdateAvatarPayload{
+		Avatar: file,
+	}
+
+	if err := updateAvatarPayload.Validate(); err != nil {
+		if errors.Is(err, domain.ErrAvatarTooLarge) {
+			log.Warn("Avatar file is too large", slog.Int64("size", file.Size))
+			return ctx.JSON(http.StatusRequestEntityTooLarge, &problem.ProblemDetail{
+				Status: http.StatusRequestEntityTooLarge,
+				Title:  "File Too Large",
+				Detail: "The avatar must be at most 5MB.",
+			})
+		}
+
+		log.Warn("Invalid payload", slog.String("error", err.Error()))
+		return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Invalid Request",
+			Detail: "The data provided is incorrect or incomplete. Please verify and try again.",
+		})
+	}
+
+	userResponse, err := u.userService.UpdateAvatar(ctx.Request().Context(), updateAvatarPayload)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserNotFoundInContext):
+			log.Warn("User not found in context", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Unauthorized",
+				Detail: "You need to be logged in to access this resource.",
+			})
+		case errors.Is(err, domain.ErrEmailNotConfirmed):
+			log.Warn("Email not confirmed")
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Email Not Confirmed",
+				Detail: "Your email address is not confirmed. Please check your inbox for the confirmation email and follow the instructions to confirm your email address.",
+			})
+		default:
+			log.Error("Failed to update avatar", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("User avatar updated successfully")
+	return ctx.JSON(http.StatusOK, userResponse)
+}
+
+func (u *userHandler) DeleteAvatar(ctx echo.Context) error {
+	log := slog.With(
+		slog.String("handler", "user"),
+		slog.String("func", "DeleteAvatar"),
+	)
+
+	log.Info("Initializing user avatar delete process")
+
+	if err := u.userService.DeleteAvatar(ctx.Request().Context()); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserNotFoundInContext):
+			log.Warn("User not found in context", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+				Status: http.StatusForbidden,
+				Title:  "Unauthorized",
+				Detail: "You need to be logged in to access this resource.",
+			})
+		case errors.Is(err, domain.ErrAvatarNotFound):
+			log.Warn("User has no avatar")
+			return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+				Status: http.StatusNotFound,
+				Title:  "Not Found",
+				Detail: "You don't have an avatar to remove.",
+			})
+		default:
+			log.Error("Failed to delete avatar", slog.String("error", err.Error()))
+			return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+				Status: http.StatusInternalServerError,
+				Title:  "Internal Server Error",
+				Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+			})
+		}
+	}
+
+	log.Info("User avatar deleted successfully")
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+type passwordPolicyProblem struct {
+	problem.ProblemDetail
+	Errors []domain.PasswordViolationResponse `json:"errors"`
+}
+
+func passwordPolicyResponse(ctx echo.Context, log *slog.Logger, passwordPolicyError *domain.PasswordPolicyError) error {
+	log.Warn("Password does not satisfy the policy", slog.Int("violations", len(passwordPolicyError.Violations)))
+	return ctx.JSON(http.StatusBadRequest, &passwordPolicyProblem{
+		ProblemDetail: problem.ProblemDetail{
+			Status: http.StatusBadRequest,
+			Title:  "Weak Password",
+			Detail: "The password does not meet the security requirements. Please review the errors and try again.",
+		},
+		Errors: passwordPolicyError.Violations,
+	})
+}
+
+func usernameInvalidResponse(ctx echo.Context, log *slog.Logger, err error) error {
+	log.Warn("Invalid username", slog.String("error", err.Error()))
+
+	detail := fmt.Sprintf("Usernames must be %d to %d characters long and may only contain lowercase letters, digits, dots and underscores, starting and ending with a letter or digit.", domain.MinUsernameLength, domain.MaxUsernameLength)
+	if errors.Is(err, domain.ErrUsernameReserved) {
+		detail = "This username is reserved. Please choose a different one."
+	}
+
+	return ctx.JSON(http.StatusBadRequest, &problem.ProblemDetail{
+		Status: http.StatusBadRequest,
+		Title:  "Invalid Username",
+		Detail: detail,
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"mime/multipart"
 	"net/http"
+	"strings"
 
 	jsoniter "github.com/json-iterator/go"
 
@@ -26,10 +27,12 @@ var (
 	ErrDecodeJSON       = errors.New("failed to decode JSON response")
 	ErrUploadFailed     = errors.New("upload failed with status code")
 	ErrCloudflareFailed = errors.New("cloudflare response error")
+	ErrDeleteFailed     = errors.New("delete failed with status code")
 )
 
 type CloudFlareService interface {
 	UploadImage(image *multipart.FileHeader) (string, error)
+	DeleteImage(imageURL string) error
 }
 
 type cloudFlareService struct {
@@ -136,3 +139,61 @@ func (c *cloudFlareService) UploadImage(image *multipart.FileHeader) (string, er
 
 	return imageURL, nil
 }
+
+func (c *cloudFlareService) DeleteImage(imageURL string) error {
+	log := slog.With(
+		slog.String("handler", "cloudFlare"),
+		slog.String("func", "DeleteImage"),
+	)
+
+	log.Info("Initializing image delete process")
+
+	imageID := c.getImageID(imageURL)
+	if imageID == "" {
+		log.Warn("Image is not hosted on cloudflare, skipping delete", slog.String("imageURL", imageURL))
+		return nil
+	}
+
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/%s", strings.TrimSuffix(config.Env.CloudFlareAccountAPI, "/"), imageID), nil)
+	if err != nil {
+		log.Error("Failed to create request", slog.String("error", err.Error()))
+		return ErrCreateRequest
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", config.Env.CloudFlareApiKey))
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Error("Failed to send request", slog.String("error", err.Error()))
+		return ErrSendRequest
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		log.Warn("Image already deleted", slog.String("imageID", imageID))
+		return nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Delete failed", slog.Int("status", resp.StatusCode))
+		return ErrDeleteFailed
+	}
+
+	log.Info("Image delete successful", slog.String("imageID", imageID))
+	return nil
+}
+
+func (c *cloudFlareService) getImageID(imageURL string) string {
+	deliveryURL := strings.TrimSuffix(config.Env.CloudFlareImageDeliveryUrl, "/")
+	if deliveryURL == "" || !strings.HasPrefix(imageURL, deliveryURL+"/") {
+		return ""
+	}
+
+	parts := strings.Split(strings.TrimPrefix(imageURL, deliveryURL+"/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+
+	return parts[len(parts)-2]
+}
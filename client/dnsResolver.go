@@ -0,0 +1,110 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/samber/do"
+)
+
+var ErrDNSLookupFailed = errors.New("failed to lookup dns records")
+
+type DNSResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+type netDNSResolver struct {
+	i        *do.Injector
+	resolver *net.Resolver
+}
+
+// localDNSResolver stands in for real DNS in development and tests. Records
+// are read from a file with one "name value" pair per line, so a hostname can
+// be verified by editing the file instead of publishing a record.
+type localDNSResolver struct {
+	i    *do.Injector
+	path string
+}
+
+func NewDNSResolver(i *do.Injector) (DNSResolver, error) {
+	if config.Env.DNSRecordsPath != "" {
+		return &localDNSResolver{
+			i:    i,
+			path: config.Env.DNSRecordsPath,
+		}, nil
+	}
+
+	return &netDNSResolver{
+		i:        i,
+		resolver: net.DefaultResolver,
+	}, nil
+}
+
+func (n *netDNSResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	log := slog.With(
+		slog.String("client", "dnsResolver"),
+		slog.String("func", "LookupTXT"),
+	)
+
+	records, err := n.resolver.LookupTXT(ctx, name)
+	if err != nil {
+		var dnsError *net.DNSError
+		if errors.As(err, &dnsError) && dnsError.IsNotFound {
+			return nil, nil
+		}
+
+		log.Error("Failed to lookup txt records", slog.String("name", name), slog.String("error", err.Error()))
+		return nil, ErrDNSLookupFailed
+	}
+
+	return records, nil
+}
+
+func (l *localDNSResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	log := slog.With(
+		slog.String("client", "dnsResolver"),
+		slog.String("func", "LookupTXT"),
+	)
+
+	file, err := os.Open(l.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		log.Error("Failed to open local dns records", slog.String("error", err.Error()))
+		return nil, ErrDNSLookupFailed
+	}
+	defer file.Close()
+
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+
+	var records []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		recordName, value, found := strings.Cut(line, " ")
+		if !found || strings.TrimSuffix(strings.ToLower(recordName), ".") != name {
+			continue
+		}
+
+		records = append(records, strings.Trim(strings.TrimSpace(value), `"`))
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Error("Failed to read local dns records", slog.String("error", err.Error()))
+		return nil, ErrDNSLookupFailed
+	}
+
+	return records, nil
+}
@@ -0,0 +1,329 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/golang-jwt/jwt"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/samber/do"
+)
+
+var (
+	ErrOIDCNotConfigured    = errors.New("oidc provider not configured")
+	ErrOIDCDiscoveryFailed  = errors.New("failed to load oidc discovery document")
+	ErrOIDCJWKSFailed       = errors.New("failed to load oidc jwks")
+	ErrOIDCExchangeFailed   = errors.New("failed to exchange authorization code")
+	ErrOIDCIDTokenMissing   = errors.New("id_token missing from token response")
+	ErrOIDCIDTokenInvalid   = errors.New("invalid id_token")
+	ErrOIDCKeyNotFound      = errors.New("signing key not found in jwks")
+	ErrOIDCUnexpectedMethod = errors.New("unexpected id_token signing method")
+)
+
+// oidcJWKSRefreshInterval bounds how often an unknown kid can make us fetch
+// the provider JWKS again, so forged tokens cannot be used to flood it.
+const oidcJWKSRefreshInterval = time.Minute
+
+type OIDCClient interface {
+	AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code string, codeVerifier string) (*OIDCTokenResponse, error)
+	VerifyIDToken(ctx context.Context, rawIDToken string, nonce string) (*OIDCIDTokenClaims, error)
+}
+
+type OIDCDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type OIDCTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+type OIDCIDTokenClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Picture       string
+}
+
+type oidcClient struct {
+	i               *do.Injector
+	httpClient      *http.Client
+	mutex           sync.Mutex
+	discovery       *OIDCDiscovery
+	keys            map[string]interface{}
+	keysRefreshedAt time.Time
+}
+
+func NewOIDCClient(i *do.Injector) (OIDCClient, error) {
+	return &oidcClient{
+		i:          i,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		keys:       map[string]interface{}{},
+	}, nil
+}
+
+func (o *oidcClient) AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	discovery, err := o.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", config.Env.OIDCClientID)
+	params.Set("redirect_uri", config.Env.OIDCRedirectURL)
+	params.Set("scope", "openid email profile")
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+func (o *oidcClient) Exchange(ctx context.Context, code string, codeVerifier string) (*OIDCTokenResponse, error) {
+	log := slog.With(
+		slog.String("client", "oidc"),
+		slog.String("func", "Exchange"),
+	)
+
+	log.Info("Initializing authorization code exchange process")
+
+	discovery, err := o.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", config.Env.OIDCRedirectURL)
+	form.Set("client_id", config.Env.OIDCClientID)
+	form.Set("code_verifier", codeVerifier)
+	if config.Env.OIDCClientSecret != "" {
+		form.Set("client_secret", config.Env.OIDCClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		log.Error("Failed to create request", slog.String("error", err.Error()))
+		return nil, ErrCreateRequest
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		log.Error("Failed to send request", slog.String("error", err.Error()))
+		return nil, ErrSendRequest
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Error("Failed to read token response", slog.String("error", err.Error()))
+		return nil, ErrReadResponse
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error("Token endpoint rejected the code", slog.Int("status", resp.StatusCode))
+		return nil, ErrOIDCExchangeFailed
+	}
+
+	var tokenResponse OIDCTokenResponse
+	if err := jsoniter.Unmarshal(respBody, &tokenResponse); err != nil {
+		log.Error("Failed to decode token response", slog.String("error", err.Error()))
+		return nil, ErrDecodeJSON
+	}
+
+	if tokenResponse.IDToken == "" {
+		log.Error("Token response has no id_token")
+		return nil, ErrOIDCIDTokenMissing
+	}
+
+	log.Info("Authorization code exchanged successfully")
+	return &tokenResponse, nil
+}
+
+func (o *oidcClient) VerifyIDToken(ctx context.Context, rawIDToken string, nonce string) (*OIDCIDTokenClaims, error) {
+	log := slog.With(
+		slog.String("client", "oidc"),
+		slog.String("func", "VerifyIDToken"),
+	)
+
+	log.Info("Initializing id_token verification process")
+
+	discovery, err := o.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := jwt.Parse(rawIDToken, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		default:
+			return nil, ErrOIDCUnexpectedMethod
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		return o.getKey(ctx, discovery.JWKSURI, kid)
+	})
+	if err != nil || !token.Valid {
+		log.Warn("id_token signature or lifetime is invalid")
+		return nil, ErrOIDCIDTokenInvalid
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrOIDCIDTokenInvalid
+	}
+
+	if !claims.VerifyIssuer(discovery.Issuer, true) || !claims.VerifyAudience(config.Env.OIDCClientID, true) {
+		log.Warn("id_token issuer or audience mismatch")
+		return nil, ErrOIDCIDTokenInvalid
+	}
+
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce == "" || tokenNonce != nonce {
+		log.Warn("id_token nonce mismatch")
+		return nil, ErrOIDCIDTokenInvalid
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		log.Warn("id_token has no subject")
+		return nil, ErrOIDCIDTokenInvalid
+	}
+
+	idTokenClaims := &OIDCIDTokenClaims{Subject: subject}
+	idTokenClaims.Email, _ = claims["email"].(string)
+	idTokenClaims.Name, _ = claims["name"].(string)
+	idTokenClaims.Picture, _ = claims["picture"].(string)
+
+	switch emailVerified := claims["email_verified"].(type) {
+	case bool:
+		idTokenClaims.EmailVerified = emailVerified
+	case string:
+		idTokenClaims.EmailVerified = emailVerified == "true"
+	}
+
+	log.Info("id_token verified successfully")
+	return idTokenClaims, nil
+}
+
+func (o *oidcClient) getDiscovery(ctx context.Context) (*OIDCDiscovery, error) {
+	if config.Env.OIDCIssuerURL == "" || config.Env.OIDCClientID == "" {
+		return nil, ErrOIDCNotConfigured
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.discovery != nil {
+		return o.discovery, nil
+	}
+
+	discoveryURL := strings.TrimSuffix(config.Env.OIDCIssuerURL, "/") + "/.well-known/openid-configuration"
+
+	var discovery OIDCDiscovery
+	if err := o.getJSON(ctx, discoveryURL, &discovery); err != nil {
+		slog.Error("Failed to load oidc discovery document", slog.String("error", err.Error()))
+		return nil, ErrOIDCDiscoveryFailed
+	}
+
+	if discovery.Issuer == "" || discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, ErrOIDCDiscoveryFailed
+	}
+
+	o.discovery = &discovery
+	return o.discovery, nil
+}
+
+func (o *oidcClient) getKey(ctx context.Context, jwksURI string, kid string) (interface{}, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if key, ok := o.keys[kid]; ok {
+		return key, nil
+	}
+
+	if time.Since(o.keysRefreshedAt) < oidcJWKSRefreshInterval {
+		slog.Warn("Unknown jwk kid, skipping jwks refresh", slog.String("kid", kid))
+		return nil, ErrOIDCKeyNotFound
+	}
+
+	var keySet secure.JSONWebKeySet
+	if err := o.getJSON(ctx, jwksURI, &keySet); err != nil {
+		slog.Error("Failed to load oidc jwks", slog.String("error", err.Error()))
+		return nil, ErrOIDCJWKSFailed
+	}
+
+	keys := map[string]interface{}{}
+	for _, jwk := range keySet.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		publicKey, err := secure.ParseJSONWebKey(jwk)
+		if err != nil {
+			slog.Warn("Skipping unsupported jwk", slog.String("kid", jwk.Kid), slog.String("error", err.Error()))
+			continue
+		}
+		keys[jwk.Kid] = publicKey
+	}
+	o.keys = keys
+	o.keysRefreshedAt = time.Now()
+
+	key, ok := o.keys[kid]
+	if !ok {
+		return nil, ErrOIDCKeyNotFound
+	}
+
+	return key, nil
+}
+
+func (o *oidcClient) getJSON(ctx context.Context, url string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return jsoniter.Unmarshal(body, target)
+}
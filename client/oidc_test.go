@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/client/oidctest"
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/golang-jwt/jwt"
+)
+
+func newTestOIDCClient(t *testing.T) (*oidcClient, *oidctest.Server) {
+	t.Helper()
+
+	server, err := oidctest.NewServer("test-client")
+	if err != nil {
+		t.Fatalf("start fake idp: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	env := config.Env
+	t.Cleanup(func() { config.Env = env })
+	config.Env.OIDCIssuerURL = server.URL
+	config.Env.OIDCClientID = server.ClientID
+	config.Env.OIDCRedirectURL = "https://app.example.com/oidc/callback"
+
+	return &oidcClient{
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		keys:       map[string]interface{}{},
+	}, server
+}
+
+func TestOIDCClientVerifyIDToken(t *testing.T) {
+	claims := func(mutate func(jwt.MapClaims)) func(server *oidctest.Server) jwt.MapClaims {
+		return func(server *oidctest.Server) jwt.MapClaims {
+			claims := jwt.MapClaims{
+				"iss":   server.URL,
+				"aud":   server.ClientID,
+				"sub":   "subject",
+				"nonce": "nonce",
+				"exp":   time.Now().Add(time.Minute).Unix(),
+			}
+			if mutate != nil {
+				mutate(claims)
+			}
+			return claims
+		}
+	}
+
+	tests := []struct {
+		name    string
+		claims  func(server *oidctest.Server) jwt.MapClaims
+		kid     string
+		wantErr error
+	}{
+		{name: "valid", claims: claims(nil), kid: oidctest.KeyID},
+		{name: "wrong issuer", claims: claims(func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }), kid: oidctest.KeyID, wantErr: ErrOIDCIDTokenInvalid},
+		{name: "wrong audience", claims: claims(func(c jwt.MapClaims) { c["aud"] = "other-client" }), kid: oidctest.KeyID, wantErr: ErrOIDCIDTokenInvalid},
+		{name: "wrong nonce", claims: claims(func(c jwt.MapClaims) { c["nonce"] = "other" }), kid: oidctest.KeyID, wantErr: ErrOIDCIDTokenInvalid},
+		{name: "missing subject", claims: claims(func(c jwt.MapClaims) { delete(c, "sub") }), kid: oidctest.KeyID, wantErr: ErrOIDCIDTokenInvalid},
+		{name: "expired", claims: claims(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }), kid: oidctest.KeyID, wantErr: ErrOIDCIDTokenInvalid},
+		{name: "unknown key", claims: claims(nil), kid: "unknown", wantErr: ErrOIDCIDTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oidcClient, server := newTestOIDCClient(t)
+
+			idToken, err := server.SignIDToken(tt.claims(server), tt.kid)
+			if err != nil {
+				t.Fatalf("sign id_token: %v", err)
+			}
+
+			idTokenClaims, err := oidcClient.VerifyIDToken(context.Background(), idToken, "nonce")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyIDToken() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && idTokenClaims.Subject != "subject" {
+				t.Errorf("Subject = %q, want %q", idTokenClaims.Subject, "subject")
+			}
+		})
+	}
+}
+
+func TestOIDCClientLimitsJWKSRefresh(t *testing.T) {
+	oidcClient, server := newTestOIDCClient(t)
+	ctx := context.Background()
+
+	discovery, err := oidcClient.getDiscovery(ctx)
+	if err != nil {
+		t.Fatalf("getDiscovery() error = %v", err)
+	}
+
+	if _, err := oidcClient.getKey(ctx, discovery.JWKSURI, oidctest.KeyID); err != nil {
+		t.Fatalf("getKey() error = %v", err)
+	}
+
+	for range 5 {
+		if _, err := oidcClient.getKey(ctx, discovery.JWKSURI, "unknown"); !errors.Is(err, ErrOIDCKeyNotFound) {
+			t.Fatalf("getKey() error = %v, want %v", err, ErrOIDCKeyNotFound)
+		}
+	}
+
+	if got := server.JWKSRequests(); got != 1 {
+		t.Fatalf("JWKS requests = %d, want 1", got)
+	}
+
+	oidcClient.keysRefreshedAt = time.Now().Add(-oidcJWKSRefreshInterval)
+	if _, err := oidcClient.getKey(ctx, discovery.JWKSURI, "unknown"); !errors.Is(err, ErrOIDCKeyNotFound) {
+		t.Fatalf("getKey() error = %v, want %v", err, ErrOIDCKeyNotFound)
+	}
+
+	if got := server.JWKSRequests(); got != 2 {
+		t.Errorf("JWKS requests after refresh interval = %d, want 2", got)
+	}
+}
@@ -0,0 +1,249 @@
+// Package oidctest provides an in-process OpenID Connect provider so the
+// sign in flow can be exercised end to end without a real identity provider.
+package oidctest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/golang-jwt/jwt"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// KeyID is the kid the provider signs id_tokens with.
+const KeyID = "oidctest"
+
+var ErrAuthorizeRejected = errors.New("authorization request rejected")
+
+// Server serves discovery, authorization, token and JWKS endpoints. The
+// token endpoint enforces PKCE and single use codes like a real provider.
+// Exported fields shape the id_token and must be set before the flow starts.
+type Server struct {
+	*httptest.Server
+
+	ClientID      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+
+	// Issuer, Audience and Nonce override the matching id_token claims when
+	// set, to simulate a misbehaving or malicious provider.
+	Issuer   string
+	Audience string
+	Nonce    string
+
+	signingKey *ecdsa.PrivateKey
+
+	mutex        sync.Mutex
+	grants       map[string]grant
+	jwksRequests int
+}
+
+type grant struct {
+	clientID      string
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+}
+
+func NewServer(clientID string) (*Server, error) {
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		ClientID:      clientID,
+		Subject:       "oidctest-subject",
+		Email:         "oidc.user@example.com",
+		EmailVerified: true,
+		Name:          "OIDC User",
+		signingKey:    signingKey,
+		grants:        map[string]grant{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.handleDiscovery)
+	mux.HandleFunc("/authorize", s.handleAuthorize)
+	mux.HandleFunc("/token", s.handleToken)
+	mux.HandleFunc("/jwks", s.handleJWKS)
+	s.Server = httptest.NewServer(mux)
+
+	return s, nil
+}
+
+// Authorize follows an authorization URL as a user who approves the request
+// and returns the code and state the provider redirects back with.
+func (s *Server) Authorize(authorizationURL string) (string, string, error) {
+	httpClient := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := httpClient.Get(authorizationURL)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		return "", "", ErrAuthorizeRejected
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return "", "", err
+	}
+
+	return location.Query().Get("code"), location.Query().Get("state"), nil
+}
+
+// SignIDToken signs arbitrary claims with the provider key, or with a fresh
+// key under kid when kid is not the one published in the JWKS.
+func (s *Server) SignIDToken(claims jwt.MapClaims, kid string) (string, error) {
+	signingKey := s.signingKey
+	if kid != KeyID {
+		var err error
+		signingKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = kid
+	return token.SignedString(signingKey)
+}
+
+// JWKSRequests reports how many times the JWKS was downloaded.
+func (s *Server) JWKSRequests() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.jwksRequests
+}
+
+func (s *Server) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 s.URL,
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"jwks_uri":               s.URL + "/jwks",
+	})
+}
+
+func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != s.ClientID || query.Get("code_challenge_method") != "S256" ||
+		query.Get("code_challenge") == "" || query.Get("state") == "" || query.Get("redirect_uri") == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	code, err := secure.GenerateRandomToken(16)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	s.mutex.Lock()
+	s.grants[code] = grant{
+		clientID:      query.Get("client_id"),
+		redirectURI:   query.Get("redirect_uri"),
+		codeChallenge: query.Get("code_challenge"),
+		nonce:         query.Get("nonce"),
+	}
+	s.mutex.Unlock()
+
+	redirect := url.Values{}
+	redirect.Set("code", code)
+	redirect.Set("state", query.Get("state"))
+	http.Redirect(w, r, query.Get("redirect_uri")+"?"+redirect.Encode(), http.StatusFound)
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	s.mutex.Lock()
+	grant, ok := s.grants[r.PostForm.Get("code")]
+	delete(s.grants, r.PostForm.Get("code"))
+	s.mutex.Unlock()
+
+	hash := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || grant.clientID != r.PostForm.Get("client_id") || grant.redirectURI != r.PostForm.Get("redirect_uri") ||
+		base64.RawURLEncoding.EncodeToString(hash[:]) != grant.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":            s.URL,
+		"aud":            s.ClientID,
+		"sub":            s.Subject,
+		"nonce":          grant.nonce,
+		"email":          s.Email,
+		"email_verified": s.EmailVerified,
+		"name":           s.Name,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(5 * time.Minute).Unix(),
+	}
+	if s.Issuer != "" {
+		claims["iss"] = s.Issuer
+	}
+	if s.Audience != "" {
+		claims["aud"] = s.Audience
+	}
+	if s.Nonce != "" {
+		claims["nonce"] = s.Nonce
+	}
+
+	idToken, err := s.SignIDToken(claims, KeyID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": "oidctest-access-token",
+		"id_token":     idToken,
+		"token_type":   "Bearer",
+		"expires_in":   300,
+	})
+}
+
+func (s *Server) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	s.jwksRequests++
+	s.mutex.Unlock()
+
+	writeJSON(w, http.StatusOK, secure.JSONWebKeySet{
+		Keys: []secure.JSONWebKey{secure.NewECJSONWebKey(KeyID, &s.signingKey.PublicKey)},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	data, err := jsoniter.Marshal(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
@@ -2,12 +2,15 @@ package config
 
 import (
 	"crypto/ecdsa"
-	"crypto/x509"
-	"encoding/pem"
-	"errors"
+	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
 
+	"github.com/GSVillas/e-commercer-api/secure"
 	"github.com/Netflix/go-env"
 	"github.com/joho/godotenv"
 )
@@ -21,6 +24,8 @@ type Environment struct {
 	RedisDB                    int    `env:"REDIS_DB"`
 	APIPort                    string `env:"API_PORT"`
 	TokenExp                   int    `env:"TOKEN_EXP"`
+	AccessTokenExp             int    `env:"ACCESS_TOKEN_EXP"`
+	AdminKey                   string `env:"ADMIN_KEY"`
 	ResendKey                  string `env:"RESEND_KEY"`
 	URLFront                   string `env:"FRONT_URL"`
 	OTPEmailSize               int8   `env:"OTP_EMAIL_SIZE"`
@@ -28,8 +33,39 @@ type Environment struct {
 	CloudFlareAccountAPI       string `env:"CLOUD_FLARE_ACCOUNT_API"`
 	CloudFlareImageDeliveryUrl string `env:"CLOUD_FLARE_IMAGE_DELIVERY_URL"`
 	CloudFlareApiKey           string `env:"CLOUD_FLARE_API_KEY"`
-	PrivateKey                 *ecdsa.PrivateKey
-	PublicKey                  *ecdsa.PublicKey
+	OIDCProvider               string `env:"OIDC_PROVIDER"`
+	OIDCIssuerURL              string `env:"OIDC_ISSUER_URL"`
+	OIDCClientID               string `env:"OIDC_CLIENT_ID"`
+	OIDCClientSecret           string `env:"OIDC_CLIENT_SECRET"`
+	OIDCRedirectURL            string `env:"OIDC_REDIRECT_URL"`
+	JWTKeysDir                 string `env:"JWT_KEYS_DIR"`
+	LockoutMaxAttempts         int    `env:"LOCKOUT_MAX_ATTEMPTS"`
+	LockoutIPMaxAttempts       int    `env:"LOCKOUT_IP_MAX_ATTEMPTS"`
+	LockoutDuration            int    `env:"LOCKOUT_DURATION"`
+	RequestRateLimit           int    `env:"REQUEST_RATE_LIMIT"`
+	RequestRateIPLimit         int    `env:"REQUEST_RATE_IP_LIMIT"`
+	RequestRateWindow          int    `env:"REQUEST_RATE_WINDOW"`
+	AccountPurgeGraceDays      int    `env:"ACCOUNT_PURGE_GRACE_DAYS"`
+	StorePurgeRetentionDays    int    `env:"STORE_PURGE_RETENTION_DAYS"`
+	StoreInviteExp             int    `env:"STORE_INVITE_EXP"`
+	MagicLinkExp               int    `env:"MAGIC_LINK_EXP"`
+	PasswordMinLength          int    `env:"PASSWORD_MIN_LENGTH"`
+	PasswordMaxLength          int    `env:"PASSWORD_MAX_LENGTH"`
+	PasswordRequireUpper       bool   `env:"PASSWORD_REQUIRE_UPPER"`
+	PasswordRequireLower       bool   `env:"PASSWORD_REQUIRE_LOWER"`
+	PasswordRequireDigit       bool   `env:"PASSWORD_REQUIRE_DIGIT"`
+	PasswordRequireSymbol      bool   `env:"PASSWORD_REQUIRE_SYMBOL"`
+	PasswordMinScore           int    `env:"PASSWORD_MIN_SCORE"`
+	PasswordBreachedListPath   string `env:"PASSWORD_BREACHED_LIST_PATH"`
+	BcryptCost                 int    `env:"BCRYPT_COST"`
+	DNSRecordsPath             string `env:"DNS_RECORDS_PATH"`
+	PublicCacheTTL             int    `env:"PUBLIC_CACHE_TTL"`
+	PublicCORSOrigins          string `env:"PUBLIC_CORS_ORIGINS"`
+	TrustedProxies             string `env:"TRUSTED_PROXIES"`
+	TwoFactorEncryptionKey     string `env:"TWO_FACTOR_ENCRYPTION_KEY"`
+	KeyRing                    *secure.KeyRing
+	PasswordPolicy             *secure.PasswordPolicy
+	TwoFactorSecretBox         *secure.SecretBox
 }
 
 func LoadEnvironments() {
@@ -43,15 +79,26 @@ func LoadEnvironments() {
 		panic(err)
 	}
 
-	Env.PrivateKey, err = loadPrivateKey()
+	Env.KeyRing, err = loadKeyRing()
 	if err != nil {
 		panic(err)
 	}
 
-	Env.PublicKey, err = loadPublicKey()
+	Env.PasswordPolicy, err = loadPasswordPolicy()
 	if err != nil {
 		panic(err)
 	}
+
+	Env.TwoFactorSecretBox, err = secure.NewSecretBox(Env.TwoFactorEncryptionKey)
+	if err != nil {
+		panic(fmt.Errorf("TWO_FACTOR_ENCRYPTION_KEY: %w", err))
+	}
+
+	if Env.BcryptCost > 0 {
+		if err := secure.SetHashCost(Env.BcryptCost); err != nil {
+			panic(err)
+		}
+	}
 }
 
 func ConfigureLogger() {
@@ -61,45 +108,143 @@ func ConfigureLogger() {
 	slog.SetDefault(handler)
 }
 
-func loadPrivateKey() (*ecdsa.PrivateKey, error) {
-	keyData, err := os.ReadFile("ec_private_key.pem")
+func ReloadKeyRing() error {
+	keyRing, err := loadKeyRing()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	block, _ := pem.Decode(keyData)
-	if block == nil || block.Type != "EC PRIVATE KEY" {
-		return nil, errors.New("failed to decode PEM block containing private key")
+	Env.KeyRing.Replace(keyRing)
+	return nil
+}
+
+func WatchKeyRing() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		for range signals {
+			if err := ReloadKeyRing(); err != nil {
+				slog.Error("Failed to reload key ring", slog.String("error", err.Error()))
+				continue
+			}
+
+			activeKID, _ := Env.KeyRing.SigningKey()
+			slog.Info("Key ring reloaded", slog.String("activeKid", activeKID))
+		}
+	}()
+}
+
+func loadPasswordPolicy() (*secure.PasswordPolicy, error) {
+	passwordPolicy := secure.DefaultPasswordPolicy()
+	passwordPolicy.RequireUpper = Env.PasswordRequireUpper
+	passwordPolicy.RequireLower = Env.PasswordRequireLower
+	passwordPolicy.RequireDigit = Env.PasswordRequireDigit
+	passwordPolicy.RequireSymbol = Env.PasswordRequireSymbol
+
+	if Env.PasswordMinLength > 0 {
+		passwordPolicy.MinLength = Env.PasswordMinLength
+	}
+
+	if Env.PasswordMaxLength > 0 {
+		passwordPolicy.MaxLength = Env.PasswordMaxLength
+	}
+
+	if Env.PasswordMinScore > 0 {
+		passwordPolicy.MinScore = Env.PasswordMinScore
+	}
+
+	if Env.PasswordBreachedListPath != "" {
+		if err := passwordPolicy.LoadBreachedPasswords(Env.PasswordBreachedListPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return passwordPolicy, nil
+}
+
+func loadKeyRing() (*secure.KeyRing, error) {
+	if Env.JWTKeysDir == "" {
+		return loadDefaultKeyRing()
 	}
 
-	privateKey, err := x509.ParseECPrivateKey(block.Bytes)
+	activeKIDData, err := os.ReadFile(filepath.Join(Env.JWTKeysDir, "active"))
 	if err != nil {
 		return nil, err
 	}
+	activeKID := strings.TrimSpace(string(activeKIDData))
 
-	return privateKey, nil
+	entries, err := os.ReadDir(Env.JWTKeysDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var signingKey *ecdsa.PrivateKey
+	verificationKeys := map[string]*ecdsa.PublicKey{}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".pem") {
+			continue
+		}
+
+		kid := strings.TrimSuffix(entry.Name(), ".pem")
+
+		keyData, err := os.ReadFile(filepath.Join(Env.JWTKeysDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		if privateKey, err := secure.ParseECPrivateKeyPEM(keyData); err == nil {
+			if kid == activeKID {
+				signingKey = privateKey
+			}
+			verificationKeys[kid] = &privateKey.PublicKey
+			continue
+		}
+
+		publicKey, err := secure.ParseECPublicKeyPEM(keyData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key %s: %w", entry.Name(), err)
+		}
+		verificationKeys[kid] = publicKey
+	}
+
+	if signingKey == nil {
+		return nil, fmt.Errorf("%w: %s", secure.ErrSigningKeyNotFound, activeKID)
+	}
+
+	return secure.NewKeyRing(activeKID, signingKey, verificationKeys)
 }
 
-func loadPublicKey() (*ecdsa.PublicKey, error) {
-	keyData, err := os.ReadFile("ec_public_key.pem")
+func loadDefaultKeyRing() (*secure.KeyRing, error) {
+	privateKey, err := loadPrivateKey()
 	if err != nil {
 		return nil, err
 	}
 
-	block, _ := pem.Decode(keyData)
-	if block == nil || block.Type != "PUBLIC KEY" {
-		return nil, errors.New("failed to decode PEM block containing public key")
+	publicKey, err := loadPublicKey()
+	if err != nil {
+		return nil, err
 	}
 
-	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	kid := secure.ECThumbprint(publicKey)
+	return secure.NewKeyRing(kid, privateKey, map[string]*ecdsa.PublicKey{kid: publicKey})
+}
+
+func loadPrivateKey() (*ecdsa.PrivateKey, error) {
+	keyData, err := os.ReadFile("ec_private_key.pem")
 	if err != nil {
 		return nil, err
 	}
 
-	ecdsaPubKey, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return nil, errors.New("not ECDSA public key")
+	return secure.ParseECPrivateKeyPEM(keyData)
+}
+
+func loadPublicKey() (*ecdsa.PublicKey, error) {
+	keyData, err := os.ReadFile("ec_public_key.pem")
+	if err != nil {
+		return nil, err
 	}
 
-	return ecdsaPubKey, nil
+	return secure.ParseECPublicKeyPEM(keyData)
 }
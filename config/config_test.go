@@ -0,0 +1,86 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GSVillas/e-commercer-api/secure"
+)
+
+func writeTestKey(t *testing.T, dir string, kid string) *ecdsa.PrivateKey {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	keyData, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyData})
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), keyPEM, 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return privateKey
+}
+
+func writeActiveKID(t *testing.T, dir string, kid string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, "active"), []byte(kid+"\n"), 0o600); err != nil {
+		t.Fatalf("write active kid: %v", err)
+	}
+}
+
+func TestReloadKeyRing(t *testing.T) {
+	env := Env
+	t.Cleanup(func() { Env = env })
+
+	dir := t.TempDir()
+	Env.JWTKeysDir = dir
+
+	oldKey := writeTestKey(t, dir, "2024-01")
+	writeActiveKID(t, dir, "2024-01")
+
+	keyRing, err := loadKeyRing()
+	if err != nil {
+		t.Fatalf("loadKeyRing() error = %v", err)
+	}
+	Env.KeyRing = keyRing
+
+	newKey := writeTestKey(t, dir, "2024-02")
+	writeActiveKID(t, dir, "2024-02")
+
+	if err := ReloadKeyRing(); err != nil {
+		t.Fatalf("ReloadKeyRing() error = %v", err)
+	}
+
+	activeKID, signingKey := keyRing.SigningKey()
+	if activeKID != "2024-02" || !signingKey.Equal(newKey) {
+		t.Fatalf("SigningKey() after reload = %q, want 2024-02", activeKID)
+	}
+
+	publicKey, err := keyRing.VerificationKey("2024-01")
+	if err != nil || !publicKey.Equal(&oldKey.PublicKey) {
+		t.Errorf("VerificationKey(2024-01) after reload error = %v, want the previous key", err)
+	}
+
+	writeActiveKID(t, dir, "missing")
+	if err := ReloadKeyRing(); !errors.Is(err, secure.ErrSigningKeyNotFound) {
+		t.Fatalf("ReloadKeyRing() with a missing active key error = %v, want %v", err, secure.ErrSigningKeyNotFound)
+	}
+
+	if activeKID, _ := keyRing.SigningKey(); activeKID != "2024-02" {
+		t.Errorf("failed reload replaced the key ring, active kid = %q", activeKID)
+	}
+}
@@ -2,12 +2,14 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"time"
 
 	"github.com/GSVillas/e-commercer-api/config"
 	"github.com/GSVillas/e-commercer-api/config/database"
 	"github.com/GSVillas/e-commercer-api/domain"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -20,9 +22,112 @@ func main() {
 		log.Fatal("Fail to connect to mysql: ", err)
 	}
 
-	if err := db.AutoMigrate(&domain.User{}, &domain.Store{}, &domain.Billboard{}); err != nil {
+	if err := db.AutoMigrate(&domain.User{}, &domain.Store{}, &domain.Billboard{}, &domain.RecoveryCode{}, &domain.UserIdentity{}, &domain.APIKey{}, &domain.StoreMember{}, &domain.StoreInvite{}, &domain.AuditEvent{}, &domain.LoginHistory{}, &domain.StoreSlugRedirect{}, &domain.StoreDomain{}); err != nil {
 		log.Fatal("Fail to migrate: ", err)
 	}
 
+	if err := backfillUsernames(db); err != nil {
+		log.Fatal("Fail to backfill usernames: ", err)
+	}
+
+	if err := backfillStoreSlugs(db); err != nil {
+		log.Fatal("Fail to backfill store slugs: ", err)
+	}
+
+	if err := encryptTwoFactorSecrets(db); err != nil {
+		log.Fatal("Fail to encrypt two factor secrets: ", err)
+	}
+
 	log.Println("Migration executed successfully")
 }
+
+// backfillUsernames replaces the email based usernames created before handles
+// existed with a generated one, so emails are no longer exposed publicly.
+func backfillUsernames(db *gorm.DB) error {
+	var users []*domain.User
+	if err := db.Unscoped().Select("id", "name", "username").Where("username LIKE ?", "%@%").Find(&users).Error; err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		username, err := generateUsername(db, user.Name)
+		if err != nil {
+			return fmt.Errorf("user %s: %w", user.ID.String(), err)
+		}
+
+		if err := db.Unscoped().Model(&domain.User{}).Where("id = ?", user.ID.String()).Update("username", username).Error; err != nil {
+			return fmt.Errorf("user %s: %w", user.ID.String(), err)
+		}
+	}
+
+	log.Printf("Usernames backfilled for %d users", len(users))
+	return nil
+}
+
+func generateUsername(db *gorm.DB, name string) (string, error) {
+	return domain.GenerateUsername(name, func(username string) (bool, error) {
+		var count int64
+		err := db.Unscoped().Model(&domain.User{}).Where("username = ?", username).Count(&count).Error
+		return count > 0, err
+	})
+}
+
+// backfillStoreSlugs gives stores created before slugs existed one derived
+// from their name, including deleted ones so they keep it if restored.
+func backfillStoreSlugs(db *gorm.DB) error {
+	var stores []*domain.Store
+	if err := db.Unscoped().Select("id", "name").Where("slug IS NULL OR slug = ''").Find(&stores).Error; err != nil {
+		return err
+	}
+
+	for _, store := range stores {
+		slug, err := generateStoreSlug(db, store.Name)
+		if err != nil {
+			return fmt.Errorf("store %s: %w", store.ID.String(), err)
+		}
+
+		if err := db.Unscoped().Model(&domain.Store{}).Where("id = ?", store.ID.String()).Update("slug", slug).Error; err != nil {
+			return fmt.Errorf("store %s: %w", store.ID.String(), err)
+		}
+	}
+
+	log.Printf("Store slugs backfilled for %d stores", len(stores))
+	return nil
+}
+
+func generateStoreSlug(db *gorm.DB, name string) (string, error) {
+	return domain.GenerateStoreSlug(name, func(slug string) (bool, error) {
+		var count int64
+		err := db.Unscoped().Model(&domain.Store{}).Where("slug = ?", slug).Count(&count).Error
+		return count > 0, err
+	})
+}
+
+// encryptTwoFactorSecrets encrypts the TOTP secrets stored in plaintext before
+// they were encrypted at rest. Secrets that already decrypt are left alone.
+func encryptTwoFactorSecrets(db *gorm.DB) error {
+	var users []*domain.User
+	if err := db.Unscoped().Select("id", "twoFactorSecret").Where("twoFactorSecret IS NOT NULL AND twoFactorSecret <> ''").Find(&users).Error; err != nil {
+		return err
+	}
+
+	encrypted := 0
+	for _, user := range users {
+		if _, err := config.Env.TwoFactorSecretBox.Open(user.TwoFactorSecret); err == nil {
+			continue
+		}
+
+		encryptedSecret, err := config.Env.TwoFactorSecretBox.Seal(user.TwoFactorSecret)
+		if err != nil {
+			return fmt.Errorf("user %s: %w", user.ID.String(), err)
+		}
+
+		if err := db.Unscoped().Model(&domain.User{}).Where("id = ?", user.ID.String()).Update("twoFactorSecret", encryptedSecret).Error; err != nil {
+			return fmt.Errorf("user %s: %w", user.ID.String(), err)
+		}
+		encrypted++
+	}
+
+	log.Printf("Two factor secrets encrypted for %d users", encrypted)
+	return nil
+}
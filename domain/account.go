@@ -0,0 +1,179 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrReauthenticationRequired = errors.New("password or otp required to confirm this action")
+)
+
+type DeleteAccountPayload struct {
+	Password string `json:"password" validate:"required_without=OTP"`
+	OTP      string `json:"otp" validate:"required_without=Password,omitempty,numeric,min=6,max=6"`
+}
+
+type AccountData struct {
+	User         User
+	Identities   []*UserIdentity
+	Stores       []*Store
+	APIKeys      []*APIKey
+	LoginHistory []*LoginHistory
+	Memberships  []*StoreMember
+	AuditEvents  []*AuditEvent
+}
+
+type AccountProfileExport struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	Username         string    `json:"username"`
+	Email            string    `json:"email"`
+	EmailConfirmed   bool      `json:"emailConfirmed"`
+	AvatarURL        string    `json:"avatarUrl"`
+	TwoFactorEnabled bool      `json:"twoFactorEnabled"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+type AccountIdentityExport struct {
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type AccountStoreExport struct {
+	StoreResponse
+	Billboards []*BillboardRespose `json:"billboards"`
+}
+
+type AccountMembershipExport struct {
+	StoreID   string    `json:"storeId"`
+	StoreName string    `json:"storeName"`
+	Role      StoreRole `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type AccountExport struct {
+	ExportedAt   time.Time                  `json:"exportedAt"`
+	Profile      AccountProfileExport       `json:"profile"`
+	Identities   []*AccountIdentityExport   `json:"identities"`
+	Stores       []*AccountStoreExport      `json:"stores"`
+	APIKeys      []*APIKeyResponse          `json:"apiKeys"`
+	Sessions     []*SessionInfoResponse     `json:"sessions"`
+	LoginHistory []*LoginHistoryResponse    `json:"loginHistory"`
+	Memberships  []*AccountMembershipExport `json:"memberships"`
+	AuditEvents  []*AuditEventResponse      `json:"auditEvents"`
+}
+
+type AccountHandler interface {
+	SendDeletionCode(ctx echo.Context) error
+	Delete(ctx echo.Context) error
+	Export(ctx echo.Context) error
+}
+
+type AccountService interface {
+	SendDeletionCode(ctx context.Context) error
+	Delete(ctx context.Context, deleteAccountPayload DeleteAccountPayload) error
+	Export(ctx context.Context) (*AccountExport, error)
+	PurgeDeleted(ctx context.Context) (int, error)
+}
+
+type AccountRepository interface {
+	SoftDelete(ctx context.Context, userID uuid.UUID) error
+	GetData(ctx context.Context, userID uuid.UUID) (*AccountData, error)
+	GetDeletedBefore(ctx context.Context, before time.Time) ([]*User, error)
+	Purge(ctx context.Context, userID uuid.UUID) ([]string, error)
+}
+
+func (d *DeleteAccountPayload) trim() {
+	d.OTP = strings.TrimSpace(d.OTP)
+}
+
+func (d *DeleteAccountPayload) Validate() error {
+	d.trim()
+	validate := validator.New()
+	err := validate.RegisterValidation("numeric", util.IsNumeric)
+	if err != nil {
+		return err
+	}
+
+	return validate.Struct(d)
+}
+
+func (a *AccountData) ToExport(sessions []*SessionInfoResponse) *AccountExport {
+	accountExport := &AccountExport{
+		ExportedAt: time.Now().UTC(),
+		Profile: AccountProfileExport{
+			ID:               a.User.ID.String(),
+			Name:             a.User.Name,
+			Username:         a.User.Username,
+			Email:            a.User.Email,
+			EmailConfirmed:   a.User.EmailConfirmed,
+			AvatarURL:        a.User.AvatarURL,
+			TwoFactorEnabled: a.User.TwoFactorEnabled,
+			CreatedAt:        a.User.CreatedAt,
+			UpdatedAt:        a.User.UpdatedAt,
+		},
+		Identities:   make([]*AccountIdentityExport, 0, len(a.Identities)),
+		Stores:       make([]*AccountStoreExport, 0, len(a.Stores)),
+		APIKeys:      make([]*APIKeyResponse, 0, len(a.APIKeys)),
+		Sessions:     sessions,
+		LoginHistory: make([]*LoginHistoryResponse, 0, len(a.LoginHistory)),
+		Memberships:  make([]*AccountMembershipExport, 0, len(a.Memberships)),
+		AuditEvents:  make([]*AuditEventResponse, 0, len(a.AuditEvents)),
+	}
+
+	for _, identity := range a.Identities {
+		accountExport.Identities = append(accountExport.Identities, &AccountIdentityExport{
+			Provider:  identity.Provider,
+			Subject:   identity.Subject,
+			Email:     identity.Email,
+			CreatedAt: identity.CreatedAt,
+		})
+	}
+
+	for _, store := range a.Stores {
+		storeExport := &AccountStoreExport{
+			StoreResponse: *store.ToResponse(),
+			Billboards:    make([]*BillboardRespose, 0, len(store.Billboards)),
+		}
+
+		for _, billboard := range store.Billboards {
+			storeExport.Billboards = append(storeExport.Billboards, billboard.ToResponse())
+		}
+
+		accountExport.Stores = append(accountExport.Stores, storeExport)
+	}
+
+	for _, apiKey := range a.APIKeys {
+		accountExport.APIKeys = append(accountExport.APIKeys, apiKey.ToResponse())
+	}
+
+	for _, loginHistory := range a.LoginHistory {
+		accountExport.LoginHistory = append(accountExport.LoginHistory, loginHistory.ToResponse())
+	}
+
+	for _, membership := range a.Memberships {
+		accountExport.Memberships = append(accountExport.Memberships, &AccountMembershipExport{
+			StoreID:   membership.StoreID.String(),
+			StoreName: membership.Store.Name,
+			Role:      membership.Role,
+			CreatedAt: membership.CreatedAt,
+		})
+	}
+
+	for _, auditEvent := range a.AuditEvents {
+		accountExport.AuditEvents = append(accountExport.AuditEvents, auditEvent.ToResponse())
+	}
+
+	return accountExport
+}
@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrCannotModerateSelf   = errors.New("superadmin cannot moderate own account")
+	ErrUserAlreadySuspended = errors.New("user already suspended")
+	ErrUserNotSuspended     = errors.New("user is not suspended")
+	ErrStoreNotDeleted      = errors.New("store is not deleted")
+)
+
+const defaultAdminPageSize = 20
+
+type AdminSearchPayload struct {
+	Search   string `query:"search" validate:"max=100"`
+	Page     int    `query:"page" validate:"omitempty,min=1"`
+	PageSize int    `query:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type AdminStoreSearchPayload struct {
+	AdminSearchPayload
+	Deleted bool `query:"deleted"`
+}
+
+type AdminUserRolePayload struct {
+	Role UserRole `json:"role" validate:"required,oneof=user superadmin"`
+}
+
+type AdminUserResponse struct {
+	ID               string     `json:"id"`
+	Name             string     `json:"name"`
+	Username         string     `json:"username"`
+	Email            string     `json:"email"`
+	EmailConfirmed   bool       `json:"emailConfirmed"`
+	TwoFactorEnabled bool       `json:"twoFactorEnabled"`
+	Role             UserRole   `json:"role"`
+	SuspendedAt      *time.Time `json:"suspendedAt,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt"`
+}
+
+type AdminStoreResponse struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	OwnerID   string     `json:"ownerId"`
+	CreatedAt time.Time  `json:"createdAt"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+type AdminUsersResponse struct {
+	Items    []*AdminUserResponse `json:"items"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"pageSize"`
+	Total    int64                `json:"total"`
+}
+
+type AdminStoresResponse struct {
+	Items    []*AdminStoreResponse `json:"items"`
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"pageSize"`
+	Total    int64                 `json:"total"`
+}
+
+type AdminHandler interface {
+	GetUsers(ctx echo.Context) error
+	GetStores(ctx echo.Context) error
+	SuspendUser(ctx echo.Context) error
+	UnsuspendUser(ctx echo.Context) error
+	ConfirmUserEmail(ctx echo.Context) error
+	UpdateUserRole(ctx echo.Context) error
+	RestoreStore(ctx echo.Context) error
+}
+
+type AdminService interface {
+	GetUsers(ctx context.Context, adminSearchPayload AdminSearchPayload) (*AdminUsersResponse, error)
+	GetStores(ctx context.Context, adminStoreSearchPayload AdminStoreSearchPayload) (*AdminStoresResponse, error)
+	SuspendUser(ctx context.Context, userID uuid.UUID) error
+	UnsuspendUser(ctx context.Context, userID uuid.UUID) error
+	ConfirmUserEmail(ctx context.Context, userID uuid.UUID) error
+	UpdateUserRole(ctx context.Context, userID uuid.UUID, adminUserRolePayload AdminUserRolePayload) error
+	RestoreStore(ctx context.Context, storeID uuid.UUID) (*AdminStoreResponse, error)
+}
+
+type AdminRepository interface {
+	GetUsers(ctx context.Context, search string, offset int, limit int) ([]*User, int64, error)
+	GetStores(ctx context.Context, search string, deleted bool, offset int, limit int) ([]*Store, int64, error)
+	GetStoreWithDeleted(ctx context.Context, storeID uuid.UUID) (*Store, error)
+	UpdateSuspension(ctx context.Context, userID uuid.UUID, suspendedAt *time.Time) error
+	UpdateRole(ctx context.Context, userID uuid.UUID, role UserRole) error
+}
+
+func (a *AdminSearchPayload) trim() {
+	a.Search = strings.TrimSpace(a.Search)
+}
+
+func (a *AdminSearchPayload) Validate() error {
+	a.trim()
+	validate := validator.New()
+	if err := validate.Struct(a); err != nil {
+		return err
+	}
+
+	if a.Page == 0 {
+		a.Page = 1
+	}
+
+	if a.PageSize == 0 {
+		a.PageSize = defaultAdminPageSize
+	}
+
+	return nil
+}
+
+func (a *AdminStoreSearchPayload) Validate() error {
+	return a.AdminSearchPayload.Validate()
+}
+
+func (a *AdminUserRolePayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(a)
+}
+
+func (a *AdminSearchPayload) Offset() int {
+	return (a.Page - 1) * a.PageSize
+}
+
+func (u *User) ToAdminResponse() *AdminUserResponse {
+	return &AdminUserResponse{
+		ID:               u.ID.String(),
+		Name:             u.Name,
+		Username:         u.Username,
+		Email:            u.Email,
+		EmailConfirmed:   u.EmailConfirmed,
+		TwoFactorEnabled: u.TwoFactorEnabled,
+		Role:             u.Role,
+		SuspendedAt:      u.SuspendedAt,
+		CreatedAt:        u.CreatedAt,
+	}
+}
+
+func (s *Store) ToAdminResponse() *AdminStoreResponse {
+	adminStoreResponse := &AdminStoreResponse{
+		ID:        s.ID.String(),
+		Name:      s.Name,
+		OwnerID:   s.UserID.String(),
+		CreatedAt: s.CreatedAt,
+	}
+
+	if s.DeletedAt.Valid {
+		deletedAt := s.DeletedAt.Time
+		adminStoreResponse.DeletedAt = &deletedAt
+	}
+
+	return adminStoreResponse
+}
@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrAPIKeyNotFound     = errors.New("api key not found")
+	ErrAPIKeyInvalid      = errors.New("invalid api key")
+	ErrAPIKeyScopeMissing = errors.New("api key missing required scope")
+	ErrAPIKeyNotAllowed   = errors.New("action not allowed with an api key")
+)
+
+const (
+	ScopeStoresRead      = "stores:read"
+	ScopeStoresWrite     = "stores:write"
+	ScopeBillboardsRead  = "billboards:read"
+	ScopeBillboardsWrite = "billboards:write"
+	ScopeCatalogRead     = "catalog:read"
+	ScopeCatalogWrite    = "catalog:write"
+)
+
+type APIKey struct {
+	ID         uuid.UUID  `gorm:"type:char(36);primaryKey;column:id"`
+	StoreID    uuid.UUID  `gorm:"type:char(36);column:storeId;not null;index"`
+	Store      Store      `gorm:"foreignKey:StoreID"`
+	UserID     uuid.UUID  `gorm:"type:char(36);column:userId;not null"`
+	User       User       `gorm:"foreignKey:UserID"`
+	Name       string     `gorm:"size:100;not null;column:name"`
+	Prefix     string     `gorm:"size:20;not null;column:prefix"`
+	KeyHash    string     `gorm:"size:64;not null;uniqueIndex;column:keyHash"`
+	Scopes     string     `gorm:"size:255;not null;column:scopes"`
+	LastUsedAt *time.Time `gorm:"column:lastUsedAt"`
+	RevokedAt  *time.Time `gorm:"column:revokedAt"`
+	CreatedAt  time.Time  `gorm:"column:createdAt"`
+}
+
+type APIKeyPayload struct {
+	Name   string   `json:"name" validate:"required,min=1,max=100"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,oneof=stores:read stores:write billboards:read billboards:write catalog:read catalog:write"`
+}
+
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type APIKeyCreatedResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type APIKeyHandler interface {
+	Create(ctx echo.Context) error
+	GetAll(ctx echo.Context) error
+	Revoke(ctx echo.Context) error
+}
+
+type APIKeyService interface {
+	Create(ctx context.Context, storeID uuid.UUID, apiKeyPayload APIKeyPayload) (*APIKeyCreatedResponse, error)
+	GetAll(ctx context.Context, storeID uuid.UUID) ([]*APIKeyResponse, error)
+	Revoke(ctx context.Context, storeID uuid.UUID, apiKeyID uuid.UUID) error
+	Authenticate(ctx context.Context, key string) (*Session, error)
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, apiKey APIKey) error
+	GetAll(ctx context.Context, storeID uuid.UUID) ([]*APIKey, error)
+	GetByID(ctx context.Context, apiKeyID uuid.UUID) (*APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (*APIKey, error)
+	UpdateLastUsed(ctx context.Context, apiKeyID uuid.UUID, lastUsedAt time.Time) error
+	Revoke(ctx context.Context, apiKeyID uuid.UUID) error
+}
+
+func (a *APIKeyPayload) trim() {
+	a.Name = strings.TrimSpace(a.Name)
+	for index, scope := range a.Scopes {
+		a.Scopes[index] = strings.TrimSpace(scope)
+	}
+}
+
+func (a *APIKeyPayload) Validate() error {
+	a.trim()
+	validate := validator.New()
+	return validate.Struct(a)
+}
+
+func (a *APIKeyPayload) ToAPIKey(storeID uuid.UUID, userID uuid.UUID, prefix string, keyHash string) *APIKey {
+	return &APIKey{
+		ID:        uuid.New(),
+		StoreID:   storeID,
+		UserID:    userID,
+		Name:      a.Name,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		Scopes:    strings.Join(a.Scopes, ","),
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func (a *APIKey) GetScopes() []string {
+	if a.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(a.Scopes, ",")
+}
+
+func (a *APIKey) ToResponse() *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:         a.ID.String(),
+		Name:       a.Name,
+		Prefix:     a.Prefix,
+		Scopes:     a.GetScopes(),
+		LastUsedAt: a.LastUsedAt,
+		CreatedAt:  a.CreatedAt,
+	}
+}
+
+func (a *APIKeyResponse) In(location *time.Location) *APIKeyResponse {
+	if a.LastUsedAt != nil {
+		lastUsedAt := a.LastUsedAt.In(location)
+		a.LastUsedAt = &lastUsedAt
+	}
+
+	a.CreatedAt = a.CreatedAt.In(location)
+	return a
+}
+
+func (a *APIKey) ToSession() *Session {
+	return &Session{
+		ID:        a.ID,
+		Name:      a.User.Name,
+		UserID:    a.UserID,
+		Email:     a.User.Email,
+		AvatarURL: a.User.AvatarURL,
+		APIKeyID:  a.ID,
+		StoreID:   a.StoreID,
+		Scopes:    a.GetScopes(),
+	}
+}
+
+func (APIKey) TableName() string {
+	return "APIKey"
+}
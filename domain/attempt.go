@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrAccountLocked      = errors.New("account temporarily locked")
+	ErrIPLocked           = errors.New("too many attempts from this ip")
+	ErrAttemptThrottled   = errors.New("attempt throttled")
+	ErrRequestRateLimited = errors.New("too many requests")
+	ErrUnlockTokenInvalid = errors.New("invalid or expired unlock token")
+)
+
+type AttemptPurpose string
+
+const (
+	AttemptPurposeSignIn          AttemptPurpose = "sign_in"
+	AttemptPurposeConfirmEmail    AttemptPurpose = "confirm_email"
+	AttemptPurposeOTP             AttemptPurpose = "otp"
+	AttemptPurposeResendCode      AttemptPurpose = "resend_code"
+	AttemptPurposeMagicLink       AttemptPurpose = "magic_link"
+	AttemptPurposeForgotPassword  AttemptPurpose = "forgot_password"
+	AttemptPurposeAccountDeletion AttemptPurpose = "account_deletion"
+)
+
+type AttemptScope string
+
+const (
+	AttemptScopeAccount AttemptScope = "account"
+	AttemptScopeIP      AttemptScope = "ip"
+)
+
+type AttemptBlockedError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+type UnlockToken struct {
+	Purpose AttemptPurpose
+	Account string
+}
+
+type UnlockAccountPayload struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type AttemptHandler interface {
+	Unlock(ctx echo.Context) error
+}
+
+type AttemptService interface {
+	Check(ctx context.Context, purpose AttemptPurpose, account string) error
+	Fail(ctx context.Context, purpose AttemptPurpose, account string, user *User) error
+	Reset(ctx context.Context, purpose AttemptPurpose, account string) error
+	Limit(ctx context.Context, purpose AttemptPurpose, account string) error
+	VerifyPassword(ctx context.Context, user User, password string) error
+	Unlock(ctx context.Context, unlockAccountPayload UnlockAccountPayload) error
+}
+
+type AttemptRepository interface {
+	IncrementFailures(ctx context.Context, purpose AttemptPurpose, scope AttemptScope, identifier string, window time.Duration) (int64, error)
+	IncrementRequests(ctx context.Context, purpose AttemptPurpose, scope AttemptScope, identifier string, window time.Duration) (int64, time.Duration, error)
+	DeleteFailures(ctx context.Context, purpose AttemptPurpose, scope AttemptScope, identifier string) error
+	SaveLock(ctx context.Context, purpose AttemptPurpose, scope AttemptScope, identifier string, duration time.Duration) error
+	GetLock(ctx context.Context, purpose AttemptPurpose, scope AttemptScope, identifier string) (time.Duration, error)
+	DeleteLock(ctx context.Context, purpose AttemptPurpose, scope AttemptScope, identifier string) error
+	SaveThrottle(ctx context.Context, purpose AttemptPurpose, account string, delay time.Duration) error
+	GetThrottle(ctx context.Context, purpose AttemptPurpose, account string) (time.Duration, error)
+	SaveUnlockToken(ctx context.Context, tokenHash string, unlockToken UnlockToken, expiration time.Duration) error
+	GetUnlockToken(ctx context.Context, tokenHash string) (*UnlockToken, error)
+}
+
+func (a *AttemptBlockedError) Error() string {
+	return a.Err.Error()
+}
+
+func (a *AttemptBlockedError) Unwrap() error {
+	return a.Err
+}
+
+func (u *UnlockAccountPayload) trim() {
+	u.Token = strings.TrimSpace(u.Token)
+}
+
+func (u *UnlockAccountPayload) Validate() error {
+	u.trim()
+	validate := validator.New()
+	return validate.Struct(u)
+}
@@ -0,0 +1,364 @@
+package domain
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrAuditCursorInvalid = errors.New("invalid audit cursor")
+)
+
+const (
+	defaultAuditPageSize = 50
+	MaxAuditExportRows   = 10000
+)
+
+type AuditActorType string
+
+const (
+	AuditActorUser     AuditActorType = "user"
+	AuditActorAPIKey   AuditActorType = "api_key"
+	AuditActorAdminKey AuditActorType = "admin_key"
+	AuditActorSystem   AuditActorType = "system"
+)
+
+type AuditAction string
+
+const (
+	AuditActionUserCreate            AuditAction = "user.create"
+	AuditActionUserUpdateName        AuditAction = "user.update_name"
+	AuditActionUserUpdateUsername    AuditAction = "user.update_username"
+	AuditActionUserUpdatePassword    AuditAction = "user.update_password"
+	AuditActionUserResetPassword     AuditAction = "user.reset_password"
+	AuditActionUserConfirmEmail      AuditAction = "user.confirm_email"
+	AuditActionUserChangeEmail       AuditAction = "user.change_email"
+	AuditActionUserCancelEmailChange AuditAction = "user.cancel_email_change"
+	AuditActionUserUpdateAvatar      AuditAction = "user.update_avatar"
+	AuditActionUserDeleteAvatar      AuditAction = "user.delete_avatar"
+	AuditActionUserDelete            AuditAction = "user.delete"
+	AuditActionUserSuspend           AuditAction = "user.suspend"
+	AuditActionUserUnsuspend         AuditAction = "user.unsuspend"
+	AuditActionUserUpdateRole        AuditAction = "user.update_role"
+	AuditActionUserReportLogin       AuditAction = "user.report_login"
+	AuditActionTwoFactorEnable       AuditAction = "two_factor.enable"
+	AuditActionTwoFactorDisable      AuditAction = "two_factor.disable"
+	AuditActionIdentityLink          AuditAction = "identity.link"
+	AuditActionSessionRevoke         AuditAction = "session.revoke"
+	AuditActionSessionRevokeOthers   AuditAction = "session.revoke_others"
+	AuditActionSessionRevokeAll      AuditAction = "session.revoke_all"
+	AuditActionStoreCreate           AuditAction = "store.create"
+	AuditActionStoreUpdateName       AuditAction = "store.update_name"
+	AuditActionStoreUpdateSlug       AuditAction = "store.update_slug"
+	AuditActionStoreUpdateVisibility AuditAction = "store.update_visibility"
+	AuditActionStoreUpdateSettings   AuditAction = "store.update_settings"
+	AuditActionStoreDelete           AuditAction = "store.delete"
+	AuditActionStoreRestore          AuditAction = "store.restore"
+	AuditActionDomainCreate          AuditAction = "domain.create"
+	AuditActionDomainVerify          AuditAction = "domain.verify"
+	AuditActionDomainDelete          AuditAction = "domain.delete"
+	AuditActionBillboardCreate       AuditAction = "billboard.create"
+	AuditActionAPIKeyCreate          AuditAction = "api_key.create"
+	AuditActionAPIKeyRevoke          AuditAction = "api_key.revoke"
+	AuditActionMemberUpdateRole      AuditAction = "member.update_role"
+	AuditActionMemberDelete          AuditAction = "member.delete"
+	AuditActionInviteCreate          AuditAction = "invite.create"
+	AuditActionInviteResend          AuditAction = "invite.resend"
+	AuditActionInviteRevoke          AuditAction = "invite.revoke"
+	AuditActionInviteAccept          AuditAction = "invite.accept"
+	AuditActionInviteDecline         AuditAction = "invite.decline"
+)
+
+const (
+	AuditTargetUser      = "user"
+	AuditTargetSession   = "session"
+	AuditTargetStore     = "store"
+	AuditTargetBillboard = "billboard"
+	AuditTargetAPIKey    = "api_key"
+	AuditTargetMember    = "member"
+	AuditTargetInvite    = "invite"
+	AuditTargetDomain    = "domain"
+)
+
+type AuditEvent struct {
+	ID         uuid.UUID      `gorm:"type:char(36);primaryKey;column:id"`
+	StoreID    *uuid.UUID     `gorm:"type:char(36);column:storeId;index:idx_audit_store_created,priority:1"`
+	ActorID    *uuid.UUID     `gorm:"type:char(36);column:actorId;index"`
+	ActorType  AuditActorType `gorm:"size:20;not null;column:actorType"`
+	Action     AuditAction    `gorm:"size:50;not null;column:action;index"`
+	TargetType string         `gorm:"size:50;column:targetType"`
+	TargetID   string         `gorm:"size:64;column:targetId"`
+	Before     string         `gorm:"type:text;column:beforeData"`
+	After      string         `gorm:"type:text;column:afterData"`
+	IP         string         `gorm:"size:45;column:ip"`
+	RequestID  string         `gorm:"size:64;column:requestId"`
+	CreatedAt  time.Time      `gorm:"column:createdAt;index:idx_audit_store_created,priority:2"`
+}
+
+type AuditEntry struct {
+	StoreID    uuid.UUID
+	ActorID    uuid.UUID
+	Action     AuditAction
+	TargetType string
+	TargetID   string
+	Before     any
+	After      any
+}
+
+type AuditFilter struct {
+	Action     AuditAction
+	ActorID    *uuid.UUID
+	TargetType string
+	TargetID   string
+	From       *time.Time
+	To         *time.Time
+}
+
+type AuditCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+type AuditQueryPayload struct {
+	Action     string `query:"action" validate:"max=50"`
+	ActorID    string `query:"actorId" validate:"omitempty,uuid"`
+	TargetType string `query:"targetType" validate:"max=50"`
+	TargetID   string `query:"targetId" validate:"max=64"`
+	From       string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To         string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Cursor     string `query:"cursor"`
+	Limit      int    `query:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+type AuditEventResponse struct {
+	ID         string              `json:"id"`
+	StoreID    string              `json:"storeId,omitempty"`
+	ActorID    string              `json:"actorId,omitempty"`
+	ActorType  AuditActorType      `json:"actorType"`
+	Action     AuditAction         `json:"action"`
+	TargetType string              `json:"targetType,omitempty"`
+	TargetID   string              `json:"targetId,omitempty"`
+	Before     jsoniter.RawMessage `json:"before,omitempty"`
+	After      jsoniter.RawMessage `json:"after,omitempty"`
+	IP         string              `json:"ip,omitempty"`
+	RequestID  string              `json:"requestId,omitempty"`
+	CreatedAt  time.Time           `json:"createdAt"`
+}
+
+type AuditEventsResponse struct {
+	Items      []*AuditEventResponse `json:"items"`
+	NextCursor string                `json:"nextCursor,omitempty"`
+}
+
+type AuditHandler interface {
+	GetAll(ctx echo.Context) error
+	Export(ctx echo.Context) error
+}
+
+type AuditService interface {
+	Record(ctx context.Context, auditEntry AuditEntry) error
+	GetAll(ctx context.Context, storeID uuid.UUID, auditQueryPayload AuditQueryPayload) (*AuditEventsResponse, error)
+	Export(ctx context.Context, storeID uuid.UUID, auditQueryPayload AuditQueryPayload) ([]*AuditEventResponse, error)
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, auditEvent AuditEvent) error
+	GetAll(ctx context.Context, storeID uuid.UUID, auditFilter AuditFilter, auditCursor *AuditCursor, limit int) ([]*AuditEvent, error)
+}
+
+func (a *AuditQueryPayload) trim() {
+	a.Action = strings.TrimSpace(a.Action)
+	a.ActorID = strings.TrimSpace(a.ActorID)
+	a.TargetType = strings.TrimSpace(a.TargetType)
+	a.TargetID = strings.TrimSpace(a.TargetID)
+	a.Cursor = strings.TrimSpace(a.Cursor)
+}
+
+func (a *AuditQueryPayload) Validate() error {
+	a.trim()
+	validate := validator.New()
+	if err := validate.Struct(a); err != nil {
+		return err
+	}
+
+	if a.Limit == 0 {
+		a.Limit = defaultAuditPageSize
+	}
+
+	if a.Cursor != "" {
+		if _, err := DecodeAuditCursor(a.Cursor); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *AuditQueryPayload) ToFilter() AuditFilter {
+	auditFilter := AuditFilter{
+		Action:     AuditAction(a.Action),
+		TargetType: a.TargetType,
+		TargetID:   a.TargetID,
+	}
+
+	if actorID, err := uuid.Parse(a.ActorID); err == nil {
+		auditFilter.ActorID = &actorID
+	}
+
+	if from, err := time.Parse(time.RFC3339, a.From); err == nil {
+		auditFilter.From = &from
+	}
+
+	if to, err := time.Parse(time.RFC3339, a.To); err == nil {
+		auditFilter.To = &to
+	}
+
+	return auditFilter
+}
+
+func (a *AuditEntry) Diff() (string, string, error) {
+	before, err := toAuditMap(a.Before)
+	if err != nil {
+		return "", "", err
+	}
+
+	after, err := toAuditMap(a.After)
+	if err != nil {
+		return "", "", err
+	}
+
+	if before != nil && after != nil {
+		for key, value := range before {
+			if afterValue, ok := after[key]; ok && reflect.DeepEqual(value, afterValue) {
+				delete(before, key)
+				delete(after, key)
+			}
+		}
+	}
+
+	beforeJSON, err := marshalAuditMap(before)
+	if err != nil {
+		return "", "", err
+	}
+
+	afterJSON, err := marshalAuditMap(after)
+	if err != nil {
+		return "", "", err
+	}
+
+	return beforeJSON, afterJSON, nil
+}
+
+func (a *AuditEvent) ToResponse() *AuditEventResponse {
+	auditEventResponse := &AuditEventResponse{
+		ID:         a.ID.String(),
+		ActorType:  a.ActorType,
+		Action:     a.Action,
+		TargetType: a.TargetType,
+		TargetID:   a.TargetID,
+		IP:         a.IP,
+		RequestID:  a.RequestID,
+		CreatedAt:  a.CreatedAt,
+	}
+
+	if a.StoreID != nil {
+		auditEventResponse.StoreID = a.StoreID.String()
+	}
+
+	if a.ActorID != nil {
+		auditEventResponse.ActorID = a.ActorID.String()
+	}
+
+	if a.Before != "" {
+		auditEventResponse.Before = jsoniter.RawMessage(a.Before)
+	}
+
+	if a.After != "" {
+		auditEventResponse.After = jsoniter.RawMessage(a.After)
+	}
+
+	return auditEventResponse
+}
+
+func (a *AuditEvent) Cursor() string {
+	value := fmt.Sprintf("%d_%s", a.CreatedAt.UnixNano(), a.ID.String())
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+func DecodeAuditCursor(cursor string) (*AuditCursor, error) {
+	value, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrAuditCursorInvalid
+	}
+
+	createdAt, id, found := strings.Cut(string(value), "_")
+	if !found {
+		return nil, ErrAuditCursorInvalid
+	}
+
+	nanos, err := strconv.ParseInt(createdAt, 10, 64)
+	if err != nil {
+		return nil, ErrAuditCursorInvalid
+	}
+
+	auditEventID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrAuditCursorInvalid
+	}
+
+	return &AuditCursor{
+		CreatedAt: time.Unix(0, nanos).UTC(),
+		ID:        auditEventID,
+	}, nil
+}
+
+func toAuditMap(value any) (map[string]any, error) {
+	if value == nil || (reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil()) {
+		return nil, nil
+	}
+
+	data, err := jsoniter.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var auditMap map[string]any
+	if err := jsoniter.Unmarshal(data, &auditMap); err != nil {
+		return nil, err
+	}
+
+	return auditMap, nil
+}
+
+func marshalAuditMap(auditMap map[string]any) (string, error) {
+	if len(auditMap) == 0 {
+		return "", nil
+	}
+
+	data, err := jsoniter.Marshal(auditMap)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func (a *AuditEventResponse) In(location *time.Location) *AuditEventResponse {
+	a.CreatedAt = a.CreatedAt.In(location)
+	return a
+}
+
+func (AuditEvent) TableName() string {
+	return "AuditEvent"
+}
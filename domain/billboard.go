@@ -87,6 +87,11 @@ func (b *Billboard) ToResponse() *BillboardRespose {
 	}
 }
 
+func (b *BillboardRespose) In(location *time.Location) *BillboardRespose {
+	b.CreatedAt = b.CreatedAt.In(location)
+	return b
+}
+
 func (Billboard) TableName() string {
 	return "Billboard"
 }
@@ -17,10 +17,46 @@ type OTPEmailPayload struct {
 	OTP  string
 }
 
+type LinkEmailPayload struct {
+	Name string
+	URL  string
+}
+
+type EmailChangedPayload struct {
+	Name     string
+	NewEmail string
+	URL      string
+}
+
+type NewDeviceEmailPayload struct {
+	Name   string
+	Device string
+	IP     string
+	Time   string
+	URL    string
+}
+
+type StoreInviteEmailPayload struct {
+	StoreName    string
+	InviterName  string
+	Role         StoreRole
+	URL          string
+	ExpiresAt    string
+	SupportEmail string
+}
+
 type SendEmailResponse struct {
 	Id string
 }
 
 type EmailService interface {
 	SendConfirmationCode(ctx context.Context, user User) error
+	SendPasswordResetCode(ctx context.Context, user User) error
+	SendUnlockLink(ctx context.Context, user User, unlockURL string) error
+	SendMagicLink(ctx context.Context, user User, magicLinkURL string) error
+	SendEmailChangeCode(ctx context.Context, user User, newEmail string) error
+	SendAccountDeletionCode(ctx context.Context, user User) error
+	SendEmailChangedNotice(ctx context.Context, user User, newEmail string, cancelURL string) error
+	SendNewDeviceNotice(ctx context.Context, user User, newDeviceEmailPayload NewDeviceEmailPayload) error
+	SendStoreInvite(ctx context.Context, email string, storeInviteEmailPayload StoreInviteEmailPayload) error
 }
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrOIDCNotConfigured      = errors.New("oidc sign in not configured")
+	ErrOIDCStateInvalid       = errors.New("invalid or expired oidc state")
+	ErrOIDCTokenInvalid       = errors.New("invalid oidc token")
+	ErrOIDCEmailNotVerified   = errors.New("oidc email not verified")
+	ErrIdentityProviderFailed = errors.New("identity provider request failed")
+	ErrOIDCLinkRequired       = errors.New("sign in with your password to link this identity")
+	ErrIdentityAlreadyLinked  = errors.New("identity already linked to another user")
+)
+
+type UserIdentity struct {
+	ID        uuid.UUID `gorm:"type:char(36);primaryKey;column:id"`
+	UserID    uuid.UUID `gorm:"type:char(36);column:userId;not null;index"`
+	User      User      `gorm:"foreignKey:UserID"`
+	Provider  string    `gorm:"size:50;not null;uniqueIndex:idx_identity_provider_subject;column:provider"`
+	Subject   string    `gorm:"size:255;not null;uniqueIndex:idx_identity_provider_subject;column:subject"`
+	Email     string    `gorm:"size:100;column:email"`
+	CreatedAt time.Time `gorm:"column:createdAt"`
+}
+
+type OIDCState struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+	UserID       uuid.UUID
+}
+
+type OIDCCallbackPayload struct {
+	Code  string `json:"code" validate:"required"`
+	State string `json:"state" validate:"required"`
+}
+
+type OIDCAuthorizeResponse struct {
+	AuthorizationURL string `json:"authorizationUrl"`
+	State            string `json:"state"`
+}
+
+type IdentityHandler interface {
+	Authorize(ctx echo.Context) error
+	Callback(ctx echo.Context) error
+	AuthorizeLink(ctx echo.Context) error
+	Link(ctx echo.Context) error
+}
+
+type IdentityService interface {
+	Authorize(ctx context.Context) (*OIDCAuthorizeResponse, error)
+	Callback(ctx context.Context, oidcCallbackPayload OIDCCallbackPayload) (*SessionResponse, error)
+	AuthorizeLink(ctx context.Context) (*OIDCAuthorizeResponse, error)
+	Link(ctx context.Context, oidcCallbackPayload OIDCCallbackPayload) error
+}
+
+type IdentityRepository interface {
+	Create(ctx context.Context, identity UserIdentity) error
+	GetByProviderSubject(ctx context.Context, provider string, subject string) (*UserIdentity, error)
+	SaveState(ctx context.Context, oidcState OIDCState) error
+	GetState(ctx context.Context, state string) (*OIDCState, error)
+}
+
+func (o *OIDCCallbackPayload) trim() {
+	o.Code = strings.TrimSpace(o.Code)
+	o.State = strings.TrimSpace(o.State)
+}
+
+func (o *OIDCCallbackPayload) Validate() error {
+	o.trim()
+	validate := validator.New()
+	return validate.Struct(o)
+}
+
+func (UserIdentity) TableName() string {
+	return "UserIdentity"
+}
@@ -0,0 +1,7 @@
+package domain
+
+import "github.com/labstack/echo/v4"
+
+type JWKSHandler interface {
+	GetJWKS(ctx echo.Context) error
+}
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrPasswordResetRequired = errors.New("password reset required")
+	ErrLoginReportInvalid    = errors.New("invalid or expired login report token")
+)
+
+const defaultLoginHistoryPageSize = 20
+
+type LoginMethod string
+
+const (
+	LoginMethodPassword  LoginMethod = "password"
+	LoginMethodTwoFactor LoginMethod = "two_factor"
+	LoginMethodMagicLink LoginMethod = "magic_link"
+	LoginMethodOIDC      LoginMethod = "oidc"
+	LoginMethodInvite    LoginMethod = "invite"
+)
+
+type LoginResult string
+
+const (
+	LoginResultSuccess               LoginResult = "success"
+	LoginResultUnknownUser           LoginResult = "unknown_user"
+	LoginResultInvalidPassword       LoginResult = "invalid_password"
+	LoginResultInvalidTwoFactor      LoginResult = "invalid_two_factor"
+	LoginResultTwoFactorRequired     LoginResult = "two_factor_required"
+	LoginResultBlocked               LoginResult = "blocked"
+	LoginResultSuspended             LoginResult = "suspended"
+	LoginResultPasswordResetRequired LoginResult = "password_reset_required"
+	LoginResultDeviceMismatch        LoginResult = "device_mismatch"
+)
+
+type LoginHistory struct {
+	ID          uuid.UUID   `gorm:"type:char(36);primaryKey;column:id"`
+	UserID      *uuid.UUID  `gorm:"type:char(36);index:idx_login_history_user_created,priority:1;column:userId"`
+	Email       string      `gorm:"size:100;not null;column:email"`
+	Method      LoginMethod `gorm:"size:20;not null;column:method"`
+	Result      LoginResult `gorm:"size:30;not null;column:result"`
+	IP          string      `gorm:"size:45;column:ip"`
+	UserAgent   string      `gorm:"size:255;column:userAgent"`
+	Device      string      `gorm:"size:100;column:device"`
+	Fingerprint string      `gorm:"size:64;index;column:fingerprint"`
+	SessionID   *uuid.UUID  `gorm:"type:char(36);column:sessionId"`
+	CreatedAt   time.Time   `gorm:"index:idx_login_history_user_created,priority:2;column:createdAt"`
+}
+
+type LoginAttempt struct {
+	UserID    uuid.UUID
+	Email     string
+	Method    LoginMethod
+	Result    LoginResult
+	SessionID uuid.UUID
+}
+
+type LoginReport struct {
+	UserID    uuid.UUID
+	SessionID uuid.UUID
+}
+
+type LoginHistoryQueryPayload struct {
+	Page     int `query:"page" validate:"omitempty,min=1"`
+	PageSize int `query:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type LoginReportPayload struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type LoginHistoryResponse struct {
+	ID        string      `json:"id"`
+	Method    LoginMethod `json:"method"`
+	Result    LoginResult `json:"result"`
+	IP        string      `json:"ip"`
+	UserAgent string      `json:"userAgent"`
+	Device    string      `json:"device"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+type LoginHistoriesResponse struct {
+	Items    []*LoginHistoryResponse `json:"items"`
+	Page     int                     `json:"page"`
+	PageSize int                     `json:"pageSize"`
+	Total    int64                   `json:"total"`
+}
+
+type LoginHistoryHandler interface {
+	GetAll(ctx echo.Context) error
+	Report(ctx echo.Context) error
+}
+
+type LoginHistoryService interface {
+	Record(ctx context.Context, loginAttempt LoginAttempt) error
+	GetAll(ctx context.Context, loginHistoryQueryPayload LoginHistoryQueryPayload) (*LoginHistoriesResponse, error)
+	Report(ctx context.Context, loginReportPayload LoginReportPayload) error
+}
+
+type LoginHistoryRepository interface {
+	Create(ctx context.Context, loginHistory LoginHistory) error
+	GetAll(ctx context.Context, userID uuid.UUID, offset int, limit int) ([]*LoginHistory, int64, error)
+	HasSuccess(ctx context.Context, userID uuid.UUID, fingerprint string) (bool, error)
+	SaveReport(ctx context.Context, tokenHash string, loginReport LoginReport, expiration time.Duration) error
+	ConsumeReport(ctx context.Context, tokenHash string) (*LoginReport, error)
+}
+
+func (l *LoginHistoryQueryPayload) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(l); err != nil {
+		return err
+	}
+
+	if l.Page == 0 {
+		l.Page = 1
+	}
+
+	if l.PageSize == 0 {
+		l.PageSize = defaultLoginHistoryPageSize
+	}
+
+	return nil
+}
+
+func (l *LoginHistoryQueryPayload) Offset() int {
+	return (l.Page - 1) * l.PageSize
+}
+
+func (l *LoginReportPayload) trim() {
+	l.Token = strings.TrimSpace(l.Token)
+}
+
+func (l *LoginReportPayload) Validate() error {
+	l.trim()
+	validate := validator.New()
+	return validate.Struct(l)
+}
+
+func (l *LoginHistory) ToResponse() *LoginHistoryResponse {
+	return &LoginHistoryResponse{
+		ID:        l.ID.String(),
+		Method:    l.Method,
+		Result:    l.Result,
+		IP:        l.IP,
+		UserAgent: l.UserAgent,
+		Device:    l.Device,
+		CreatedAt: l.CreatedAt,
+	}
+}
+
+func (LoginHistory) TableName() string {
+	return "LoginHistory"
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrMagicLinkInvalid        = errors.New("invalid or expired magic link")
+	ErrMagicLinkDeviceMismatch = errors.New("magic link requested from another device")
+)
+
+type MagicLink struct {
+	UserID          uuid.UUID
+	Email           string
+	DeviceTokenHash string
+}
+
+type MagicLinkPayload struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type MagicLinkSignInPayload struct {
+	Token       string `json:"token" validate:"required"`
+	DeviceToken string `json:"deviceToken" validate:"required"`
+}
+
+// MagicLinkResponse carries the device token the requesting browser has to
+// present together with the emailed link, so the link alone cannot sign in.
+type MagicLinkResponse struct {
+	DeviceToken string `json:"deviceToken"`
+}
+
+type MagicLinkHandler interface {
+	Request(ctx echo.Context) error
+	SignIn(ctx echo.Context) error
+}
+
+type MagicLinkService interface {
+	Request(ctx context.Context, magicLinkPayload MagicLinkPayload) (*MagicLinkResponse, error)
+	SignIn(ctx context.Context, magicLinkSignInPayload MagicLinkSignInPayload) (*SessionResponse, error)
+}
+
+type MagicLinkRepository interface {
+	Save(ctx context.Context, tokenHash string, magicLink MagicLink, expiration time.Duration) error
+	Consume(ctx context.Context, tokenHash string) (*MagicLink, error)
+}
+
+func (m *MagicLinkPayload) trim() {
+	m.Email = strings.TrimSpace(m.Email)
+}
+
+func (m *MagicLinkPayload) Validate() error {
+	m.trim()
+	validate := validator.New()
+	return validate.Struct(m)
+}
+
+func (m *MagicLinkSignInPayload) trim() {
+	m.Token = strings.TrimSpace(m.Token)
+	m.DeviceToken = strings.TrimSpace(m.DeviceToken)
+}
+
+func (m *MagicLinkSignInPayload) Validate() error {
+	m.trim()
+	validate := validator.New()
+	return validate.Struct(m)
+}
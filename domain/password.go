@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+
+	"github.com/GSVillas/e-commercer-api/secure"
+)
+
+var ErrPasswordPolicy = errors.New("password does not satisfy the policy")
+
+type PasswordViolationResponse struct {
+	Field  string                       `json:"field"`
+	Code   secure.PasswordViolationCode `json:"code"`
+	Reason string                       `json:"reason"`
+}
+
+type PasswordPolicyError struct {
+	Violations []PasswordViolationResponse
+}
+
+func (p *PasswordPolicyError) Error() string {
+	return ErrPasswordPolicy.Error()
+}
+
+func (p *PasswordPolicyError) Unwrap() error {
+	return ErrPasswordPolicy
+}
+
+// checkPasswordPolicy falls back to the default policy when none was loaded.
+func checkPasswordPolicy(passwordPolicy *secure.PasswordPolicy, field string, password string, userInputs ...string) error {
+	if passwordPolicy == nil {
+		passwordPolicy = secure.DefaultPasswordPolicy()
+	}
+
+	violations := passwordPolicy.Check(password, userInputs...)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	passwordPolicyError := &PasswordPolicyError{
+		Violations: make([]PasswordViolationResponse, 0, len(violations)),
+	}
+	for _, violation := range violations {
+		passwordPolicyError.Violations = append(passwordPolicyError.Violations, PasswordViolationResponse{
+			Field:  field,
+			Code:   violation.Code,
+			Reason: violation.Reason,
+		})
+	}
+
+	return passwordPolicyError
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type PublicBillboardResponse struct {
+	ID       string `json:"id"`
+	Label    string `json:"label"`
+	ImageURL string `json:"imageUrl"`
+}
+
+type PublicStoreDetailResponse struct {
+	PublicStoreResponse
+	Billboards []*PublicBillboardResponse `json:"billboards"`
+}
+
+// PublicStoreCache is the rendered public payload of a store as kept in
+// Redis, so cache hits skip both the database and the JSON encoding.
+type PublicStoreCache struct {
+	ETag   string        `json:"etag"`
+	Body   []byte        `json:"body"`
+	MaxAge time.Duration `json:"-"`
+}
+
+type PublicStoreHandler interface {
+	GetByID(ctx echo.Context) error
+	GetStorefront(ctx echo.Context) error
+}
+
+type PublicStoreService interface {
+	GetByID(ctx context.Context, storeID uuid.UUID) (*PublicStoreCache, error)
+	GetStorefront(ctx context.Context) (*PublicStoreCache, error)
+}
+
+func (b *Billboard) ToPublicResponse() *PublicBillboardResponse {
+	return &PublicBillboardResponse{
+		ID:       b.ID.String(),
+		Label:    b.Label,
+		ImageURL: b.ImageURL,
+	}
+}
+
+func (s *Store) ToPublicDetailResponse() *PublicStoreDetailResponse {
+	billboards := make([]*PublicBillboardResponse, 0, len(s.Billboards))
+	for _, billboard := range s.Billboards {
+		billboards = append(billboards, billboard.ToPublicResponse())
+	}
+
+	return &PublicStoreDetailResponse{
+		PublicStoreResponse: *s.ToPublicResponse(),
+		Billboards:          billboards,
+	}
+}
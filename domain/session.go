@@ -3,8 +3,14 @@ package domain
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
 )
 
 var (
@@ -14,41 +20,184 @@ var (
 	ErrTokenNotFoundInContext = errors.New("token not found in context")
 	ErrOTPNotFound            = errors.New("OTP not found")
 	ErrOTPInvalid             = errors.New("OTP expires")
+	ErrTokenExpired           = errors.New("token expired")
+	ErrRefreshTokenInvalid    = errors.New("invalid refresh token")
+	ErrRefreshTokenReused     = errors.New("refresh token reused")
+	ErrSessionExpired         = errors.New("session expired")
+)
+
+type OTPPurpose string
+
+const (
+	OTPPurposeEmailConfirmation OTPPurpose = "email_confirmation"
+	OTPPurposePasswordReset     OTPPurpose = "password_reset"
+	OTPPurposeEmailChange       OTPPurpose = "email_change"
+	OTPPurposeAccountDeletion   OTPPurpose = "account_deletion"
 )
 
 type Session struct {
-	Token     string
-	Name      string
+	ID         uuid.UUID
+	Token      string
+	Name       string
+	Username   string
+	UserID     uuid.UUID
+	Email      string
+	AvatarURL  string
+	Device     string
+	IP         string
+	UserAgent  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	APIKeyID   uuid.UUID
+	StoreID    uuid.UUID
+	Scopes     []string
+	Role       UserRole
+}
+
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+	Device    string
+	RequestID string
+}
+
+type SessionInfoResponse struct {
+	ID         string    `json:"id"`
+	Device     string    `json:"device"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"userAgent"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	Current    bool      `json:"current"`
+}
+
+// RefreshToken is the current token of a family. CreatedAt is when the family
+// was started at sign in, and rotations keep it so the session has an
+// absolute lifetime.
+type RefreshToken struct {
+	FamilyID  uuid.UUID
 	UserID    uuid.UUID
-	Email     string
-	AvatarURL string
+	TokenHash string
+	CreatedAt time.Time
 }
 
 type SessionResponse struct {
-	Token string `json:"token"`
+	Token             string     `json:"token,omitempty"`
+	RefreshToken      string     `json:"refreshToken,omitempty"`
+	ExpiresAt         *time.Time `json:"expiresAt,omitempty"`
+	TwoFactorRequired bool       `json:"twoFactorRequired,omitempty"`
+	ChallengeToken    string     `json:"challengeToken,omitempty"`
+	SessionID         uuid.UUID  `json:"-"`
+}
+
+type RefreshTokenPayload struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type RevokeTokenPayload struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type SessionHandler interface {
+	GetAll(ctx echo.Context) error
+	Delete(ctx echo.Context) error
+	DeleteOthers(ctx echo.Context) error
+	RevokeToken(ctx echo.Context) error
+	RevokeUserSessions(ctx echo.Context) error
 }
 
 type SessionService interface {
-	Create(ctx context.Context, user User) (string, error)
+	Create(ctx context.Context, user User) (*SessionResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (*SessionResponse, error)
 	GetUser(ctx context.Context, token string) (*Session, error)
+	GetAll(ctx context.Context) ([]*SessionInfoResponse, error)
 	Update(ctx context.Context) error
-	SaveOTP(ctx context.Context, email string, otp string) error
-	GetOTP(ctx context.Context, email string) (string, error)
+	Delete(ctx context.Context, sessionID uuid.UUID) error
+	DeleteOthers(ctx context.Context) error
+	RevokeToken(ctx context.Context, token string) error
+	RevokeUserSessions(ctx context.Context, userID uuid.UUID) error
+	SuspendUser(ctx context.Context, userID uuid.UUID) error
+	UnsuspendUser(ctx context.Context, userID uuid.UUID) error
+	CreateTwoFactorChallenge(ctx context.Context, userID uuid.UUID) (string, error)
+	GetTwoFactorChallenge(ctx context.Context, challengeToken string) (uuid.UUID, error)
+	DeleteTwoFactorChallenge(ctx context.Context, challengeToken string) error
+	SaveOTP(ctx context.Context, purpose OTPPurpose, email string, otp string) error
+	GetOTP(ctx context.Context, purpose OTPPurpose, email string) (string, error)
+	DeleteOTP(ctx context.Context, purpose OTPPurpose, email string) error
 }
 
 type SessionRepository interface {
-	Create(ctx context.Context, user User, token string) error
-	GetUser(ctx context.Context, userID string) (*Session, error)
-	Update(ctx context.Context, user User, token string) error
-	SaveOTP(ctx context.Context, email string, otp string) error
-	GetOTP(ctx context.Context, email string) (string, error)
+	Create(ctx context.Context, session Session) error
+	GetUser(ctx context.Context, userID string, sessionID string) (*Session, error)
+	GetAll(ctx context.Context, userID string) ([]*Session, error)
+	Update(ctx context.Context, session Session) error
+	Delete(ctx context.Context, userID string, sessionID string) error
+	DeleteAll(ctx context.Context, userID string) error
+	SaveOTP(ctx context.Context, purpose OTPPurpose, email string, otp string) error
+	GetOTP(ctx context.Context, purpose OTPPurpose, email string) (string, error)
+	DeleteOTP(ctx context.Context, purpose OTPPurpose, email string) error
+	SaveRefreshToken(ctx context.Context, refreshToken RefreshToken) error
+	GetRefreshToken(ctx context.Context, familyID string) (*RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, currentTokenHash string, refreshToken RefreshToken) (bool, error)
+	DeleteRefreshToken(ctx context.Context, familyID string) error
+	SaveTwoFactorChallenge(ctx context.Context, challengeHash string, userID string) error
+	GetTwoFactorChallenge(ctx context.Context, challengeHash string) (string, error)
+	DeleteTwoFactorChallenge(ctx context.Context, challengeHash string) error
+	SaveSuspension(ctx context.Context, userID string) error
+	DeleteSuspension(ctx context.Context, userID string) error
+	IsSuspended(ctx context.Context, userID string) (bool, error)
+}
+
+func (r *RefreshTokenPayload) trim() {
+	r.RefreshToken = strings.TrimSpace(r.RefreshToken)
+}
+
+func (r *RefreshTokenPayload) Validate() error {
+	r.trim()
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+func (r *RevokeTokenPayload) trim() {
+	r.Token = strings.TrimSpace(r.Token)
+}
+
+func (r *RevokeTokenPayload) Validate() error {
+	r.trim()
+	validate := validator.New()
+	return validate.Struct(r)
 }
 
 func (u *Session) ToResponse() *UserResponse {
 	return &UserResponse{
 		ID:        u.UserID.String(),
 		Name:      u.Name,
+		Username:  u.Username,
 		Email:     u.Email,
 		AvatarURL: u.AvatarURL,
 	}
 }
+
+func (c *ClientInfo) Fingerprint() string {
+	return secure.HashToken(c.Device + "|" + c.UserAgent)
+}
+
+func (s *Session) IsAPIKey() bool {
+	return s.APIKeyID != uuid.Nil
+}
+
+func (s *Session) HasScope(scope string) bool {
+	return slices.Contains(s.Scopes, scope)
+}
+
+func (s *Session) ToInfoResponse(currentSessionID uuid.UUID) *SessionInfoResponse {
+	return &SessionInfoResponse{
+		ID:         s.ID.String(),
+		Device:     s.Device,
+		IP:         s.IP,
+		UserAgent:  s.UserAgent,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		Current:    s.ID == currentSessionID,
+	}
+}
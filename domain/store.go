@@ -21,7 +21,10 @@ var (
 type Store struct {
 	ID         uuid.UUID      `gorm:"type:char(36);primaryKey;column:id"`
 	Name       string         `gorm:"size:100;not null;column:name"`
+	Slug       string         `gorm:"size:63;uniqueIndex;column:slug"`
 	UserID     uuid.UUID      `gorm:"type:char(36);column:userId;not null"`
+	IsPublic   bool           `gorm:"not null;default:false;column:isPublic"`
+	Settings   StoreSettings  `gorm:"embedded"`
 	User       User           `gorm:"foreignKey:UserID"`
 	Billboards []Billboard    `gorm:"foreignKey:StoreID"`
 	CreatedAt  time.Time      `gorm:"column:createdAt"`
@@ -30,42 +33,132 @@ type Store struct {
 }
 
 type StorePayload struct {
-	Name string `json:"name" validate:"required,min=1,max=100"`
+	Name     string `json:"name" validate:"required,min=1,max=100"`
+	Slug     string `json:"slug" validate:"omitempty,min=3,max=63"`
+	IsPublic bool   `json:"isPublic"`
 }
 type StoreNameUpdatePayload struct {
 	Name string `json:"name" validate:"required,min=1,max=100"`
 }
 
+type StoreVisibilityUpdatePayload struct {
+	IsPublic *bool `json:"isPublic" validate:"required"`
+}
+
 type StoreResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	IsPublic  bool      `json:"isPublic"`
+	Role      StoreRole `json:"role,omitempty"`
+	CreatedAt string    `json:"createdAt"`
+}
+
+type TrashedStoreResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	DeletedAt string `json:"deletedAt"`
+	PurgeAt   string `json:"purgeAt"`
+}
+
+type StoreOverview struct {
+	BillboardCount int64
+}
+
+type MonthlyRevenueResponse struct {
+	Month   string `json:"month"`
+	Revenue int64  `json:"revenue"`
+}
+
+// StoreOverviewResponse only aggregates billboards for now. Products,
+// categories, orders and stock are not modelled yet, so their aggregates are
+// always null and the dashboard must render them as unavailable rather than
+// as zero. They will be filled in once those models land, with revenue in
+// the minor unit of the store currency.
+type StoreOverviewResponse struct {
+	BillboardCount int64                    `json:"billboardCount"`
+	ProductCount   *int64                   `json:"productCount"`
+	CategoryCount  *int64                   `json:"categoryCount"`
+	TotalRevenue   *int64                   `json:"totalRevenue"`
+	SalesCount     *int64                   `json:"salesCount"`
+	StockOnHand    *int64                   `json:"stockOnHand"`
+	MonthlyRevenue []MonthlyRevenueResponse `json:"monthlyRevenue"`
+}
+
+type StoreDetailResponse struct {
+	StoreResponse
+	UpdatedAt string                 `json:"updatedAt"`
+	Settings  *StoreSettingsResponse `json:"settings"`
+	Overview  StoreOverviewResponse  `json:"overview"`
+}
+
+type PublicStoreResponse struct {
 	ID        string `json:"id"`
 	Name      string `json:"name"`
+	Slug      string `json:"slug"`
 	CreatedAt string `json:"createdAt"`
 }
 
 type StoreHandler interface {
 	Create(ctx echo.Context) error
 	GetAll(ctx echo.Context) error
+	GetTrash(ctx echo.Context) error
+	GetByID(ctx echo.Context) error
 	UpdateName(ctx echo.Context) error
+	UpdateSlug(ctx echo.Context) error
+	UpdateVisibility(ctx echo.Context) error
+	GetSettings(ctx echo.Context) error
+	UpdateSettings(ctx echo.Context) error
 	Delete(ctx echo.Context) error
+	Restore(ctx echo.Context) error
 }
 
 type StoreService interface {
 	Create(ctx context.Context, storePayload StorePayload) (*StoreResponse, error)
 	GetAll(ctx context.Context) ([]*StoreResponse, error)
+	GetTrash(ctx context.Context) ([]*TrashedStoreResponse, error)
+	GetByID(ctx context.Context, storeID uuid.UUID) (*StoreDetailResponse, error)
 	UpdateName(ctx context.Context, storeID uuid.UUID, updateStoreNamePayload StoreNameUpdatePayload) error
+	UpdateSlug(ctx context.Context, storeID uuid.UUID, storeSlugUpdatePayload StoreSlugUpdatePayload) error
+	UpdateVisibility(ctx context.Context, storeID uuid.UUID, storeVisibilityUpdatePayload StoreVisibilityUpdatePayload) error
+	GetSettings(ctx context.Context, storeID uuid.UUID) (*StoreSettingsResponse, error)
+	UpdateSettings(ctx context.Context, storeID uuid.UUID, storeSettingsPayload StoreSettingsPayload) (*StoreSettingsResponse, error)
 	Delete(ctx context.Context, storeID uuid.UUID) error
+	Restore(ctx context.Context, storeID uuid.UUID) (*StoreResponse, error)
+	PurgeDeleted(ctx context.Context) (int, error)
+	ResolveSlug(ctx context.Context, slug string) (*Store, error)
 }
 
 type StoreRepository interface {
 	Create(ctx context.Context, store Store) error
 	GetAll(ctx context.Context, userID uuid.UUID) ([]*Store, error)
 	GetByID(ctx context.Context, storeID uuid.UUID) (*Store, error)
+	GetDeleted(ctx context.Context, userID uuid.UUID) ([]*Store, error)
+	GetDeletedByID(ctx context.Context, storeID uuid.UUID) (*Store, error)
+	GetDeletedBefore(ctx context.Context, before time.Time) ([]*Store, error)
+	GetBySlug(ctx context.Context, slug string) (*Store, error)
+	ExistsBySlug(ctx context.Context, slug string) (bool, error)
+	GetSlugRedirect(ctx context.Context, slug string) (*StoreSlugRedirect, error)
+	GetPublicByOwner(ctx context.Context, userID uuid.UUID) ([]*Store, error)
+	GetPublicByID(ctx context.Context, storeID uuid.UUID) (*Store, error)
+	GetPublicCache(ctx context.Context, storeID uuid.UUID) (*PublicStoreCache, error)
+	SavePublicCache(ctx context.Context, storeID uuid.UUID, publicStoreCache PublicStoreCache, expiration time.Duration) error
+	DeletePublicCache(ctx context.Context, storeID uuid.UUID) error
+	DeletePublicCacheByOwner(ctx context.Context, userID uuid.UUID) error
+	GetOverview(ctx context.Context, storeID uuid.UUID) (*StoreOverview, error)
 	UpdateName(ctx context.Context, name string, ID uuid.UUID) error
+	UpdateSlug(ctx context.Context, oldSlug string, newSlug string, ID uuid.UUID) error
+	UpdateVisibility(ctx context.Context, isPublic bool, ID uuid.UUID) error
+	UpdateSettings(ctx context.Context, storeSettings StoreSettings, ID uuid.UUID) error
 	Delete(ctx context.Context, storeID uuid.UUID) error
+	Restore(ctx context.Context, storeID uuid.UUID, deletedAt time.Time) error
+	Purge(ctx context.Context, storeID uuid.UUID) ([]string, error)
 }
 
 func (s *StorePayload) trim() {
 	s.Name = strings.TrimSpace(s.Name)
+	s.Slug = NormalizeStoreSlug(s.Slug)
 }
 
 func (s *StoreNameUpdatePayload) trim() {
@@ -75,7 +168,15 @@ func (s *StoreNameUpdatePayload) trim() {
 func (s *StorePayload) Validate() error {
 	s.trim()
 	validator := validator.New()
-	return validator.Struct(s)
+	if err := validator.Struct(s); err != nil {
+		return err
+	}
+
+	if s.Slug != "" {
+		return ValidateStoreSlug(s.Slug)
+	}
+
+	return nil
 }
 
 func (s *StoreNameUpdatePayload) Validate() error {
@@ -84,11 +185,19 @@ func (s *StoreNameUpdatePayload) Validate() error {
 	return validator.Struct(s)
 }
 
+func (s *StoreVisibilityUpdatePayload) Validate() error {
+	validator := validator.New()
+	return validator.Struct(s)
+}
+
 func (s *StorePayload) ToStore(userID uuid.UUID) *Store {
 	return &Store{
 		ID:        uuid.New(),
 		Name:      s.Name,
+		Slug:      s.Slug,
 		UserID:    userID,
+		IsPublic:  s.IsPublic,
+		Settings:  DefaultStoreSettings(),
 		CreatedAt: time.Now().UTC(),
 	}
 }
@@ -97,7 +206,43 @@ func (s *Store) ToResponse() *StoreResponse {
 	return &StoreResponse{
 		ID:        s.ID.String(),
 		Name:      s.Name,
-		CreatedAt: s.CreatedAt.Format(time.RFC3339),
+		Slug:      s.Slug,
+		IsPublic:  s.IsPublic,
+		CreatedAt: s.CreatedAt.In(s.Settings.Location()).Format(time.RFC3339),
+	}
+}
+
+func (s *Store) ToDetailResponse(role StoreRole, storeOverview StoreOverview) *StoreDetailResponse {
+	storeResponse := s.ToResponse()
+	storeResponse.Role = role
+
+	return &StoreDetailResponse{
+		StoreResponse: *storeResponse,
+		UpdatedAt:     s.UpdatedAt.In(s.Settings.Location()).Format(time.RFC3339),
+		Settings:      s.Settings.ToResponse(),
+		Overview: StoreOverviewResponse{
+			BillboardCount: storeOverview.BillboardCount,
+		},
+	}
+}
+
+func (s *Store) ToPublicResponse() *PublicStoreResponse {
+	return &PublicStoreResponse{
+		ID:        s.ID.String(),
+		Name:      s.Name,
+		Slug:      s.Slug,
+		CreatedAt: s.CreatedAt.In(s.Settings.Location()).Format(time.RFC3339),
+	}
+}
+
+func (s *Store) ToTrashResponse(purgeAt time.Time) *TrashedStoreResponse {
+	location := s.Settings.Location()
+	return &TrashedStoreResponse{
+		ID:        s.ID.String(),
+		Name:      s.Name,
+		Slug:      s.Slug,
+		DeletedAt: s.DeletedAt.Time.In(location).Format(time.RFC3339),
+		PurgeAt:   purgeAt.In(location).Format(time.RFC3339),
 	}
 }
 
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrStoreDomainNotFound     = errors.New("store domain not found")
+	ErrStoreDomainAlreadyInUse = errors.New("hostname already in use")
+	ErrStoreDomainNotVerified  = errors.New("hostname verification record not found")
+)
+
+const (
+	// StoreDomainVerificationLabel is prepended to the hostname to build the
+	// name of the TXT record the store owner has to publish.
+	StoreDomainVerificationLabel = "_ecommercer-verification"
+	StoreDomainVerificationValue = "ecommercer-verification="
+	StoreDomainVerificationType  = "TXT"
+)
+
+type StoreDomain struct {
+	ID                uuid.UUID  `gorm:"type:char(36);primaryKey;column:id"`
+	StoreID           uuid.UUID  `gorm:"type:char(36);column:storeId;not null;uniqueIndex:idx_store_domain"`
+	Store             Store      `gorm:"foreignKey:StoreID"`
+	Hostname          string     `gorm:"size:253;not null;uniqueIndex:idx_store_domain;index;column:hostname"`
+	VerificationToken string     `gorm:"size:64;not null;column:verificationToken"`
+	VerifiedAt        *time.Time `gorm:"column:verifiedAt"`
+	CreatedAt         time.Time  `gorm:"column:createdAt"`
+	UpdatedAt         time.Time  `gorm:"column:updatedAt"`
+}
+
+type StoreDomainPayload struct {
+	Hostname string `json:"hostname" validate:"required,fqdn,max=253"`
+}
+
+type StoreDomainVerificationResponse struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type StoreDomainResponse struct {
+	ID           string                          `json:"id"`
+	Hostname     string                          `json:"hostname"`
+	Verified     bool                            `json:"verified"`
+	VerifiedAt   *time.Time                      `json:"verifiedAt"`
+	Verification StoreDomainVerificationResponse `json:"verification"`
+	CreatedAt    time.Time                       `json:"createdAt"`
+}
+
+type StoreDomainHandler interface {
+	Create(ctx echo.Context) error
+	GetAll(ctx echo.Context) error
+	Verify(ctx echo.Context) error
+	Delete(ctx echo.Context) error
+}
+
+type StoreDomainService interface {
+	Create(ctx context.Context, storeID uuid.UUID, storeDomainPayload StoreDomainPayload) (*StoreDomainResponse, error)
+	GetAll(ctx context.Context, storeID uuid.UUID) ([]*StoreDomainResponse, error)
+	Verify(ctx context.Context, storeID uuid.UUID, storeDomainID uuid.UUID) (*StoreDomainResponse, error)
+	Delete(ctx context.Context, storeID uuid.UUID, storeDomainID uuid.UUID) error
+	ResolveHost(ctx context.Context, hostname string) (*Store, error)
+}
+
+type StoreDomainRepository interface {
+	Create(ctx context.Context, storeDomain StoreDomain) error
+	GetAll(ctx context.Context, storeID uuid.UUID) ([]*StoreDomain, error)
+	GetByID(ctx context.Context, storeDomainID uuid.UUID) (*StoreDomain, error)
+	GetByStoreAndHostname(ctx context.Context, storeID uuid.UUID, hostname string) (*StoreDomain, error)
+	GetVerifiedByHostname(ctx context.Context, hostname string) (*StoreDomain, error)
+	UpdateVerifiedAt(ctx context.Context, storeDomainID uuid.UUID, verifiedAt time.Time) error
+	Delete(ctx context.Context, storeDomainID uuid.UUID) error
+}
+
+// NormalizeHostname lowercases the hostname and drops the port and the
+// trailing root dot, so values from payloads and Host headers compare equal.
+func NormalizeHostname(hostname string) string {
+	hostname = strings.ToLower(strings.TrimSpace(hostname))
+	if host, _, err := net.SplitHostPort(hostname); err == nil {
+		hostname = host
+	}
+	return strings.TrimSuffix(hostname, ".")
+}
+
+func (s *StoreDomainPayload) trim() {
+	s.Hostname = NormalizeHostname(s.Hostname)
+}
+
+func (s *StoreDomainPayload) Validate() error {
+	s.trim()
+	validate := validator.New()
+	return validate.Struct(s)
+}
+
+func (s *StoreDomainPayload) ToStoreDomain(storeID uuid.UUID, verificationToken string) *StoreDomain {
+	return &StoreDomain{
+		ID:                uuid.New(),
+		StoreID:           storeID,
+		Hostname:          s.Hostname,
+		VerificationToken: verificationToken,
+		CreatedAt:         time.Now().UTC(),
+	}
+}
+
+func (s *StoreDomain) VerificationRecordName() string {
+	return StoreDomainVerificationLabel + "." + s.Hostname
+}
+
+func (s *StoreDomain) VerificationRecordValue() string {
+	return StoreDomainVerificationValue + s.VerificationToken
+}
+
+func (s *StoreDomain) ToResponse() *StoreDomainResponse {
+	return &StoreDomainResponse{
+		ID:         s.ID.String(),
+		Hostname:   s.Hostname,
+		Verified:   s.VerifiedAt != nil,
+		VerifiedAt: s.VerifiedAt,
+		Verification: StoreDomainVerificationResponse{
+			Type:  StoreDomainVerificationType,
+			Name:  s.VerificationRecordName(),
+			Value: s.VerificationRecordValue(),
+		},
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+func (s *StoreDomainResponse) In(location *time.Location) *StoreDomainResponse {
+	if s.VerifiedAt != nil {
+		verifiedAt := s.VerifiedAt.In(location)
+		s.VerifiedAt = &verifiedAt
+	}
+
+	s.CreatedAt = s.CreatedAt.In(location)
+	return s
+}
+
+func (StoreDomain) TableName() string {
+	return "StoreDomain"
+}
@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrStoreInviteNotFound      = errors.New("store invite not found")
+	ErrStoreInviteInvalid       = errors.New("invalid or expired store invite")
+	ErrStoreInviteEmailMismatch = errors.New("store invite was sent to a different email")
+	ErrStoreInviteAlreadyExists = errors.New("a pending invite already exists for this email")
+)
+
+type StoreInviteStatus string
+
+const (
+	StoreInviteStatusPending  StoreInviteStatus = "pending"
+	StoreInviteStatusAccepted StoreInviteStatus = "accepted"
+	StoreInviteStatusDeclined StoreInviteStatus = "declined"
+	StoreInviteStatusRevoked  StoreInviteStatus = "revoked"
+	StoreInviteStatusExpired  StoreInviteStatus = "expired"
+)
+
+type StoreInvite struct {
+	ID          uuid.UUID  `gorm:"type:char(36);primaryKey;column:id"`
+	StoreID     uuid.UUID  `gorm:"type:char(36);column:storeId;not null;index"`
+	Store       Store      `gorm:"foreignKey:StoreID"`
+	Email       string     `gorm:"size:100;not null;index;column:email"`
+	Role        StoreRole  `gorm:"size:20;not null;column:role"`
+	InvitedByID uuid.UUID  `gorm:"type:char(36);column:invitedById;not null"`
+	InvitedBy   User       `gorm:"foreignKey:InvitedByID"`
+	ExpiresAt   time.Time  `gorm:"column:expiresAt;not null"`
+	AcceptedAt  *time.Time `gorm:"column:acceptedAt"`
+	DeclinedAt  *time.Time `gorm:"column:declinedAt"`
+	RevokedAt   *time.Time `gorm:"column:revokedAt"`
+	CreatedAt   time.Time  `gorm:"column:createdAt"`
+	UpdatedAt   time.Time  `gorm:"column:updatedAt"`
+}
+
+type StoreInvitePayload struct {
+	Email string    `json:"email" validate:"required,email,max=100"`
+	Role  StoreRole `json:"role" validate:"required,oneof=admin editor viewer"`
+}
+
+type StoreInviteTokenPayload struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type StoreInviteSignUpPayload struct {
+	Token           string `json:"token" validate:"required"`
+	Name            string `json:"name" validate:"required,min=1,max=75"`
+	Password        string `json:"password,omitempty" validate:"required"`
+	ConfirmPassword string `json:"confirmPassword" validate:"required,eqfield=Password"`
+}
+
+type StoreInviteResponse struct {
+	ID        string            `json:"id"`
+	StoreID   string            `json:"storeId"`
+	Email     string            `json:"email"`
+	Role      StoreRole         `json:"role"`
+	Status    StoreInviteStatus `json:"status"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+type StoreInviteHandler interface {
+	Create(ctx echo.Context) error
+	GetAll(ctx echo.Context) error
+	Resend(ctx echo.Context) error
+	Revoke(ctx echo.Context) error
+	Accept(ctx echo.Context) error
+	Decline(ctx echo.Context) error
+	SignUp(ctx echo.Context) error
+}
+
+type StoreInviteService interface {
+	Create(ctx context.Context, storeID uuid.UUID, storeInvitePayload StoreInvitePayload) (*StoreInviteResponse, error)
+	GetAll(ctx context.Context, storeID uuid.UUID) ([]*StoreInviteResponse, error)
+	Resend(ctx context.Context, storeID uuid.UUID, inviteID uuid.UUID) error
+	Revoke(ctx context.Context, storeID uuid.UUID, inviteID uuid.UUID) error
+	Accept(ctx context.Context, storeInviteTokenPayload StoreInviteTokenPayload) (*StoreResponse, error)
+	Decline(ctx context.Context, storeInviteTokenPayload StoreInviteTokenPayload) error
+	SignUp(ctx context.Context, storeInviteSignUpPayload StoreInviteSignUpPayload) (*SessionResponse, error)
+}
+
+type StoreInviteRepository interface {
+	Create(ctx context.Context, storeInvite StoreInvite) error
+	GetByID(ctx context.Context, inviteID uuid.UUID) (*StoreInvite, error)
+	GetPending(ctx context.Context, storeID uuid.UUID, email string) (*StoreInvite, error)
+	GetAll(ctx context.Context, storeID uuid.UUID) ([]*StoreInvite, error)
+	UpdateExpiration(ctx context.Context, inviteID uuid.UUID, expiresAt time.Time) error
+	Accept(ctx context.Context, inviteID uuid.UUID, storeMember StoreMember) error
+	AcceptWithUser(ctx context.Context, inviteID uuid.UUID, user User, storeMember StoreMember) error
+	Decline(ctx context.Context, inviteID uuid.UUID) error
+	Revoke(ctx context.Context, inviteID uuid.UUID) error
+}
+
+func (s *StoreInvitePayload) trim() {
+	s.Email = strings.ToLower(strings.TrimSpace(s.Email))
+}
+
+func (s *StoreInvitePayload) Validate() error {
+	s.trim()
+	validate := validator.New()
+	return validate.Struct(s)
+}
+
+func (s *StoreInviteTokenPayload) trim() {
+	s.Token = strings.TrimSpace(s.Token)
+}
+
+func (s *StoreInviteTokenPayload) Validate() error {
+	s.trim()
+	validate := validator.New()
+	return validate.Struct(s)
+}
+
+func (s *StoreInviteSignUpPayload) trim() {
+	s.Token = strings.TrimSpace(s.Token)
+	s.Name = strings.TrimSpace(s.Name)
+}
+
+func (s *StoreInviteSignUpPayload) Validate(passwordPolicy *secure.PasswordPolicy) error {
+	s.trim()
+	validate := validator.New()
+	if err := validate.Struct(s); err != nil {
+		return err
+	}
+
+	return checkPasswordPolicy(passwordPolicy, "password", s.Password, s.Name)
+}
+
+func (s *StoreInvitePayload) ToStoreInvite(storeID uuid.UUID, invitedByID uuid.UUID, expiresAt time.Time) *StoreInvite {
+	return &StoreInvite{
+		ID:          uuid.New(),
+		StoreID:     storeID,
+		Email:       s.Email,
+		Role:        s.Role,
+		InvitedByID: invitedByID,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   time.Now().UTC(),
+	}
+}
+
+func (s *StoreInvite) Status() StoreInviteStatus {
+	switch {
+	case s.AcceptedAt != nil:
+		return StoreInviteStatusAccepted
+	case s.DeclinedAt != nil:
+		return StoreInviteStatusDeclined
+	case s.RevokedAt != nil:
+		return StoreInviteStatusRevoked
+	case time.Now().UTC().After(s.ExpiresAt):
+		return StoreInviteStatusExpired
+	default:
+		return StoreInviteStatusPending
+	}
+}
+
+func (s *StoreInvite) ToStoreMember(userID uuid.UUID) *StoreMember {
+	return &StoreMember{
+		ID:        uuid.New(),
+		StoreID:   s.StoreID,
+		UserID:    userID,
+		Role:      s.Role,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func (s *StoreInvite) ToResponse() *StoreInviteResponse {
+	return &StoreInviteResponse{
+		ID:        s.ID.String(),
+		StoreID:   s.StoreID.String(),
+		Email:     s.Email,
+		Role:      s.Role,
+		Status:    s.Status(),
+		ExpiresAt: s.ExpiresAt,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+func (s *StoreInviteResponse) In(location *time.Location) *StoreInviteResponse {
+	s.ExpiresAt = s.ExpiresAt.In(location)
+	s.CreatedAt = s.CreatedAt.In(location)
+	return s
+}
+
+func (StoreInvite) TableName() string {
+	return "StoreInvite"
+}
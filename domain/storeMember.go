@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrStoreMemberNotFound      = errors.New("store member not found")
+	ErrStoreOwnerNotEditable    = errors.New("store owner role cannot be changed")
+	ErrStoreMemberAlreadyExists = errors.New("user is already a member of this store")
+)
+
+type StoreRole string
+
+const (
+	StoreRoleOwner  StoreRole = "owner"
+	StoreRoleAdmin  StoreRole = "admin"
+	StoreRoleEditor StoreRole = "editor"
+	StoreRoleViewer StoreRole = "viewer"
+)
+
+type StorePermission string
+
+const (
+	PermissionStoreRead       StorePermission = "store:read"
+	PermissionStoreUpdate     StorePermission = "store:update"
+	PermissionStoreDelete     StorePermission = "store:delete"
+	PermissionBillboardsRead  StorePermission = "billboards:read"
+	PermissionBillboardsWrite StorePermission = "billboards:write"
+	PermissionMembersRead     StorePermission = "members:read"
+	PermissionMembersManage   StorePermission = "members:manage"
+	PermissionAPIKeysManage   StorePermission = "apiKeys:manage"
+	PermissionAuditRead       StorePermission = "audit:read"
+)
+
+var rolePermissions = map[StoreRole][]StorePermission{
+	StoreRoleOwner: {
+		PermissionStoreRead, PermissionStoreUpdate, PermissionStoreDelete,
+		PermissionBillboardsRead, PermissionBillboardsWrite,
+		PermissionMembersRead, PermissionMembersManage,
+		PermissionAPIKeysManage, PermissionAuditRead,
+	},
+	StoreRoleAdmin: {
+		PermissionStoreRead, PermissionStoreUpdate,
+		PermissionBillboardsRead, PermissionBillboardsWrite,
+		PermissionMembersRead, PermissionMembersManage,
+		PermissionAPIKeysManage, PermissionAuditRead,
+	},
+	StoreRoleEditor: {
+		PermissionStoreRead,
+		PermissionBillboardsRead, PermissionBillboardsWrite,
+		PermissionMembersRead,
+	},
+	StoreRoleViewer: {
+		PermissionStoreRead,
+		PermissionBillboardsRead,
+		PermissionMembersRead,
+	},
+}
+
+var permissionScopes = map[StorePermission]string{
+	PermissionStoreRead:       ScopeStoresRead,
+	PermissionStoreUpdate:     ScopeStoresWrite,
+	PermissionBillboardsRead:  ScopeBillboardsRead,
+	PermissionBillboardsWrite: ScopeBillboardsWrite,
+}
+
+type StoreMember struct {
+	ID        uuid.UUID `gorm:"type:char(36);primaryKey;column:id"`
+	StoreID   uuid.UUID `gorm:"type:char(36);column:storeId;not null;uniqueIndex:idx_store_member"`
+	Store     Store     `gorm:"foreignKey:StoreID"`
+	UserID    uuid.UUID `gorm:"type:char(36);column:userId;not null;uniqueIndex:idx_store_member;index"`
+	User      User      `gorm:"foreignKey:UserID"`
+	Role      StoreRole `gorm:"size:20;not null;column:role"`
+	CreatedAt time.Time `gorm:"column:createdAt"`
+	UpdatedAt time.Time `gorm:"column:updatedAt"`
+}
+
+type StoreAccess struct {
+	Session *Session
+	Store   *Store
+	Role    StoreRole
+}
+
+type StoreMemberRolePayload struct {
+	Role StoreRole `json:"role" validate:"required,oneof=admin editor viewer"`
+}
+
+type StoreMemberResponse struct {
+	UserID    string    `json:"userId"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	AvatarURL string    `json:"avatarUrl"`
+	Role      StoreRole `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type StoreMemberHandler interface {
+	GetAll(ctx echo.Context) error
+	UpdateRole(ctx echo.Context) error
+	Delete(ctx echo.Context) error
+}
+
+type StoreMemberService interface {
+	GetAll(ctx context.Context, storeID uuid.UUID) ([]*StoreMemberResponse, error)
+	UpdateRole(ctx context.Context, storeID uuid.UUID, userID uuid.UUID, storeMemberRolePayload StoreMemberRolePayload) error
+	Delete(ctx context.Context, storeID uuid.UUID, userID uuid.UUID) error
+}
+
+type StoreMemberRepository interface {
+	Create(ctx context.Context, storeMember StoreMember) error
+	GetAll(ctx context.Context, storeID uuid.UUID) ([]*StoreMember, error)
+	GetAllByUser(ctx context.Context, userID uuid.UUID) ([]*StoreMember, error)
+	GetByStoreAndUser(ctx context.Context, storeID uuid.UUID, userID uuid.UUID) (*StoreMember, error)
+	UpdateRole(ctx context.Context, storeID uuid.UUID, userID uuid.UUID, role StoreRole) error
+	Delete(ctx context.Context, storeID uuid.UUID, userID uuid.UUID) error
+}
+
+type StoreAuthorizer interface {
+	Authorize(ctx context.Context, storeID uuid.UUID, permission StorePermission) (*StoreAccess, error)
+}
+
+func (r StoreRole) Can(permission StorePermission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
+
+func (p StorePermission) Scope() (string, bool) {
+	scope, ok := permissionScopes[p]
+	return scope, ok
+}
+
+func (s *StoreMemberRolePayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(s)
+}
+
+func (s *StoreMember) ToResponse() *StoreMemberResponse {
+	return &StoreMemberResponse{
+		UserID:    s.UserID.String(),
+		Name:      s.User.Name,
+		Email:     s.User.Email,
+		AvatarURL: s.User.AvatarURL,
+		Role:      s.Role,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+func (s *StoreMemberResponse) In(location *time.Location) *StoreMemberResponse {
+	s.CreatedAt = s.CreatedAt.In(location)
+	return s
+}
+
+func (StoreMember) TableName() string {
+	return "StoreMember"
+}
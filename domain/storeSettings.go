@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	DefaultStoreCurrency = "USD"
+	DefaultStoreLocale   = "en-US"
+	DefaultStoreTimezone = "UTC"
+)
+
+type StoreAddress struct {
+	Line1      string `gorm:"size:150;column:addressLine1"`
+	Line2      string `gorm:"size:150;column:addressLine2"`
+	City       string `gorm:"size:100;column:addressCity"`
+	State      string `gorm:"size:100;column:addressState"`
+	PostalCode string `gorm:"size:20;column:addressPostalCode"`
+	Country    string `gorm:"size:2;column:addressCountry"`
+}
+
+type StoreSettings struct {
+	Currency     string       `gorm:"size:3;not null;default:'USD';column:currency"`
+	Locale       string       `gorm:"size:35;not null;default:'en-US';column:locale"`
+	Timezone     string       `gorm:"size:64;not null;default:'UTC';column:timezone"`
+	SupportEmail string       `gorm:"size:100;column:supportEmail"`
+	SupportPhone string       `gorm:"size:20;column:supportPhone"`
+	Address      StoreAddress `gorm:"embedded"`
+	OrderPrefix  string       `gorm:"size:10;column:orderPrefix"`
+}
+
+type StoreAddressPayload struct {
+	Line1      string `json:"line1" validate:"required,max=150"`
+	Line2      string `json:"line2" validate:"max=150"`
+	City       string `json:"city" validate:"required,max=100"`
+	State      string `json:"state" validate:"max=100"`
+	PostalCode string `json:"postalCode" validate:"max=20"`
+	Country    string `json:"country" validate:"required,iso3166_1_alpha2"`
+}
+
+type StoreSettingsPayload struct {
+	Currency     *string              `json:"currency" validate:"required,iso4217"`
+	Locale       *string              `json:"locale" validate:"required,max=35,bcp47_language_tag"`
+	Timezone     *string              `json:"timezone" validate:"required,max=64,timezone"`
+	SupportEmail *string              `json:"supportEmail" validate:"omitempty,email,max=100"`
+	SupportPhone *string              `json:"supportPhone" validate:"omitempty,e164"`
+	Address      *StoreAddressPayload `json:"address"`
+	OrderPrefix  *string              `json:"orderPrefix" validate:"omitempty,alphanum,max=10"`
+}
+
+type StoreAddressResponse struct {
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	PostalCode string `json:"postalCode"`
+	Country    string `json:"country"`
+}
+
+type StoreSettingsResponse struct {
+	Currency     string                `json:"currency"`
+	Locale       string                `json:"locale"`
+	Timezone     string                `json:"timezone"`
+	SupportEmail string                `json:"supportEmail"`
+	SupportPhone string                `json:"supportPhone"`
+	Address      *StoreAddressResponse `json:"address"`
+	OrderPrefix  string                `json:"orderPrefix"`
+}
+
+func DefaultStoreSettings() StoreSettings {
+	return StoreSettings{
+		Currency: DefaultStoreCurrency,
+		Locale:   DefaultStoreLocale,
+		Timezone: DefaultStoreTimezone,
+	}
+}
+
+func (s *StoreSettingsPayload) trim() {
+	for _, field := range []*string{s.Currency, s.Locale, s.Timezone, s.SupportEmail, s.SupportPhone, s.OrderPrefix} {
+		if field != nil {
+			*field = strings.TrimSpace(*field)
+		}
+	}
+
+	if s.Currency != nil {
+		*s.Currency = strings.ToUpper(*s.Currency)
+	}
+
+	if s.OrderPrefix != nil {
+		*s.OrderPrefix = strings.ToUpper(*s.OrderPrefix)
+	}
+
+	if s.Address != nil {
+		s.Address.Line1 = strings.TrimSpace(s.Address.Line1)
+		s.Address.Line2 = strings.TrimSpace(s.Address.Line2)
+		s.Address.City = strings.TrimSpace(s.Address.City)
+		s.Address.State = strings.TrimSpace(s.Address.State)
+		s.Address.PostalCode = strings.TrimSpace(s.Address.PostalCode)
+		s.Address.Country = strings.ToUpper(strings.TrimSpace(s.Address.Country))
+	}
+}
+
+func (s *StoreSettingsPayload) Validate() error {
+	s.trim()
+	validate := validator.New()
+
+	// Currency, locale and timezone can be left out but never cleared, so
+	// they are only skipped when absent. An empty support email, phone or
+	// order prefix clears the setting, so it must not be checked against the
+	// format rules.
+	var skipped []string
+	if s.Currency == nil {
+		skipped = append(skipped, "Currency")
+	}
+
+	if s.Locale == nil {
+		skipped = append(skipped, "Locale")
+	}
+
+	if s.Timezone == nil {
+		skipped = append(skipped, "Timezone")
+	}
+
+	if s.SupportEmail != nil && *s.SupportEmail == "" {
+		skipped = append(skipped, "SupportEmail")
+	}
+
+	if s.SupportPhone != nil && *s.SupportPhone == "" {
+		skipped = append(skipped, "SupportPhone")
+	}
+
+	if s.OrderPrefix != nil && *s.OrderPrefix == "" {
+		skipped = append(skipped, "OrderPrefix")
+	}
+
+	return validate.StructExcept(s, skipped...)
+}
+
+// Apply returns a copy of the settings with the fields present in the
+// payload replaced.
+func (s *StoreSettingsPayload) Apply(storeSettings StoreSettings) StoreSettings {
+	if s.Currency != nil {
+		storeSettings.Currency = *s.Currency
+	}
+
+	if s.Locale != nil {
+		storeSettings.Locale = *s.Locale
+	}
+
+	if s.Timezone != nil {
+		storeSettings.Timezone = *s.Timezone
+	}
+
+	if s.SupportEmail != nil {
+		storeSettings.SupportEmail = *s.SupportEmail
+	}
+
+	if s.SupportPhone != nil {
+		storeSettings.SupportPhone = *s.SupportPhone
+	}
+
+	if s.Address != nil {
+		storeSettings.Address = StoreAddress{
+			Line1:      s.Address.Line1,
+			Line2:      s.Address.Line2,
+			City:       s.Address.City,
+			State:      s.Address.State,
+			PostalCode: s.Address.PostalCode,
+			Country:    s.Address.Country,
+		}
+	}
+
+	if s.OrderPrefix != nil {
+		storeSettings.OrderPrefix = *s.OrderPrefix
+	}
+
+	return storeSettings
+}
+
+// Location resolves the store timezone, falling back to UTC for rows written
+// before the setting existed or holding a zone the host no longer knows.
+func (s *StoreSettings) Location() *time.Location {
+	if s.Timezone == "" {
+		return time.UTC
+	}
+
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return location
+}
+
+func (s *StoreSettings) ToResponse() *StoreSettingsResponse {
+	storeSettingsResponse := &StoreSettingsResponse{
+		Currency:     s.Currency,
+		Locale:       s.Locale,
+		Timezone:     s.Timezone,
+		SupportEmail: s.SupportEmail,
+		SupportPhone: s.SupportPhone,
+		OrderPrefix:  s.OrderPrefix,
+	}
+
+	if s.Address != (StoreAddress{}) {
+		storeSettingsResponse.Address = &StoreAddressResponse{
+			Line1:      s.Address.Line1,
+			Line2:      s.Address.Line2,
+			City:       s.Address.City,
+			State:      s.Address.State,
+			PostalCode: s.Address.PostalCode,
+			Country:    s.Address.Country,
+		}
+	}
+
+	return storeSettingsResponse
+}
@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrStoreSlugInvalid      = errors.New("store slug has an invalid format")
+	ErrStoreSlugReserved     = errors.New("store slug is reserved")
+	ErrStoreSlugAlreadyInUse = errors.New("store slug already in use")
+	ErrStoreSlugIsSame       = errors.New("new store slug is same as current store slug")
+)
+
+const (
+	MinStoreSlugLength = 3
+	MaxStoreSlugLength = 63
+	// storeSlugSuffixLength is the number of random characters appended to a
+	// generated slug when the base one is already taken.
+	storeSlugSuffixLength = 6
+	defaultStoreSlugBase  = "store"
+	maxStoreSlugAttempts  = 5
+)
+
+// Slugs are lowercase letters, digits and single hyphens, so they are valid
+// both as a path segment and as a DNS label.
+var storeSlugPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9]|-[a-z0-9])*$`)
+
+// StoreSlugRedirect keeps a slug a store used before, so old storefront links
+// keep resolving to the same store after a rename.
+type StoreSlugRedirect struct {
+	ID        uuid.UUID `gorm:"type:char(36);primaryKey;column:id"`
+	StoreID   uuid.UUID `gorm:"type:char(36);column:storeId;not null;index"`
+	Slug      string    `gorm:"size:63;not null;uniqueIndex;column:slug"`
+	CreatedAt time.Time `gorm:"column:createdAt"`
+}
+
+// StoreSlugMovedError is returned when a slug only matches a redirect, so the
+// caller can send the client to the current one.
+type StoreSlugMovedError struct {
+	Slug string
+}
+
+type StoreSlugUpdatePayload struct {
+	Slug string `json:"slug" validate:"required,min=3,max=63"`
+}
+
+func (s *StoreSlugMovedError) Error() string {
+	return "store slug moved to " + s.Slug
+}
+
+func (s *StoreSlugUpdatePayload) trim() {
+	s.Slug = NormalizeStoreSlug(s.Slug)
+}
+
+func (s *StoreSlugUpdatePayload) Validate() error {
+	s.trim()
+	validate := validator.New()
+	if err := validate.Struct(s); err != nil {
+		return err
+	}
+
+	return ValidateStoreSlug(s.Slug)
+}
+
+func NormalizeStoreSlug(slug string) string {
+	return strings.ToLower(strings.TrimSpace(slug))
+}
+
+func ValidateStoreSlug(slug string) error {
+	if len(slug) < MinStoreSlugLength || len(slug) > MaxStoreSlugLength || !storeSlugPattern.MatchString(slug) {
+		return ErrStoreSlugInvalid
+	}
+
+	if IsReservedStoreSlug(slug) {
+		return ErrStoreSlugReserved
+	}
+
+	return nil
+}
+
+// IsReservedStoreSlug shares the reserved usernames list since both end up as
+// public path segments next to the API's own routes.
+func IsReservedStoreSlug(slug string) bool {
+	_, ok := reservedUsernames[strings.ReplaceAll(slug, "-", "")]
+	return ok
+}
+
+// StoreSlugCandidate derives a slug from a store name. The first attempt uses
+// the name alone and later attempts append a random suffix, so callers can
+// retry until they find one that is not taken.
+func StoreSlugCandidate(name string, attempt int) string {
+	var builder strings.Builder
+	separator := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if separator && builder.Len() > 0 {
+				builder.WriteByte('-')
+			}
+			builder.WriteRune(r)
+			separator = false
+		default:
+			separator = true
+		}
+	}
+
+	base := builder.String()
+	maxBaseLength := MaxStoreSlugLength - storeSlugSuffixLength - 1
+	if len(base) > maxBaseLength {
+		base = strings.TrimRight(base[:maxBaseLength], "-")
+	}
+
+	if len(base) < MinStoreSlugLength || IsReservedStoreSlug(base) {
+		base = defaultStoreSlugBase
+		if attempt == 0 {
+			attempt = 1
+		}
+	}
+
+	if attempt == 0 {
+		return base
+	}
+
+	suffix := strings.ReplaceAll(uuid.New().String(), "-", "")[:storeSlugSuffixLength]
+	return base + "-" + suffix
+}
+
+// GenerateStoreSlug returns the first candidate for name that taken reports
+// as free.
+func GenerateStoreSlug(name string, taken func(slug string) (bool, error)) (string, error) {
+	for attempt := 0; attempt < maxStoreSlugAttempts; attempt++ {
+		slug := StoreSlugCandidate(name, attempt)
+
+		exists, err := taken(slug)
+		if err != nil {
+			return "", err
+		}
+
+		if !exists {
+			return slug, nil
+		}
+	}
+
+	return "", ErrStoreSlugAlreadyInUse
+}
+
+func (StoreSlugRedirect) TableName() string {
+	return "StoreSlugRedirect"
+}
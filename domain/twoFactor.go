@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrTwoFactorRequired         = errors.New("two factor authentication required")
+	ErrTwoFactorAlreadyEnabled   = errors.New("two factor authentication already enabled")
+	ErrTwoFactorNotEnabled       = errors.New("two factor authentication not enabled")
+	ErrTwoFactorNotEnrolled      = errors.New("two factor enrollment not started")
+	ErrTwoFactorCodeInvalid      = errors.New("invalid two factor code")
+	ErrTwoFactorChallengeInvalid = errors.New("invalid or expired two factor challenge")
+)
+
+type RecoveryCode struct {
+	ID        uuid.UUID  `gorm:"type:char(36);primaryKey;column:id"`
+	UserID    uuid.UUID  `gorm:"type:char(36);column:userId;not null;index"`
+	User      User       `gorm:"foreignKey:UserID"`
+	CodeHash  string     `gorm:"size:64;not null;column:codeHash"`
+	UsedAt    *time.Time `gorm:"column:usedAt"`
+	CreatedAt time.Time  `gorm:"column:createdAt"`
+}
+
+type TwoFactorCodePayload struct {
+	Code string `json:"code" validate:"required,min=6,max=20"`
+}
+
+type TwoFactorSignInPayload struct {
+	ChallengeToken string `json:"challengeToken" validate:"required"`
+	Code           string `json:"code" validate:"required,min=6,max=20"`
+}
+
+type TwoFactorEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauthUrl"`
+	QRCode     string `json:"qrCode"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type TwoFactorHandler interface {
+	Enroll(ctx echo.Context) error
+	Confirm(ctx echo.Context) error
+	Disable(ctx echo.Context) error
+	SignIn(ctx echo.Context) error
+}
+
+type TwoFactorService interface {
+	Enroll(ctx context.Context) (*TwoFactorEnrollResponse, error)
+	Confirm(ctx context.Context, twoFactorCodePayload TwoFactorCodePayload) (*RecoveryCodesResponse, error)
+	Disable(ctx context.Context, twoFactorCodePayload TwoFactorCodePayload) error
+	SignIn(ctx context.Context, twoFactorSignInPayload TwoFactorSignInPayload) (*SessionResponse, error)
+}
+
+type TwoFactorRepository interface {
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, recoveryCodes []RecoveryCode) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+	DeleteRecoveryCodes(ctx context.Context, userID uuid.UUID) error
+	UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+}
+
+func (t *TwoFactorCodePayload) trim() {
+	t.Code = strings.TrimSpace(t.Code)
+}
+
+func (t *TwoFactorSignInPayload) trim() {
+	t.ChallengeToken = strings.TrimSpace(t.ChallengeToken)
+	t.Code = strings.TrimSpace(t.Code)
+}
+
+func (t *TwoFactorCodePayload) Validate() error {
+	t.trim()
+	validate := validator.New()
+	return validate.Struct(t)
+}
+
+func (t *TwoFactorSignInPayload) Validate() error {
+	t.trim()
+	validate := validator.New()
+	return validate.Struct(t)
+}
+
+func (RecoveryCode) TableName() string {
+	return "RecoveryCode"
+}
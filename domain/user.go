@@ -3,9 +3,11 @@ package domain
 import (
 	"context"
 	"errors"
+	"mime/multipart"
 	"strings"
 	"time"
 
+	"github.com/GSVillas/e-commercer-api/secure"
 	"github.com/GSVillas/e-commercer-api/util"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -14,47 +16,78 @@ import (
 )
 
 var (
-	ErrUserNotFound          = errors.New("user not found")
-	ErrUserAlreadyExists     = errors.New("user already exists")
-	ErrHashingPassword       = errors.New("failed to hash password")
-	ErrInvalidPassword       = errors.New("invalid password")
-	ErrEmailNotConfirmed     = errors.New("email not confirmed")
-	ErrNameIsSame            = errors.New("name is same as oldName")
-	ErrUserNotFoundInContext = errors.New("user not found in context")
-	ErrInvalidOldPassword    = errors.New("invalid old password")
-	ErrPasswordIsSame        = errors.New("new password is same as old password")
-	ErrEmailAlreadyConfirmed = errors.New("email already confirmed")
-	ErrOTPExpires            = errors.New("otp expires")
+	ErrUserNotFound             = errors.New("user not found")
+	ErrUserAlreadyExists        = errors.New("user already exists")
+	ErrHashingPassword          = errors.New("failed to hash password")
+	ErrInvalidPassword          = errors.New("invalid password")
+	ErrEmailNotConfirmed        = errors.New("email not confirmed")
+	ErrNameIsSame               = errors.New("name is same as oldName")
+	ErrUserNotFoundInContext    = errors.New("user not found in context")
+	ErrInvalidOldPassword       = errors.New("invalid old password")
+	ErrPasswordIsSame           = errors.New("new password is same as old password")
+	ErrEmailAlreadyConfirmed    = errors.New("email already confirmed")
+	ErrOTPExpires               = errors.New("otp expires")
+	ErrEmailAlreadyInUse        = errors.New("email already in use")
+	ErrEmailIsSame              = errors.New("new email is same as current email")
+	ErrEmailChangeNotFound      = errors.New("no pending email change")
+	ErrEmailChangeCancelInvalid = errors.New("invalid or expired email change cancel token")
+	ErrAvatarNotFound           = errors.New("user has no avatar")
+	ErrAvatarTooLarge           = errors.New("avatar file is too large")
+	ErrUserSuspended            = errors.New("user is suspended")
+)
+
+const maxAvatarSize = 5 << 20
+
+type UserRole string
+
+const (
+	UserRoleUser       UserRole = "user"
+	UserRoleSuperAdmin UserRole = "superadmin"
 )
 
 type User struct {
-	ID             uuid.UUID      `gorm:"type:char(36);primaryKey;column:id"`
-	Name           string         `gorm:"size:100;not null;column:name"`
-	Username       string         `gorm:"uniqueIndex;size:100;not null;column:username"`
-	Email          string         `gorm:"uniqueIndex;size:100;not null;column:email"`
-	PasswordHash   string         `gorm:"size:255;not null;column:passwordHash"`
-	EmailConfirmed bool           `gorm:"not null;default:false;column:emailConfirmed"`
-	AvatarURL      string         `gorm:"size:255;column:AvatarUrl"`
-	CreatedAt      time.Time      `gorm:"column:createdAt"`
-	UpdatedAt      time.Time      `gorm:"column:updatedAt"`
-	DeletedAt      gorm.DeletedAt `gorm:"index;column:deletedAt"`
+	ID                    uuid.UUID      `gorm:"type:char(36);primaryKey;column:id"`
+	Name                  string         `gorm:"size:100;not null;column:name"`
+	Username              string         `gorm:"uniqueIndex;size:100;not null;column:username"`
+	Email                 string         `gorm:"uniqueIndex;size:100;not null;column:email"`
+	PasswordHash          string         `gorm:"size:255;not null;column:passwordHash"`
+	EmailConfirmed        bool           `gorm:"not null;default:false;column:emailConfirmed"`
+	AvatarURL             string         `gorm:"size:255;column:AvatarUrl"`
+	TwoFactorEnabled      bool           `gorm:"not null;default:false;column:twoFactorEnabled"`
+	TwoFactorSecret       string         `gorm:"size:128;column:twoFactorSecret"`
+	Role                  UserRole       `gorm:"size:20;not null;default:'user';column:role"`
+	SuspendedAt           *time.Time     `gorm:"column:suspendedAt"`
+	PasswordResetRequired bool           `gorm:"not null;default:false;column:passwordResetRequired"`
+	CreatedAt             time.Time      `gorm:"column:createdAt"`
+	UpdatedAt             time.Time      `gorm:"column:updatedAt"`
+	DeletedAt             gorm.DeletedAt `gorm:"index;column:deletedAt"`
 }
 
 type UserPayLoad struct {
 	Name            string `json:"name" validate:"required,min=1,max=75"`
+	Username        string `json:"username" validate:"omitempty,min=3,max=30"`
 	Email           string `json:"email" validate:"required,email"`
 	ConfirmEmail    string `json:"confirmEmail" validate:"required,email,eqfield=Email"`
-	Password        string `json:"password,omitempty" validate:"required,min=8,max=255,containsany=!@#&?"`
+	Password        string `json:"password,omitempty" validate:"required"`
 	ConfirmPassword string `json:"confirmPassword" validate:"required,eqfield=Password"`
 }
 
 type UserResponse struct {
 	ID        string `json:"id"`
 	Name      string `json:"name"`
+	Username  string `json:"username"`
 	Email     string `json:"email"`
 	AvatarURL string `json:"avatarUrl"`
 }
 
+type PublicProfileResponse struct {
+	Username  string                 `json:"username"`
+	Name      string                 `json:"name"`
+	AvatarURL string                 `json:"avatarUrl"`
+	CreatedAt string                 `json:"createdAt"`
+	Stores    []*PublicStoreResponse `json:"stores"`
+}
+
 type SignInPayLoad struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
@@ -64,9 +97,13 @@ type UpdateNamePayload struct {
 	Name string `json:"name" validate:"required,min=1,max=100"`
 }
 
+type UpdateUsernamePayload struct {
+	Username string `json:"username" validate:"required,min=3,max=30"`
+}
+
 type UpdatePasswordPayload struct {
 	OldPassword     string `json:"oldPassword" validate:"required"`
-	NewPassword     string `json:"newPassword" validate:"required,min=8,max=255,containsany=!@#&?"`
+	NewPassword     string `json:"newPassword" validate:"required"`
 	ConfirmPassword string `json:"confirmPassword" validate:"required,eqfield=NewPassword"`
 }
 
@@ -78,38 +115,109 @@ type ConfirmEmailPayload struct {
 	OTP string `json:"otp" validate:"required,numeric,min=6,max=6"`
 }
 
+type ForgotPasswordPayload struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordPayload struct {
+	Email           string `json:"email" validate:"required,email"`
+	OTP             string `json:"otp" validate:"required,numeric,min=6,max=6"`
+	NewPassword     string `json:"newPassword" validate:"required"`
+	ConfirmPassword string `json:"confirmPassword" validate:"required,eqfield=NewPassword"`
+}
+
+type ChangeEmailPayload struct {
+	NewEmail string `json:"newEmail" validate:"required,email,max=100"`
+	Password string `json:"password" validate:"required"`
+}
+
+type ConfirmEmailChangePayload struct {
+	OTP string `json:"otp" validate:"required,numeric,min=6,max=6"`
+}
+
+type CancelEmailChangePayload struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type UpdateAvatarPayload struct {
+	Avatar *multipart.FileHeader `json:"avatar" validate:"required"`
+}
+
+type EmailChange struct {
+	UserID   uuid.UUID
+	OldEmail string
+	NewEmail string
+}
+
 type UserHandler interface {
 	Create(ctx echo.Context) error
 	SignIn(ctx echo.Context) error
 	UpdateName(ctx echo.Context) error
+	UpdateUsername(ctx echo.Context) error
 	UpdatePassword(ctx echo.Context) error
 	GetUserInfo(ctx echo.Context) error
+	GetProfile(ctx echo.Context) error
 	ResendCode(ctx echo.Context) error
 	ConfirmEmail(ctx echo.Context) error
+	RefreshToken(ctx echo.Context) error
+	SignOut(ctx echo.Context) error
+	ForgotPassword(ctx echo.Context) error
+	ResetPassword(ctx echo.Context) error
+	ChangeEmail(ctx echo.Context) error
+	ConfirmEmailChange(ctx echo.Context) error
+	CancelEmailChange(ctx echo.Context) error
+	UpdateAvatar(ctx echo.Context) error
+	DeleteAvatar(ctx echo.Context) error
 }
 
 type UserService interface {
 	Create(ctx context.Context, user UserPayLoad) error
 	SignIn(ctx context.Context, signInPayload SignInPayLoad) (*SessionResponse, error)
 	UpdateName(ctx context.Context, name string) error
+	UpdateUsername(ctx context.Context, updateUsernamePayload UpdateUsernamePayload) error
 	UpdatePassword(ctx context.Context, updatePasswordPayload UpdatePasswordPayload) error
 	GetUserInfo(ctx context.Context) (*UserResponse, error)
+	GetProfile(ctx context.Context, username string) (*PublicProfileResponse, error)
 	ResendCode(ctx context.Context, resendCodePayload ResendCodePayload) error
 	ConfirmEmail(ctx context.Context, confirmEmailPayload ConfirmEmailPayload) error
 	CheckStatus(ctx context.Context) error
+	RefreshToken(ctx context.Context, refreshTokenPayload RefreshTokenPayload) (*SessionResponse, error)
+	SignOut(ctx context.Context) error
+	ForgotPassword(ctx context.Context, forgotPasswordPayload ForgotPasswordPayload) error
+	ResetPassword(ctx context.Context, resetPasswordPayload ResetPasswordPayload) error
+	ChangeEmail(ctx context.Context, changeEmailPayload ChangeEmailPayload) error
+	ConfirmEmailChange(ctx context.Context, confirmEmailChangePayload ConfirmEmailChangePayload) error
+	CancelEmailChange(ctx context.Context, cancelEmailChangePayload CancelEmailChangePayload) error
+	UpdateAvatar(ctx context.Context, updateAvatarPayload UpdateAvatarPayload) (*UserResponse, error)
+	DeleteAvatar(ctx context.Context) error
 }
 
 type UserRepository interface {
 	Create(ctx context.Context, user User) error
 	GetByEmail(ctx context.Context, email string) (*User, error)
+	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	GetByUsername(ctx context.Context, username string) (*User, error)
+	ExistsByUsername(ctx context.Context, username string) (bool, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*User, error)
 	UpdateName(ctx context.Context, id uuid.UUID, name string) error
+	UpdateUsername(ctx context.Context, id uuid.UUID, username string) error
 	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error
 	UpdateConfirmEmail(ctx context.Context, id uuid.UUID) error
+	ReclaimUnconfirmed(ctx context.Context, id uuid.UUID, passwordHash string) error
+	UpdatePasswordResetRequired(ctx context.Context, id uuid.UUID, required bool) error
+	UpdateTwoFactor(ctx context.Context, id uuid.UUID, secret string, enabled bool) error
+	UpdateEmail(ctx context.Context, id uuid.UUID, email string) error
+	UpdateAvatar(ctx context.Context, id uuid.UUID, avatarURL string) error
+	SaveEmailChange(ctx context.Context, emailChange EmailChange) error
+	GetEmailChange(ctx context.Context, userID uuid.UUID) (*EmailChange, error)
+	DeleteEmailChange(ctx context.Context, userID uuid.UUID) error
+	SaveEmailChangeCancelToken(ctx context.Context, tokenHash string, emailChange EmailChange) error
+	GetEmailChangeCancelToken(ctx context.Context, tokenHash string) (*EmailChange, error)
 }
 
 func (u *UserPayLoad) trim() {
 	u.Name = strings.TrimSpace(u.Name)
+	u.Username = NormalizeUsername(u.Username)
 	u.Email = strings.TrimSpace(u.Email)
 	u.ConfirmEmail = strings.TrimSpace(u.ConfirmEmail)
 }
@@ -122,14 +230,49 @@ func (u *UpdateNamePayload) trim() {
 	u.Name = strings.TrimSpace(u.Name)
 }
 
+func (u *UpdateUsernamePayload) trim() {
+	u.Username = NormalizeUsername(u.Username)
+}
+
 func (r *ResendCodePayload) trim() {
 	r.Email = strings.TrimSpace(r.Email)
 }
 
-func (u *UserPayLoad) Validate() error {
+func (f *ForgotPasswordPayload) trim() {
+	f.Email = strings.TrimSpace(f.Email)
+}
+
+func (r *ResetPasswordPayload) trim() {
+	r.Email = strings.TrimSpace(r.Email)
+	r.OTP = strings.TrimSpace(r.OTP)
+}
+
+func (c *ChangeEmailPayload) trim() {
+	c.NewEmail = strings.TrimSpace(c.NewEmail)
+}
+
+func (c *ConfirmEmailChangePayload) trim() {
+	c.OTP = strings.TrimSpace(c.OTP)
+}
+
+func (c *CancelEmailChangePayload) trim() {
+	c.Token = strings.TrimSpace(c.Token)
+}
+
+func (u *UserPayLoad) Validate(passwordPolicy *secure.PasswordPolicy) error {
 	u.trim()
 	validate := validator.New()
-	return validate.Struct(u)
+	if err := validate.Struct(u); err != nil {
+		return err
+	}
+
+	if u.Username != "" {
+		if err := ValidateUsername(u.Username); err != nil {
+			return err
+		}
+	}
+
+	return checkPasswordPolicy(passwordPolicy, "password", u.Password, u.Email, u.Name, u.Username)
 }
 
 func (s *SignInPayLoad) Validate() error {
@@ -138,9 +281,13 @@ func (s *SignInPayLoad) Validate() error {
 	return validate.Struct(s)
 }
 
-func (u *UpdatePasswordPayload) Validate() error {
+func (u *UpdatePasswordPayload) Validate(passwordPolicy *secure.PasswordPolicy) error {
 	validate := validator.New()
-	return validate.Struct(u)
+	if err := validate.Struct(u); err != nil {
+		return err
+	}
+
+	return checkPasswordPolicy(passwordPolicy, "newPassword", u.NewPassword, u.OldPassword)
 }
 
 func (u *UpdateNamePayload) Validate() error {
@@ -149,6 +296,16 @@ func (u *UpdateNamePayload) Validate() error {
 	return validate.Struct(u)
 }
 
+func (u *UpdateUsernamePayload) Validate() error {
+	u.trim()
+	validate := validator.New()
+	if err := validate.Struct(u); err != nil {
+		return err
+	}
+
+	return ValidateUsername(u.Username)
+}
+
 func (r *ResendCodePayload) Validate() error {
 	r.trim()
 	validate := validator.New()
@@ -165,13 +322,75 @@ func (u *ConfirmEmailPayload) Validate() error {
 	return validate.Struct(u)
 }
 
+func (f *ForgotPasswordPayload) Validate() error {
+	f.trim()
+	validate := validator.New()
+	return validate.Struct(f)
+}
+
+func (r *ResetPasswordPayload) Validate(passwordPolicy *secure.PasswordPolicy) error {
+	r.trim()
+	validate := validator.New()
+	err := validate.RegisterValidation("numeric", util.IsNumeric)
+	if err != nil {
+		return err
+	}
+
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+
+	return checkPasswordPolicy(passwordPolicy, "newPassword", r.NewPassword, r.Email)
+}
+
+func (c *ChangeEmailPayload) Validate() error {
+	c.trim()
+	validate := validator.New()
+	return validate.Struct(c)
+}
+
+func (c *ConfirmEmailChangePayload) Validate() error {
+	c.trim()
+	validate := validator.New()
+	err := validate.RegisterValidation("numeric", util.IsNumeric)
+	if err != nil {
+		return err
+	}
+
+	return validate.Struct(c)
+}
+
+func (c *CancelEmailChangePayload) Validate() error {
+	c.trim()
+	validate := validator.New()
+	return validate.Struct(c)
+}
+
+func (u *UpdateAvatarPayload) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(u); err != nil {
+		return err
+	}
+
+	if err := util.ValidateFile(u.Avatar); err != nil {
+		return err
+	}
+
+	if u.Avatar.Size > maxAvatarSize {
+		return ErrAvatarTooLarge
+	}
+
+	return nil
+}
+
 func (u *UserPayLoad) ToUser(passwordHash string) *User {
 	return &User{
 		ID:           uuid.New(),
 		Name:         u.Name,
 		Email:        u.Email,
-		Username:     u.Email,
+		Username:     u.Username,
 		PasswordHash: passwordHash,
+		Role:         UserRoleUser,
 		CreatedAt:    time.Now().UTC(),
 	}
 }
@@ -180,11 +399,32 @@ func (u *User) ToResponse() *UserResponse {
 	return &UserResponse{
 		ID:        u.ID.String(),
 		Name:      u.Name,
+		Username:  u.Username,
 		Email:     u.Email,
 		AvatarURL: u.AvatarURL,
 	}
 }
 
+func (u *User) ToPublicProfileResponse(stores []*Store) *PublicProfileResponse {
+	publicProfileResponse := &PublicProfileResponse{
+		Username:  u.Username,
+		Name:      u.Name,
+		AvatarURL: u.AvatarURL,
+		CreatedAt: u.CreatedAt.Format(time.RFC3339),
+		Stores:    make([]*PublicStoreResponse, 0, len(stores)),
+	}
+
+	for _, store := range stores {
+		publicProfileResponse.Stores = append(publicProfileResponse.Stores, store.ToPublicResponse())
+	}
+
+	return publicProfileResponse
+}
+
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
+}
+
 func (User) TableName() string {
 	return "User"
 }
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUsernameInvalid      = errors.New("username has an invalid format")
+	ErrUsernameReserved     = errors.New("username is reserved")
+	ErrUsernameAlreadyInUse = errors.New("username already in use")
+	ErrUsernameIsSame       = errors.New("new username is same as current username")
+)
+
+const (
+	MinUsernameLength = 3
+	MaxUsernameLength = 30
+	// usernameSuffixLength is the number of random characters appended to a
+	// generated handle when the base one is already taken.
+	usernameSuffixLength = 6
+	defaultUsernameBase  = "user"
+	maxUsernameAttempts  = 5
+)
+
+// Handles are lowercase letters, digits, dots and underscores, must start and
+// end with a letter or digit and cannot contain consecutive separators.
+var usernamePattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9]|[._][a-z0-9])*$`)
+
+var reservedUsernames = map[string]struct{}{
+	"about": {}, "account": {}, "accounts": {}, "admin": {}, "administrator": {}, "api": {},
+	"app": {}, "assets": {}, "auth": {}, "billing": {}, "billboard": {}, "billboards": {},
+	"blog": {}, "checkout": {}, "contact": {}, "dashboard": {}, "delete": {}, "docs": {},
+	"help": {}, "home": {}, "invite": {}, "invites": {}, "login": {}, "logins": {},
+	"logout": {}, "mail": {}, "me": {}, "moderator": {}, "null": {}, "oidc": {},
+	"owner": {}, "privacy": {}, "profile": {}, "profiles": {}, "public": {}, "root": {},
+	"security": {}, "settings": {}, "signin": {}, "signout": {}, "signup": {}, "staff": {},
+	"static": {}, "status": {}, "store": {}, "stores": {}, "superadmin": {}, "support": {},
+	"system": {}, "terms": {}, "undefined": {}, "user": {}, "username": {}, "users": {},
+	"webmaster": {}, "www": {},
+}
+
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+func ValidateUsername(username string) error {
+	if len(username) < MinUsernameLength || len(username) > MaxUsernameLength || !usernamePattern.MatchString(username) {
+		return ErrUsernameInvalid
+	}
+
+	if IsReservedUsername(username) {
+		return ErrUsernameReserved
+	}
+
+	return nil
+}
+
+func IsReservedUsername(username string) bool {
+	_, ok := reservedUsernames[strings.ReplaceAll(strings.ReplaceAll(username, ".", ""), "_", "")]
+	return ok
+}
+
+// UsernameCandidate derives a handle from a display name. The first attempt
+// uses the name alone and later attempts append a random suffix, so callers
+// can retry until they find one that is not taken. Emails are never used as a
+// seed because handles are public.
+func UsernameCandidate(name string, attempt int) string {
+	var builder strings.Builder
+	separator := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if separator && builder.Len() > 0 {
+				builder.WriteByte('_')
+			}
+			builder.WriteRune(r)
+			separator = false
+		default:
+			separator = true
+		}
+	}
+
+	base := builder.String()
+	maxBaseLength := MaxUsernameLength - usernameSuffixLength - 1
+	if len(base) > maxBaseLength {
+		base = strings.TrimRight(base[:maxBaseLength], "_")
+	}
+
+	if len(base) < MinUsernameLength || IsReservedUsername(base) {
+		base = defaultUsernameBase
+		if attempt == 0 {
+			attempt = 1
+		}
+	}
+
+	if attempt == 0 {
+		return base
+	}
+
+	suffix := strings.ReplaceAll(uuid.New().String(), "-", "")[:usernameSuffixLength]
+	return base + "_" + suffix
+}
+
+// GenerateUsername returns the first candidate for name that taken reports as
+// free. Callers decide what counts as taken, e.g. whether handles of deleted
+// accounts stay reserved.
+func GenerateUsername(name string, taken func(username string) (bool, error)) (string, error) {
+	for attempt := 0; attempt < maxUsernameAttempts; attempt++ {
+		username := UsernameCandidate(name, attempt)
+
+		exists, err := taken(username)
+		if err != nil {
+			return "", err
+		}
+
+		if !exists {
+			return username, nil
+		}
+	}
+
+	return "", ErrUsernameAlreadyInUse
+}
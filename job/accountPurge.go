@@ -0,0 +1,33 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/samber/do"
+)
+
+const accountPurgeInterval = time.Hour
+
+func StartAccountPurge(i *do.Injector) {
+	accountService := do.MustInvoke[domain.AccountService](i)
+
+	go func() {
+		ticker := time.NewTicker(accountPurgeInterval)
+		defer ticker.Stop()
+
+		for ; ; <-ticker.C {
+			log := slog.With(
+				slog.String("job", "accountPurge"),
+			)
+
+			ctx, cancel := context.WithTimeout(context.Background(), accountPurgeInterval/2)
+			if _, err := accountService.PurgeDeleted(ctx); err != nil {
+				log.Error("Failed to purge deleted accounts", slog.String("error", err.Error()))
+			}
+			cancel()
+		}
+	}()
+}
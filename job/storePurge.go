@@ -0,0 +1,33 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/samber/do"
+)
+
+const storePurgeInterval = time.Hour
+
+func StartStorePurge(i *do.Injector) {
+	storeService := do.MustInvoke[domain.StoreService](i)
+
+	go func() {
+		ticker := time.NewTicker(storePurgeInterval)
+		defer ticker.Stop()
+
+		for ; ; <-ticker.C {
+			log := slog.With(
+				slog.String("job", "storePurge"),
+			)
+
+			ctx, cancel := context.WithTimeout(context.Background(), storePurgeInterval/2)
+			if _, err := storeService.PurgeDeleted(ctx); err != nil {
+				log.Error("Failed to purge deleted stores", slog.String("error", err.Error()))
+			}
+			cancel()
+		}
+	}()
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/GSVillas/e-commercer-api/api/handler"
 	"github.com/GSVillas/e-commercer-api/client"
 	"github.com/GSVillas/e-commercer-api/config"
 	"github.com/GSVillas/e-commercer-api/config/database"
+	"github.com/GSVillas/e-commercer-api/job"
+	Middleware "github.com/GSVillas/e-commercer-api/middleware"
 	"github.com/GSVillas/e-commercer-api/repository"
 	"github.com/GSVillas/e-commercer-api/service"
 	"github.com/go-redis/redis/v8"
@@ -23,13 +26,24 @@ import (
 func main() {
 	config.ConfigureLogger()
 	config.LoadEnvironments()
+	config.WatchKeyRing()
 
 	e := echo.New()
 	i := do.New()
 
+	ipExtractor, err := Middleware.IPExtractor()
+	if err != nil {
+		log.Fatal("Fail to configure client ip extraction: ", err)
+	}
+	e.IPExtractor = ipExtractor
+
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{config.Env.URLFront},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		Skipper: func(ctx echo.Context) bool {
+			return strings.HasPrefix(ctx.Request().URL.Path, Middleware.PublicPathPrefix)
+		},
+		AllowOrigins:  []string{config.Env.URLFront},
+		AllowHeaders:  []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, echo.HeaderXRequestID, "X-Device-Name"},
+		ExposeHeaders: []string{echo.HeaderXRequestID},
 	}))
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -60,14 +74,57 @@ func main() {
 	})
 
 	do.Provide(i, handler.NewHealthCheckHandler)
+	do.Provide(i, handler.NewJWKSHandler)
 	do.Provide(i, handler.NewUserHandler)
 	do.Provide(i, service.NewUserService)
 	do.Provide(i, repository.NewUserRepository)
+	do.Provide(i, handler.NewAccountHandler)
+	do.Provide(i, service.NewAccountService)
+	do.Provide(i, repository.NewAccountRepository)
+	do.Provide(i, handler.NewAttemptHandler)
+	do.Provide(i, service.NewAttemptService)
+	do.Provide(i, repository.NewAttemptRepository)
+	do.Provide(i, handler.NewSessionHandler)
 	do.Provide(i, service.NewSessionService)
 	do.Provide(i, repository.NewSessionRepository)
+	do.Provide(i, handler.NewTwoFactorHandler)
+	do.Provide(i, service.NewTwoFactorService)
+	do.Provide(i, repository.NewTwoFactorRepository)
+	do.Provide(i, handler.NewIdentityHandler)
+	do.Provide(i, service.NewIdentityService)
+	do.Provide(i, repository.NewIdentityRepository)
+	do.Provide(i, handler.NewMagicLinkHandler)
+	do.Provide(i, service.NewMagicLinkService)
+	do.Provide(i, repository.NewMagicLinkRepository)
+	do.Provide(i, handler.NewLoginHistoryHandler)
+	do.Provide(i, service.NewLoginHistoryService)
+	do.Provide(i, repository.NewLoginHistoryRepository)
+	do.Provide(i, client.NewOIDCClient)
 	do.Provide(i, handler.NewStoreHandler)
 	do.Provide(i, service.NewStoreService)
 	do.Provide(i, repository.NewStoreRepository)
+	do.Provide(i, handler.NewStoreMemberHandler)
+	do.Provide(i, service.NewStoreMemberService)
+	do.Provide(i, repository.NewStoreMemberRepository)
+	do.Provide(i, handler.NewStoreInviteHandler)
+	do.Provide(i, service.NewStoreInviteService)
+	do.Provide(i, repository.NewStoreInviteRepository)
+	do.Provide(i, handler.NewStoreDomainHandler)
+	do.Provide(i, service.NewStoreDomainService)
+	do.Provide(i, repository.NewStoreDomainRepository)
+	do.Provide(i, client.NewDNSResolver)
+	do.Provide(i, handler.NewPublicStoreHandler)
+	do.Provide(i, service.NewPublicStoreService)
+	do.Provide(i, handler.NewAdminHandler)
+	do.Provide(i, service.NewAdminService)
+	do.Provide(i, repository.NewAdminRepository)
+	do.Provide(i, handler.NewAuditHandler)
+	do.Provide(i, service.NewAuditService)
+	do.Provide(i, repository.NewAuditRepository)
+	do.Provide(i, service.NewStoreAuthorizer)
+	do.Provide(i, handler.NewAPIKeyHandler)
+	do.Provide(i, service.NewAPIKeyService)
+	do.Provide(i, repository.NewAPIKeyRepository)
 	do.Provide(i, service.NewEmailService)
 	do.Provide(i, client.NewCloudFlareService)
 	do.Provide(i, handler.NewBillboardHandler)
@@ -75,5 +132,7 @@ func main() {
 	do.Provide(i, repository.NewBillboardRepository)
 
 	handler.SetupRoutes(e, i)
+	job.StartAccountPurge(i)
+	job.StartStorePurge(i)
 	e.Logger.Fatal(e.Start(fmt.Sprintf(":%s", config.Env.APIPort)))
 }
@@ -2,21 +2,67 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/GSVillas/e-commercer-api/config"
 	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/util"
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
 	"github.com/meysamhadeli/problem-details"
 	"github.com/samber/do"
 )
 
 type contextKey string
 
-const UserKey contextKey = "user"
+// PublicPathPrefix groups the unauthenticated storefront routes, which have
+// their own CORS policy instead of the dashboard one.
+const PublicPathPrefix = "/v1/public"
+
+const (
+	UserKey       contextKey = "user"
+	ClientInfoKey contextKey = "clientInfo"
+	AdminKeyKey   contextKey = "adminKey"
+	StoreKey      contextKey = "store"
+)
+
+func ClientInfo() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			userAgent := ctx.Request().UserAgent()
+
+			device := strings.TrimSpace(ctx.Request().Header.Get("X-Device-Name"))
+			if device == "" {
+				device = util.DeviceFromUserAgent(userAgent)
+			}
+
+			requestID := strings.TrimSpace(ctx.Request().Header.Get(echo.HeaderXRequestID))
+			if requestID == "" || len(requestID) > 64 {
+				requestID = uuid.New().String()
+			}
+			ctx.Response().Header().Set(echo.HeaderXRequestID, requestID)
+
+			clientInfo := &domain.ClientInfo{
+				IP:        ctx.RealIP(),
+				UserAgent: userAgent,
+				Device:    device,
+				RequestID: requestID,
+			}
+
+			ctx.SetRequest(ctx.Request().WithContext(context.WithValue(ctx.Request().Context(), ClientInfoKey, clientInfo)))
+			return next(ctx)
+		}
+	}
+}
 
 func CheckLoggedIn(i *do.Injector) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
@@ -41,12 +87,16 @@ func CheckLoggedIn(i *do.Injector) echo.MiddlewareFunc {
 				})
 			}
 
-			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodECDSA); !ok {
-					return nil, domain.ErrorUnexpectedMethod
-				}
-				return config.Env.PublicKey, nil
-			})
+			token, err := jwt.Parse(tokenString, config.Env.KeyRing.Keyfunc)
+
+			var validationError *jwt.ValidationError
+			if errors.As(err, &validationError) && validationError.Errors&jwt.ValidationErrorExpired != 0 {
+				return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+					Status: http.StatusUnauthorized,
+					Title:  "Token Expired",
+					Detail: "Your access token has expired. Please refresh it or log in again.",
+				})
+			}
 
 			if err != nil || !token.Valid {
 				return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
@@ -58,6 +108,14 @@ func CheckLoggedIn(i *do.Injector) echo.MiddlewareFunc {
 
 			user, err := userSession.GetUser(ctx.Request().Context(), tokenString)
 			if err != nil {
+				if errors.Is(err, domain.ErrUserSuspended) {
+					return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+						Status: http.StatusForbidden,
+						Title:  "Account Suspended",
+						Detail: "Your account has been suspended. Please contact support.",
+					})
+				}
+
 				if errors.Is(err, domain.ErrSessionNotFound) {
 					return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
 						Status: http.StatusUnauthorized,
@@ -79,6 +137,253 @@ func CheckLoggedIn(i *do.Injector) echo.MiddlewareFunc {
 	}
 }
 
+func CheckLoggedInOrAPIKey(i *do.Injector) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		checkLoggedIn := CheckLoggedIn(i)(next)
+
+		return func(ctx echo.Context) error {
+			authorizationHeader := ctx.Request().Header.Get("Authorization")
+
+			scheme, key, found := strings.Cut(authorizationHeader, " ")
+			if !found || !strings.EqualFold(scheme, "ApiKey") {
+				return checkLoggedIn(ctx)
+			}
+
+			apiKeyService := do.MustInvoke[domain.APIKeyService](i)
+
+			session, err := apiKeyService.Authenticate(ctx.Request().Context(), strings.TrimSpace(key))
+			if err != nil {
+				if errors.Is(err, domain.ErrUserSuspended) {
+					return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+						Status: http.StatusForbidden,
+						Title:  "Account Suspended",
+						Detail: "The account that created this api key has been suspended.",
+					})
+				}
+
+				if errors.Is(err, domain.ErrAPIKeyInvalid) {
+					return ctx.JSON(http.StatusUnauthorized, &problem.ProblemDetail{
+						Status: http.StatusUnauthorized,
+						Title:  "Invalid API Key",
+						Detail: "The api key provided is invalid or has been revoked.",
+					})
+				}
+
+				return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+					Status: http.StatusInternalServerError,
+					Title:  "Internal Server Error",
+					Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+				})
+			}
+
+			ctx.SetRequest(ctx.Request().WithContext(context.WithValue(ctx.Request().Context(), UserKey, session)))
+			return next(ctx)
+		}
+	}
+}
+
+func RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			session, ok := ctx.Request().Context().Value(UserKey).(*domain.Session)
+			if !ok || session == nil || !session.IsAPIKey() {
+				return next(ctx)
+			}
+
+			if !session.HasScope(scope) {
+				return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+					Status: http.StatusForbidden,
+					Title:  "Insufficient Scope",
+					Detail: "The api key provided is missing the " + scope + " scope.",
+				})
+			}
+
+			storeID := ctx.Param("storeId")
+			if storeID != "" && storeID != session.StoreID.String() {
+				return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+					Status: http.StatusForbidden,
+					Title:  "Access Denied",
+					Detail: "The api key provided is not allowed to access this store.",
+				})
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+func RejectAPIKey() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			session, ok := ctx.Request().Context().Value(UserKey).(*domain.Session)
+			if ok && session != nil && session.IsAPIKey() {
+				return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+					Status: http.StatusForbidden,
+					Title:  "Access Denied",
+					Detail: "This resource is not available with an api key. Please log in.",
+				})
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+func RequireSuperAdmin() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			session, ok := ctx.Request().Context().Value(UserKey).(*domain.Session)
+			if !ok || session == nil || session.IsAPIKey() || session.Role != domain.UserRoleSuperAdmin {
+				return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+					Status: http.StatusForbidden,
+					Title:  "Access Denied",
+					Detail: "You are not allowed to access this resource.",
+				})
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+func CheckAdminKey() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			adminKey := ctx.Request().Header.Get("X-Admin-Key")
+
+			if config.Env.AdminKey == "" || subtle.ConstantTimeCompare([]byte(adminKey), []byte(config.Env.AdminKey)) != 1 {
+				return ctx.JSON(http.StatusForbidden, &problem.ProblemDetail{
+					Status: http.StatusForbidden,
+					Title:  "Access Denied",
+					Detail: "You are not allowed to access this resource.",
+				})
+			}
+
+			ctx.SetRequest(ctx.Request().WithContext(context.WithValue(ctx.Request().Context(), AdminKeyKey, true)))
+			return next(ctx)
+		}
+	}
+}
+
+// ResolveStore loads the public store a storefront request is addressed to,
+// from the storeSlug path param when the route has one and from the Host
+// header of a verified custom hostname otherwise. Requests using an old slug
+// are redirected to the current one.
+func ResolveStore(i *do.Injector) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			var store *domain.Store
+			var err error
+
+			slug := ctx.Param("storeSlug")
+			if slug != "" {
+				storeService := do.MustInvoke[domain.StoreService](i)
+				store, err = storeService.ResolveSlug(ctx.Request().Context(), slug)
+			} else {
+				storeDomainService := do.MustInvoke[domain.StoreDomainService](i)
+				store, err = storeDomainService.ResolveHost(ctx.Request().Context(), ctx.Request().Host)
+			}
+
+			if err != nil {
+				var storeSlugMovedError *domain.StoreSlugMovedError
+				if errors.As(err, &storeSlugMovedError) {
+					return ctx.Redirect(http.StatusMovedPermanently, replacePathSegment(ctx.Request().URL, slug, storeSlugMovedError.Slug))
+				}
+
+				if errors.Is(err, domain.ErrStoreNotFound) {
+					return ctx.JSON(http.StatusNotFound, &problem.ProblemDetail{
+						Status: http.StatusNotFound,
+						Title:  "Store Not Found",
+						Detail: "The specified store was not found.",
+					})
+				}
+
+				return ctx.JSON(http.StatusInternalServerError, &problem.ProblemDetail{
+					Status: http.StatusInternalServerError,
+					Title:  "Internal Server Error",
+					Detail: "Oops! Something went wrong while processing your request. Please try again later.",
+				})
+			}
+
+			ctx.SetRequest(ctx.Request().WithContext(context.WithValue(ctx.Request().Context(), StoreKey, store)))
+			return next(ctx)
+		}
+	}
+}
+
+func replacePathSegment(requestURL *url.URL, oldSegment string, newSegment string) string {
+	segments := strings.Split(requestURL.Path, "/")
+	for index, segment := range segments {
+		if strings.EqualFold(segment, oldSegment) {
+			segments[index] = newSegment
+			break
+		}
+	}
+
+	location := url.URL{Path: strings.Join(segments, "/"), RawQuery: requestURL.RawQuery}
+	return location.String()
+}
+
+// PublicCORS allows the origins in PUBLIC_CORS_ORIGINS (comma separated) to
+// read the public routes, or any origin when it is not set. Only safe methods
+// are allowed and no credentials are shared.
+func PublicCORS() echo.MiddlewareFunc {
+	allowOrigins := []string{"*"}
+	if config.Env.PublicCORSOrigins != "" {
+		allowOrigins = nil
+		for _, origin := range strings.Split(config.Env.PublicCORSOrigins, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				allowOrigins = append(allowOrigins, origin)
+			}
+		}
+	}
+
+	return echoMiddleware.CORSWithConfig(echoMiddleware.CORSConfig{
+		AllowOrigins:  allowOrigins,
+		AllowMethods:  []string{http.MethodGet, http.MethodHead, http.MethodOptions},
+		AllowHeaders:  []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, "If-None-Match"},
+		ExposeHeaders: []string{echo.HeaderXRequestID, "ETag"},
+		MaxAge:        int((24 * time.Hour).Seconds()),
+	})
+}
+
+// IPExtractor decides where ctx.RealIP comes from. Forwarding headers are
+// only honoured when the request arrives from one of the CIDRs listed in
+// TRUSTED_PROXIES (comma separated); otherwise the socket address is used, so
+// clients cannot pick their own IP for lockouts, audit and login history.
+func IPExtractor() (echo.IPExtractor, error) {
+	if strings.TrimSpace(config.Env.TrustedProxies) == "" {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	trustOptions := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range strings.Split(config.Env.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy == "" {
+			continue
+		}
+
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+
+		_, ipRange, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		trustOptions = append(trustOptions, echo.TrustIPRange(ipRange))
+	}
+
+	return echo.ExtractIPFromXFFHeader(trustOptions...), nil
+}
+
 func extractToken(ctx echo.Context) (string, error) {
 	token := ctx.Request().Header.Get("Authorization")
 
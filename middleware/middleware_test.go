@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/do"
+)
+
+func TestIPExtractor(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies string
+		remoteAddr     string
+		forwardedFor   string
+		want           string
+	}{
+		{name: "no proxies ignores headers", remoteAddr: "203.0.113.7:4000", forwardedFor: "198.51.100.1", want: "203.0.113.7"},
+		{name: "no proxies ignores headers from private peers", remoteAddr: "10.0.0.2:4000", forwardedFor: "198.51.100.1", want: "10.0.0.2"},
+		{name: "trusted proxy forwards client", trustedProxies: "10.0.0.0/8", remoteAddr: "10.0.0.2:4000", forwardedFor: "198.51.100.1", want: "198.51.100.1"},
+		{name: "trusted single address", trustedProxies: "10.0.0.2", remoteAddr: "10.0.0.2:4000", forwardedFor: "198.51.100.1", want: "198.51.100.1"},
+		{name: "spoofed hops before the proxy are skipped", trustedProxies: "10.0.0.0/8", remoteAddr: "10.0.0.2:4000", forwardedFor: "1.2.3.4, 198.51.100.1", want: "198.51.100.1"},
+		{name: "untrusted peer ignores headers", trustedProxies: "10.0.0.0/8", remoteAddr: "203.0.113.7:4000", forwardedFor: "198.51.100.1", want: "203.0.113.7"},
+		{name: "private peers are not trusted implicitly", trustedProxies: "10.0.0.0/8", remoteAddr: "192.168.1.5:4000", forwardedFor: "198.51.100.1", want: "192.168.1.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := config.Env
+			t.Cleanup(func() { config.Env = env })
+			config.Env.TrustedProxies = tt.trustedProxies
+
+			ipExtractor, err := IPExtractor()
+			if err != nil {
+				t.Fatalf("IPExtractor() error = %v", err)
+			}
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set(echo.HeaderXForwardedFor, tt.forwardedFor)
+			req.Header.Set(echo.HeaderXRealIP, tt.forwardedFor)
+
+			if got := ipExtractor(req); got != tt.want {
+				t.Errorf("ip = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIPExtractorRejectsInvalidProxy(t *testing.T) {
+	env := config.Env
+	t.Cleanup(func() { config.Env = env })
+	config.Env.TrustedProxies = "10.0.0.0/8, not-an-ip"
+
+	if _, err := IPExtractor(); err == nil {
+		t.Fatal("IPExtractor() error = nil, want an error for an invalid proxy")
+	}
+}
+
+type fakeStoreService struct {
+	domain.StoreService
+	stores map[string]*domain.Store
+	moved  map[string]string
+}
+
+func (f *fakeStoreService) ResolveSlug(_ context.Context, slug string) (*domain.Store, error) {
+	if newSlug, ok := f.moved[slug]; ok {
+		return nil, &domain.StoreSlugMovedError{Slug: newSlug}
+	}
+
+	if store, ok := f.stores[slug]; ok {
+		return store, nil
+	}
+	return nil, domain.ErrStoreNotFound
+}
+
+type fakeStoreDomainService struct {
+	domain.StoreDomainService
+	stores map[string]*domain.Store
+}
+
+func (f *fakeStoreDomainService) ResolveHost(_ context.Context, hostname string) (*domain.Store, error) {
+	if store, ok := f.stores[domain.NormalizeHostname(hostname)]; ok {
+		return store, nil
+	}
+	return nil, domain.ErrStoreNotFound
+}
+
+func TestResolveStore(t *testing.T) {
+	slugStore := &domain.Store{ID: uuid.New(), Slug: "new-shop"}
+	hostStore := &domain.Store{ID: uuid.New(), Slug: "host-shop"}
+
+	i := do.New()
+	do.ProvideValue[domain.StoreService](i, &fakeStoreService{
+		stores: map[string]*domain.Store{"new-shop": slugStore},
+		moved:  map[string]string{"old-shop": "new-shop"},
+	})
+	do.ProvideValue[domain.StoreDomainService](i, &fakeStoreDomainService{
+		stores: map[string]*domain.Store{"shop.example.com": hostStore},
+	})
+
+	e := echo.New()
+	handler := func(ctx echo.Context) error {
+		store, _ := ctx.Request().Context().Value(StoreKey).(*domain.Store)
+		return ctx.String(http.StatusOK, store.ID.String())
+	}
+	e.GET("/storefront", handler, ResolveStore(i))
+	e.GET("/storefront/:storeSlug", handler, ResolveStore(i))
+
+	tests := []struct {
+		name         string
+		host         string
+		target       string
+		wantStatus   int
+		wantBody     string
+		wantLocation string
+	}{
+		{name: "host header", host: "Shop.Example.com:443", target: "/storefront", wantStatus: http.StatusOK, wantBody: hostStore.ID.String()},
+		{name: "unknown host", host: "unknown.example.com", target: "/storefront", wantStatus: http.StatusNotFound},
+		{name: "slug", host: "api.example.com", target: "/storefront/new-shop", wantStatus: http.StatusOK, wantBody: slugStore.ID.String()},
+		{name: "slug wins over host", host: "shop.example.com", target: "/storefront/new-shop", wantStatus: http.StatusOK, wantBody: slugStore.ID.String()},
+		{name: "old slug redirects", host: "api.example.com", target: "/storefront/old-shop?page=2", wantStatus: http.StatusMovedPermanently, wantLocation: "/storefront/new-shop?page=2"},
+		{name: "unknown slug", host: "api.example.com", target: "/storefront/unknown-shop", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Host = tt.host
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("store = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+
+			if location := rec.Header().Get(echo.HeaderLocation); location != tt.wantLocation {
+				t.Errorf("location = %q, want %q", location, tt.wantLocation)
+			}
+		})
+	}
+}
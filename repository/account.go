@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+type accountRepository struct {
+	i           *do.Injector
+	db          *gorm.DB
+	redisClient *redis.Client
+}
+
+func NewAccountRepository(i *do.Injector) (domain.AccountRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &accountRepository{
+		i:           i,
+		db:          db,
+		redisClient: redisClient,
+	}, nil
+}
+
+func (a *accountRepository) SoftDelete(ctx context.Context, userID uuid.UUID) error {
+	log := slog.With(
+		slog.String("repository", "account"),
+		slog.String("func", "SoftDelete"),
+	)
+
+	log.Info("Initializing account soft delete process")
+
+	var publicStoreKeys []string
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ownedStoreIDs []uuid.UUID
+		if err := tx.Session(&gorm.Session{NewDB: true}).Model(&domain.Store{}).Where("userId = ?", userID.String()).Pluck("id", &ownedStoreIDs).Error; err != nil {
+			return err
+		}
+
+		for _, storeID := range ownedStoreIDs {
+			publicStoreKeys = append(publicStoreKeys, getPublicStoreKey(storeID))
+		}
+
+		storeIDs := tx.Model(&domain.Store{}).Select("id").Where("userId = ?", userID.String())
+
+		if err := tx.Where("storeId IN (?)", storeIDs).Delete(&domain.Billboard{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&domain.APIKey{}).Where("userId = ? AND revokedAt IS NULL", userID.String()).Update("revokedAt", time.Now().UTC()).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("userId = ?", userID.String()).Delete(&domain.Store{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", userID.String()).Delete(&domain.User{}).Error
+	})
+	if err != nil {
+		log.Error("Failed to soft delete account", slog.String("error", err.Error()))
+		return err
+	}
+
+	if len(publicStoreKeys) > 0 {
+		if err := a.redisClient.Del(ctx, publicStoreKeys...).Err(); err != nil {
+			log.Warn("Failed to invalidate public store cache", slog.String("error", err.Error()))
+		}
+	}
+
+	log.Info("Account soft deleted successfully")
+	return nil
+}
+
+func (a *accountRepository) GetData(ctx context.Context, userID uuid.UUID) (*domain.AccountData, error) {
+	log := slog.With(
+		slog.String("repository", "account"),
+		slog.String("func", "GetData"),
+	)
+
+	log.Info("Initializing get account data process")
+
+	var accountData domain.AccountData
+	if err := a.db.WithContext(ctx).Where("id = ?", userID.String()).First(&accountData.User).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("User not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get user", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := a.db.WithContext(ctx).Where("userId = ?", userID.String()).Find(&accountData.Identities).Error; err != nil {
+		log.Error("Failed to get identities", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := a.db.WithContext(ctx).Preload("Billboards").Where("userId = ?", userID.String()).Find(&accountData.Stores).Error; err != nil {
+		log.Error("Failed to get stores", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := a.db.WithContext(ctx).Where("userId = ?", userID.String()).Find(&accountData.APIKeys).Error; err != nil {
+		log.Error("Failed to get api keys", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := a.db.WithContext(ctx).Where("userId = ?", userID.String()).Order("createdAt DESC").Find(&accountData.LoginHistory).Error; err != nil {
+		log.Error("Failed to get login history", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := a.db.WithContext(ctx).Preload("Store").Where("userId = ?", userID.String()).Find(&accountData.Memberships).Error; err != nil {
+		log.Error("Failed to get store memberships", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := a.db.WithContext(ctx).Where("actorId = ?", userID.String()).Order("createdAt DESC").Find(&accountData.AuditEvents).Error; err != nil {
+		log.Error("Failed to get audit events", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Account data found successfully")
+	return &accountData, nil
+}
+
+func (a *accountRepository) GetDeletedBefore(ctx context.Context, before time.Time) ([]*domain.User, error) {
+	log := slog.With(
+		slog.String("repository", "account"),
+		slog.String("func", "GetDeletedBefore"),
+	)
+
+	log.Info("Initializing get deleted accounts process")
+
+	var users []*domain.User
+	if err := a.db.WithContext(ctx).Unscoped().Where("deletedAt IS NOT NULL AND deletedAt < ?", before).Find(&users).Error; err != nil {
+		log.Error("Failed to get deleted accounts", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (a *accountRepository) Purge(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	log := slog.With(
+		slog.String("repository", "account"),
+		slog.String("func", "Purge"),
+	)
+
+	log.Info("Initializing account purge process")
+
+	var imageURLs []string
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Each statement, and the store subquery, needs its own session or
+		// they would all keep adding to the same conditions.
+		storeIDs := tx.Session(&gorm.Session{NewDB: true}).Unscoped().Model(&domain.Store{}).Select("id").Where("userId = ?", userID.String())
+		tx = tx.Unscoped().Session(&gorm.Session{})
+
+		if err := tx.Model(&domain.Billboard{}).Where("storeId IN (?)", storeIDs).Pluck("imageUrl", &imageURLs).Error; err != nil {
+			return err
+		}
+
+		var avatarURLs []string
+		if err := tx.Model(&domain.User{}).Where("id = ? AND AvatarUrl <> ''", userID.String()).Pluck("AvatarUrl", &avatarURLs).Error; err != nil {
+			return err
+		}
+		imageURLs = append(imageURLs, avatarURLs...)
+
+		if err := tx.Where("storeId IN (?)", storeIDs).Delete(&domain.Billboard{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("userId = ? OR storeId IN (?)", userID.String(), storeIDs).Delete(&domain.APIKey{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("userId = ? OR storeId IN (?)", userID.String(), storeIDs).Delete(&domain.StoreMember{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("invitedById = ? OR storeId IN (?)", userID.String(), storeIDs).Delete(&domain.StoreInvite{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("storeId IN (?)", storeIDs).Delete(&domain.StoreSlugRedirect{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("storeId IN (?)", storeIDs).Delete(&domain.StoreDomain{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("userId = ?", userID.String()).Delete(&domain.Store{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("userId = ?", userID.String()).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("userId = ?", userID.String()).Delete(&domain.UserIdentity{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("userId = ?", userID.String()).Delete(&domain.LoginHistory{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", userID.String()).Delete(&domain.User{}).Error
+	})
+	if err != nil {
+		log.Error("Failed to purge account", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Account purged successfully")
+	return imageURLs, nil
+}
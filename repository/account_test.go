@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAccountRepositoryPurge(t *testing.T) {
+	db, rec := newRecorderDB(t)
+	repository := &accountRepository{db: db}
+
+	if _, err := repository.Purge(context.Background(), uuid.New()); err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+
+	const storeIDs = "(SELECT `id` FROM `Store` WHERE userId = ?)"
+
+	selects := rec.find("SELECT")
+	if len(selects) != 2 {
+		t.Fatalf("got %d SELECT statements, want 2: %q", len(selects), selects)
+	}
+
+	if want := "SELECT `imageUrl` FROM `Billboard` WHERE storeId IN " + storeIDs; selects[0] != want {
+		t.Errorf("image query = %q, want %q", selects[0], want)
+	}
+
+	deletes := rec.find("DELETE")
+	if len(deletes) != 11 {
+		t.Fatalf("got %d DELETE statements, want 11: %q", len(deletes), deletes)
+	}
+
+	for _, statement := range deletes {
+		statement = strings.ReplaceAll(statement, storeIDs, "")
+		if strings.Count(statement, "WHERE") != 1 || strings.Contains(statement, " AND ") {
+			t.Errorf("statement leaked conditions from a previous query: %q", statement)
+		}
+	}
+
+	if last := deletes[len(deletes)-1]; last != "DELETE FROM `User` WHERE id = ?" {
+		t.Errorf("last statement = %q, want the user row delete", last)
+	}
+}
+
+func TestAccountRepositorySoftDeleteReadsOwnedStoresBeforeDeleting(t *testing.T) {
+	db, rec := newRecorderDB(t)
+	repository := &accountRepository{db: db}
+
+	if err := repository.SoftDelete(context.Background(), uuid.New()); err != nil {
+		t.Fatalf("SoftDelete() error = %v", err)
+	}
+
+	if len(rec.statements) == 0 {
+		t.Fatal("no statements recorded")
+	}
+
+	if want := "SELECT `id` FROM `Store` WHERE userId = ? AND `Store`.`deletedAt` IS NULL"; rec.statements[0] != want {
+		t.Errorf("first statement = %q, want %q", rec.statements[0], want)
+	}
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+type adminRepository struct {
+	i  *do.Injector
+	db *gorm.DB
+}
+
+func NewAdminRepository(i *do.Injector) (domain.AdminRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &adminRepository{
+		i:  i,
+		db: db,
+	}, nil
+}
+
+func (a *adminRepository) GetUsers(ctx context.Context, search string, offset int, limit int) ([]*domain.User, int64, error) {
+	log := slog.With(
+		slog.String("repository", "admin"),
+		slog.String("func", "GetUsers"),
+	)
+
+	log.Info("Initializing get users process")
+
+	query := a.db.WithContext(ctx).Model(&domain.User{})
+	if search != "" {
+		like := "%" + search + "%"
+		query = query.Where("name LIKE ? OR email LIKE ? OR username LIKE ?", like, like, like)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.Error("Failed to count users", slog.String("error", err.Error()))
+		return nil, 0, err
+	}
+
+	var users []*domain.User
+	if err := query.Order("createdAt DESC").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+		log.Error("Failed to get users", slog.String("error", err.Error()))
+		return nil, 0, err
+	}
+
+	log.Info("Users found successfully")
+	return users, total, nil
+}
+
+func (a *adminRepository) GetStores(ctx context.Context, search string, deleted bool, offset int, limit int) ([]*domain.Store, int64, error) {
+	log := slog.With(
+		slog.String("repository", "admin"),
+		slog.String("func", "GetStores"),
+	)
+
+	log.Info("Initializing get stores process")
+
+	query := a.db.WithContext(ctx).Model(&domain.Store{})
+	if deleted {
+		query = query.Unscoped().Where("deletedAt IS NOT NULL")
+	}
+
+	if search != "" {
+		query = query.Where("name LIKE ?", "%"+search+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.Error("Failed to count stores", slog.String("error", err.Error()))
+		return nil, 0, err
+	}
+
+	var stores []*domain.Store
+	if err := query.Order("createdAt DESC").Offset(offset).Limit(limit).Find(&stores).Error; err != nil {
+		log.Error("Failed to get stores", slog.String("error", err.Error()))
+		return nil, 0, err
+	}
+
+	log.Info("Stores found successfully")
+	return stores, total, nil
+}
+
+func (a *adminRepository) GetStoreWithDeleted(ctx context.Context, storeID uuid.UUID) (*domain.Store, error) {
+	log := slog.With(
+		slog.String("repository", "admin"),
+		slog.String("func", "GetStoreWithDeleted"),
+	)
+
+	log.Info("Initializing get store with deleted process")
+
+	var store domain.Store
+	if err := a.db.WithContext(ctx).Unscoped().Where("id = ?", storeID.String()).First(&store).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("Store not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get store", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &store, nil
+}
+
+func (a *adminRepository) UpdateSuspension(ctx context.Context, userID uuid.UUID, suspendedAt *time.Time) error {
+	log := slog.With(
+		slog.String("repository", "admin"),
+		slog.String("func", "UpdateSuspension"),
+	)
+
+	log.Info("Initializing user suspension update process")
+
+	if err := a.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", userID.String()).Update("suspendedAt", suspendedAt).Error; err != nil {
+		log.Error("Failed to update user suspension", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("User suspension updated successfully")
+	return nil
+}
+
+func (a *adminRepository) UpdateRole(ctx context.Context, userID uuid.UUID, role domain.UserRole) error {
+	log := slog.With(
+		slog.String("repository", "admin"),
+		slog.String("func", "UpdateRole"),
+	)
+
+	log.Info("Initializing user role update process")
+
+	if err := a.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", userID.String()).Update("role", role).Error; err != nil {
+		log.Error("Failed to update user role", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("User role updated successfully")
+	return nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+type apiKeyRepository struct {
+	i  *do.Injector
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(i *do.Injector) (domain.APIKeyRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiKeyRepository{
+		i:  i,
+		db: db,
+	}, nil
+}
+
+func (a *apiKeyRepository) Create(ctx context.Context, apiKey domain.APIKey) error {
+	log := slog.With(
+		slog.String("repository", "apiKey"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing api key creation process")
+
+	if err := a.db.WithContext(ctx).Omit("Store", "User").Create(&apiKey).Error; err != nil {
+		log.Error("Failed to create api key", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("API key created successfully")
+	return nil
+}
+
+func (a *apiKeyRepository) GetAll(ctx context.Context, storeID uuid.UUID) ([]*domain.APIKey, error) {
+	log := slog.With(
+		slog.String("repository", "apiKey"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all api keys process")
+
+	var apiKeys []*domain.APIKey
+	if err := a.db.WithContext(ctx).
+		Where("storeId = ? AND revokedAt IS NULL", storeID.String()).
+		Order("createdAt DESC").
+		Find(&apiKeys).Error; err != nil {
+		log.Error("Failed to get api keys", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("API keys found successfully")
+	return apiKeys, nil
+}
+
+func (a *apiKeyRepository) GetByID(ctx context.Context, apiKeyID uuid.UUID) (*domain.APIKey, error) {
+	log := slog.With(
+		slog.String("repository", "apiKey"),
+		slog.String("func", "GetByID"),
+	)
+
+	log.Info("Initializing get api key by id process")
+
+	var apiKey domain.APIKey
+	if err := a.db.WithContext(ctx).Where("id = ? AND revokedAt IS NULL", apiKeyID.String()).First(&apiKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("API key not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get api key", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("API key found successfully")
+	return &apiKey, nil
+}
+
+func (a *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	log := slog.With(
+		slog.String("repository", "apiKey"),
+		slog.String("func", "GetByHash"),
+	)
+
+	log.Info("Initializing get api key by hash process")
+
+	var apiKey domain.APIKey
+	if err := a.db.WithContext(ctx).
+		Preload("User").
+		Preload("Store").
+		Joins("JOIN Store ON Store.id = APIKey.storeId AND Store.deletedAt IS NULL").
+		Where("APIKey.keyHash = ? AND APIKey.revokedAt IS NULL", keyHash).
+		First(&apiKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("API key not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get api key", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("API key found successfully")
+	return &apiKey, nil
+}
+
+func (a *apiKeyRepository) UpdateLastUsed(ctx context.Context, apiKeyID uuid.UUID, lastUsedAt time.Time) error {
+	log := slog.With(
+		slog.String("repository", "apiKey"),
+		slog.String("func", "UpdateLastUsed"),
+	)
+
+	log.Info("Initializing api key last used update process")
+
+	if err := a.db.WithContext(ctx).Model(&domain.APIKey{}).Where("id = ?", apiKeyID.String()).Update("lastUsedAt", lastUsedAt).Error; err != nil {
+		log.Error("Failed to update api key last used", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("API key last used updated successfully")
+	return nil
+}
+
+func (a *apiKeyRepository) Revoke(ctx context.Context, apiKeyID uuid.UUID) error {
+	log := slog.With(
+		slog.String("repository", "apiKey"),
+		slog.String("func", "Revoke"),
+	)
+
+	log.Info("Initializing api key revoke process")
+
+	if err := a.db.WithContext(ctx).Model(&domain.APIKey{}).Where("id = ?", apiKeyID.String()).Update("revokedAt", time.Now().UTC()).Error; err != nil {
+		log.Error("Failed to revoke api key", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("API key revoked successfully")
+	return nil
+}
@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/go-redis/redis/v8"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/samber/do"
+)
+
+type attemptRepository struct {
+	i           *do.Injector
+	redisClient *redis.Client
+}
+
+func NewAttemptRepository(i *do.Injector) (domain.AttemptRepository, error) {
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &attemptRepository{
+		i:           i,
+		redisClient: redisClient,
+	}, nil
+}
+
+func (a *attemptRepository) IncrementFailures(ctx context.Context, purpose domain.AttemptPurpose, scope domain.AttemptScope, identifier string, window time.Duration) (int64, error) {
+	log := slog.With(
+		slog.String("repository", "attempt"),
+		slog.String("func", "IncrementFailures"),
+	)
+
+	log.Info("Initializing increment failures process")
+
+	key := a.getFailuresKey(purpose, scope, identifier)
+
+	failures, err := a.redisClient.Incr(ctx, key).Result()
+	if err != nil {
+		log.Error("Failed to increment failures", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	if failures == 1 {
+		if err := a.redisClient.Expire(ctx, key, window).Err(); err != nil {
+			log.Error("Failed to set failures expiration", slog.String("error", err.Error()))
+			return 0, err
+		}
+	}
+
+	return failures, nil
+}
+
+func (a *attemptRepository) IncrementRequests(ctx context.Context, purpose domain.AttemptPurpose, scope domain.AttemptScope, identifier string, window time.Duration) (int64, time.Duration, error) {
+	log := slog.With(
+		slog.String("repository", "attempt"),
+		slog.String("func", "IncrementRequests"),
+	)
+
+	key := a.getRequestsKey(purpose, scope, identifier)
+
+	requests, err := a.redisClient.Incr(ctx, key).Result()
+	if err != nil {
+		log.Error("Failed to increment requests", slog.String("error", err.Error()))
+		return 0, 0, err
+	}
+
+	if requests == 1 {
+		if err := a.redisClient.Expire(ctx, key, window).Err(); err != nil {
+			log.Error("Failed to set requests expiration", slog.String("error", err.Error()))
+			return 0, 0, err
+		}
+		return requests, window, nil
+	}
+
+	ttl, err := a.redisClient.PTTL(ctx, key).Result()
+	if err != nil {
+		log.Error("Failed to get requests expiration", slog.String("error", err.Error()))
+		return 0, 0, err
+	}
+
+	if ttl < 0 {
+		ttl = window
+	}
+
+	return requests, ttl, nil
+}
+
+func (a *attemptRepository) DeleteFailures(ctx context.Context, purpose domain.AttemptPurpose, scope domain.AttemptScope, identifier string) error {
+	log := slog.With(
+		slog.String("repository", "attempt"),
+		slog.String("func", "DeleteFailures"),
+	)
+
+	log.Info("Initializing delete failures process")
+
+	keys := []string{a.getFailuresKey(purpose, scope, identifier)}
+	if scope == domain.AttemptScopeAccount {
+		keys = append(keys, a.getThrottleKey(purpose, identifier))
+	}
+
+	if err := a.redisClient.Del(ctx, keys...).Err(); err != nil {
+		log.Error("Failed to delete failures", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (a *attemptRepository) SaveLock(ctx context.Context, purpose domain.AttemptPurpose, scope domain.AttemptScope, identifier string, duration time.Duration) error {
+	log := slog.With(
+		slog.String("repository", "attempt"),
+		slog.String("func", "SaveLock"),
+	)
+
+	log.Info("Initializing save lock process")
+
+	if err := a.redisClient.Set(ctx, a.getLockKey(purpose, scope, identifier), time.Now().UTC().Unix(), duration).Err(); err != nil {
+		log.Error("Failed to save lock", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (a *attemptRepository) GetLock(ctx context.Context, purpose domain.AttemptPurpose, scope domain.AttemptScope, identifier string) (time.Duration, error) {
+	log := slog.With(
+		slog.String("repository", "attempt"),
+		slog.String("func", "GetLock"),
+	)
+
+	ttl, err := a.redisClient.TTL(ctx, a.getLockKey(purpose, scope, identifier)).Result()
+	if err != nil {
+		log.Error("Failed to get lock", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+func (a *attemptRepository) DeleteLock(ctx context.Context, purpose domain.AttemptPurpose, scope domain.AttemptScope, identifier string) error {
+	log := slog.With(
+		slog.String("repository", "attempt"),
+		slog.String("func", "DeleteLock"),
+	)
+
+	log.Info("Initializing delete lock process")
+
+	if err := a.redisClient.Del(ctx, a.getLockKey(purpose, scope, identifier)).Err(); err != nil {
+		log.Error("Failed to delete lock", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (a *attemptRepository) SaveThrottle(ctx context.Context, purpose domain.AttemptPurpose, account string, delay time.Duration) error {
+	log := slog.With(
+		slog.String("repository", "attempt"),
+		slog.String("func", "SaveThrottle"),
+	)
+
+	if err := a.redisClient.Set(ctx, a.getThrottleKey(purpose, account), time.Now().UTC().Unix(), delay).Err(); err != nil {
+		log.Error("Failed to save throttle", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (a *attemptRepository) GetThrottle(ctx context.Context, purpose domain.AttemptPurpose, account string) (time.Duration, error) {
+	log := slog.With(
+		slog.String("repository", "attempt"),
+		slog.String("func", "GetThrottle"),
+	)
+
+	ttl, err := a.redisClient.PTTL(ctx, a.getThrottleKey(purpose, account)).Result()
+	if err != nil {
+		log.Error("Failed to get throttle", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+func (a *attemptRepository) SaveUnlockToken(ctx context.Context, tokenHash string, unlockToken domain.UnlockToken, expiration time.Duration) error {
+	log := slog.With(
+		slog.String("repository", "attempt"),
+		slog.String("func", "SaveUnlockToken"),
+	)
+
+	log.Info("Initializing save unlock token process")
+
+	unlockTokenJSON, err := jsoniter.Marshal(unlockToken)
+	if err != nil {
+		log.Error("Failed to marshal unlock token", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := a.redisClient.Set(ctx, a.getUnlockTokenKey(tokenHash), unlockTokenJSON, expiration).Err(); err != nil {
+		log.Error("Failed to save unlock token", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Unlock token saved successfully")
+	return nil
+}
+
+func (a *attemptRepository) GetUnlockToken(ctx context.Context, tokenHash string) (*domain.UnlockToken, error) {
+	log := slog.With(
+		slog.String("repository", "attempt"),
+		slog.String("func", "GetUnlockToken"),
+	)
+
+	log.Info("Initializing unlock token retrieval process")
+
+	unlockTokenJSON, err := a.redisClient.GetDel(ctx, a.getUnlockTokenKey(tokenHash)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			log.Warn("Unlock token not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to retrieve unlock token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var unlockToken domain.UnlockToken
+	if err := jsoniter.UnmarshalFromString(unlockTokenJSON, &unlockToken); err != nil {
+		log.Error("Failed to unmarshal unlock token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &unlockToken, nil
+}
+
+func (a *attemptRepository) getFailuresKey(purpose domain.AttemptPurpose, scope domain.AttemptScope, identifier string) string {
+	failuresKey := fmt.Sprintf("usersession_attempt_%s_%s_%s", purpose, scope, identifier)
+	return failuresKey
+}
+
+func (a *attemptRepository) getRequestsKey(purpose domain.AttemptPurpose, scope domain.AttemptScope, identifier string) string {
+	requestsKey := fmt.Sprintf("usersession_requests_%s_%s_%s", purpose, scope, identifier)
+	return requestsKey
+}
+
+func (a *attemptRepository) getLockKey(purpose domain.AttemptPurpose, scope domain.AttemptScope, identifier string) string {
+	lockKey := fmt.Sprintf("usersession_lock_%s_%s_%s", purpose, scope, identifier)
+	return lockKey
+}
+
+func (a *attemptRepository) getThrottleKey(purpose domain.AttemptPurpose, account string) string {
+	throttleKey := fmt.Sprintf("usersession_throttle_%s_%s", purpose, account)
+	return throttleKey
+}
+
+func (a *attemptRepository) getUnlockTokenKey(tokenHash string) string {
+	unlockTokenKey := fmt.Sprintf("usersession_unlock_%s", tokenHash)
+	return unlockTokenKey
+}
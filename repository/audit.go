@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+type auditRepository struct {
+	i  *do.Injector
+	db *gorm.DB
+}
+
+func NewAuditRepository(i *do.Injector) (domain.AuditRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auditRepository{
+		i:  i,
+		db: db,
+	}, nil
+}
+
+func (a *auditRepository) Create(ctx context.Context, auditEvent domain.AuditEvent) error {
+	log := slog.With(
+		slog.String("repository", "audit"),
+		slog.String("func", "Create"),
+	)
+
+	if err := a.db.WithContext(ctx).Create(&auditEvent).Error; err != nil {
+		log.Error("Failed to create audit event", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (a *auditRepository) GetAll(ctx context.Context, storeID uuid.UUID, auditFilter domain.AuditFilter, auditCursor *domain.AuditCursor, limit int) ([]*domain.AuditEvent, error) {
+	log := slog.With(
+		slog.String("repository", "audit"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all audit events process")
+
+	query := a.db.WithContext(ctx).Where("storeId = ?", storeID.String())
+
+	if auditFilter.Action != "" {
+		query = query.Where("action = ?", auditFilter.Action)
+	}
+
+	if auditFilter.ActorID != nil {
+		query = query.Where("actorId = ?", auditFilter.ActorID.String())
+	}
+
+	if auditFilter.TargetType != "" {
+		query = query.Where("targetType = ?", auditFilter.TargetType)
+	}
+
+	if auditFilter.TargetID != "" {
+		query = query.Where("targetId = ?", auditFilter.TargetID)
+	}
+
+	if auditFilter.From != nil {
+		query = query.Where("createdAt >= ?", auditFilter.From.UTC())
+	}
+
+	if auditFilter.To != nil {
+		query = query.Where("createdAt < ?", auditFilter.To.UTC())
+	}
+
+	if auditCursor != nil {
+		query = query.Where("(createdAt < ? OR (createdAt = ? AND id < ?))", auditCursor.CreatedAt, auditCursor.CreatedAt, auditCursor.ID.String())
+	}
+
+	var auditEvents []*domain.AuditEvent
+	if err := query.Order("createdAt DESC").Order("id DESC").Limit(limit).Find(&auditEvents).Error; err != nil {
+		log.Error("Failed to get audit events", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Audit events found successfully")
+	return auditEvents, nil
+}
@@ -47,6 +47,10 @@ func (b *billboarRepository) Create(ctx context.Context, billboard domain.Billbo
 		return err
 	}
 
+	if err := b.redisClient.Del(ctx, getPublicStoreKey(billboard.StoreID)).Err(); err != nil {
+		log.Warn("Failed to invalidate public store cache", slog.String("error", err.Error()))
+	}
+
 	log.Info("billboard created successfully")
 	return nil
 }
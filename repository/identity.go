@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/go-redis/redis/v8"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+const oidcStateExp = 10 * time.Minute
+
+type identityRepository struct {
+	i           *do.Injector
+	db          *gorm.DB
+	redisClient *redis.Client
+}
+
+func NewIdentityRepository(i *do.Injector) (domain.IdentityRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &identityRepository{
+		i:           i,
+		db:          db,
+		redisClient: redisClient,
+	}, nil
+}
+
+func (r *identityRepository) Create(ctx context.Context, identity domain.UserIdentity) error {
+	log := slog.With(
+		slog.String("repository", "identity"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing identity creation process")
+
+	if err := r.db.WithContext(ctx).Omit("User").Create(&identity).Error; err != nil {
+		log.Error("Failed to create identity", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Identity created successfully")
+	return nil
+}
+
+func (r *identityRepository) GetByProviderSubject(ctx context.Context, provider string, subject string) (*domain.UserIdentity, error) {
+	log := slog.With(
+		slog.String("repository", "identity"),
+		slog.String("func", "GetByProviderSubject"),
+	)
+
+	log.Info("Initializing get identity by provider subject process")
+
+	var identity domain.UserIdentity
+	if err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("Identity not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get identity", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Identity found successfully")
+	return &identity, nil
+}
+
+func (r *identityRepository) SaveState(ctx context.Context, oidcState domain.OIDCState) error {
+	log := slog.With(
+		slog.String("repository", "identity"),
+		slog.String("func", "SaveState"),
+	)
+
+	log.Info("Initializing oidc state save process")
+
+	stateJSON, err := jsoniter.Marshal(oidcState)
+	if err != nil {
+		log.Error("Failed to marshal oidc state", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := r.redisClient.Set(ctx, r.getStateKey(oidcState.State), stateJSON, oidcStateExp).Err(); err != nil {
+		log.Error("Failed to save oidc state", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("OIDC state saved successfully")
+	return nil
+}
+
+func (r *identityRepository) GetState(ctx context.Context, state string) (*domain.OIDCState, error) {
+	log := slog.With(
+		slog.String("repository", "identity"),
+		slog.String("func", "GetState"),
+	)
+
+	log.Info("Initializing oidc state retrieval process")
+
+	stateJSON, err := r.redisClient.GetDel(ctx, r.getStateKey(state)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			log.Warn("OIDC state not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to retrieve oidc state", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var oidcState domain.OIDCState
+	if err := jsoniter.UnmarshalFromString(stateJSON, &oidcState); err != nil {
+		log.Error("Failed to unmarshal oidc state", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &oidcState, nil
+}
+
+func (r *identityRepository) getStateKey(state string) string {
+	stateKey := fmt.Sprintf("usersession_oidc_%s", state)
+	return stateKey
+}
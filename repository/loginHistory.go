@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+type loginHistoryRepository struct {
+	i           *do.Injector
+	db          *gorm.DB
+	redisClient *redis.Client
+}
+
+func NewLoginHistoryRepository(i *do.Injector) (domain.LoginHistoryRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loginHistoryRepository{
+		i:           i,
+		db:          db,
+		redisClient: redisClient,
+	}, nil
+}
+
+func (l *loginHistoryRepository) Create(ctx context.Context, loginHistory domain.LoginHistory) error {
+	log := slog.With(
+		slog.String("repository", "loginHistory"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing login history creation process")
+
+	if err := l.db.WithContext(ctx).Create(&loginHistory).Error; err != nil {
+		log.Error("Failed to create login history", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Login history created successfully")
+	return nil
+}
+
+func (l *loginHistoryRepository) GetAll(ctx context.Context, userID uuid.UUID, offset int, limit int) ([]*domain.LoginHistory, int64, error) {
+	log := slog.With(
+		slog.String("repository", "loginHistory"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all login history process")
+
+	query := l.db.WithContext(ctx).Model(&domain.LoginHistory{}).Where("userId = ?", userID.String())
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.Error("Failed to count login history", slog.String("error", err.Error()))
+		return nil, 0, err
+	}
+
+	var loginHistories []*domain.LoginHistory
+	if err := query.Order("createdAt DESC").Offset(offset).Limit(limit).Find(&loginHistories).Error; err != nil {
+		log.Error("Failed to get login history", slog.String("error", err.Error()))
+		return nil, 0, err
+	}
+
+	log.Info("Login history found successfully")
+	return loginHistories, total, nil
+}
+
+func (l *loginHistoryRepository) HasSuccess(ctx context.Context, userID uuid.UUID, fingerprint string) (bool, error) {
+	log := slog.With(
+		slog.String("repository", "loginHistory"),
+		slog.String("func", "HasSuccess"),
+	)
+
+	log.Info("Initializing check successful login process")
+
+	query := l.db.WithContext(ctx).Model(&domain.LoginHistory{}).Where("userId = ? AND result = ?", userID.String(), domain.LoginResultSuccess)
+	if fingerprint != "" {
+		query = query.Where("fingerprint = ?", fingerprint)
+	}
+
+	var count int64
+	if err := query.Limit(1).Count(&count).Error; err != nil {
+		log.Error("Failed to check successful login", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (l *loginHistoryRepository) SaveReport(ctx context.Context, tokenHash string, loginReport domain.LoginReport, expiration time.Duration) error {
+	log := slog.With(
+		slog.String("repository", "loginHistory"),
+		slog.String("func", "SaveReport"),
+	)
+
+	log.Info("Initializing save login report process")
+
+	loginReportJSON, err := jsoniter.Marshal(loginReport)
+	if err != nil {
+		log.Error("Failed to marshal login report", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := l.redisClient.Set(ctx, l.getLoginReportKey(tokenHash), loginReportJSON, expiration).Err(); err != nil {
+		log.Error("Failed to save login report", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Login report saved successfully")
+	return nil
+}
+
+func (l *loginHistoryRepository) ConsumeReport(ctx context.Context, tokenHash string) (*domain.LoginReport, error) {
+	log := slog.With(
+		slog.String("repository", "loginHistory"),
+		slog.String("func", "ConsumeReport"),
+	)
+
+	log.Info("Initializing consume login report process")
+
+	loginReportJSON, err := l.redisClient.GetDel(ctx, l.getLoginReportKey(tokenHash)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			log.Warn("Login report not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to consume login report", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var loginReport domain.LoginReport
+	if err := jsoniter.UnmarshalFromString(loginReportJSON, &loginReport); err != nil {
+		log.Error("Failed to unmarshal login report", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &loginReport, nil
+}
+
+func (l *loginHistoryRepository) getLoginReportKey(tokenHash string) string {
+	loginReportKey := fmt.Sprintf("usersession_loginreport_%s", tokenHash)
+	return loginReportKey
+}
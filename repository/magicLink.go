@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/go-redis/redis/v8"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/samber/do"
+)
+
+type magicLinkRepository struct {
+	i           *do.Injector
+	redisClient *redis.Client
+}
+
+func NewMagicLinkRepository(i *do.Injector) (domain.MagicLinkRepository, error) {
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &magicLinkRepository{
+		i:           i,
+		redisClient: redisClient,
+	}, nil
+}
+
+func (m *magicLinkRepository) Save(ctx context.Context, tokenHash string, magicLink domain.MagicLink, expiration time.Duration) error {
+	log := slog.With(
+		slog.String("repository", "magicLink"),
+		slog.String("func", "Save"),
+	)
+
+	log.Info("Initializing save magic link process")
+
+	magicLinkJSON, err := jsoniter.Marshal(magicLink)
+	if err != nil {
+		log.Error("Failed to marshal magic link", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := m.redisClient.Set(ctx, m.getMagicLinkKey(tokenHash), magicLinkJSON, expiration).Err(); err != nil {
+		log.Error("Failed to save magic link", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Magic link saved successfully")
+	return nil
+}
+
+func (m *magicLinkRepository) Consume(ctx context.Context, tokenHash string) (*domain.MagicLink, error) {
+	log := slog.With(
+		slog.String("repository", "magicLink"),
+		slog.String("func", "Consume"),
+	)
+
+	log.Info("Initializing consume magic link process")
+
+	magicLinkJSON, err := m.redisClient.GetDel(ctx, m.getMagicLinkKey(tokenHash)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			log.Warn("Magic link not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to consume magic link", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var magicLink domain.MagicLink
+	if err := jsoniter.UnmarshalFromString(magicLinkJSON, &magicLink); err != nil {
+		log.Error("Failed to unmarshal magic link", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &magicLink, nil
+}
+
+func (m *magicLinkRepository) getMagicLinkKey(tokenHash string) string {
+	magicLinkKey := fmt.Sprintf("usersession_magiclink_%s", tokenHash)
+	return magicLinkKey
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recorder is a database/sql connector that accepts every statement and
+// returns no rows, keeping the SQL gorm generated so tests can assert on it.
+// Writes report rowsAffected rows.
+type recorder struct {
+	mu           sync.Mutex
+	statements   []string
+	rowsAffected int64
+}
+
+func newRecorderDB(t *testing.T) (*gorm.DB, *recorder) {
+	t.Helper()
+
+	rec := &recorder{}
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sql.OpenDB(rec),
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open recorder db: %v", err)
+	}
+
+	return db, rec
+}
+
+func (r *recorder) record(query string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statements = append(r.statements, query)
+}
+
+// find returns the recorded statements starting with prefix.
+func (r *recorder) find(prefix string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var statements []string
+	for _, statement := range r.statements {
+		if strings.HasPrefix(statement, prefix) {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
+
+func (r *recorder) Connect(context.Context) (driver.Conn, error) { return &recorderConn{r}, nil }
+func (r *recorder) Driver() driver.Driver                        { return nil }
+
+type recorderConn struct{ rec *recorder }
+
+func (c *recorderConn) Prepare(query string) (driver.Stmt, error) {
+	return &recorderStmt{rec: c.rec, query: query}, nil
+}
+func (c *recorderConn) Close() error              { return nil }
+func (c *recorderConn) Begin() (driver.Tx, error) { return c, nil }
+func (c *recorderConn) Commit() error             { return nil }
+func (c *recorderConn) Rollback() error           { return nil }
+
+func (c *recorderConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.rec.record(query)
+	return recorderResult(c.rec.rowsAffected), nil
+}
+
+func (c *recorderConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.rec.record(query)
+	return &recorderRows{}, nil
+}
+
+type recorderStmt struct {
+	rec   *recorder
+	query string
+}
+
+func (s *recorderStmt) Close() error  { return nil }
+func (s *recorderStmt) NumInput() int { return -1 }
+
+func (s *recorderStmt) Exec([]driver.Value) (driver.Result, error) {
+	s.rec.record(s.query)
+	return recorderResult(s.rec.rowsAffected), nil
+}
+
+func (s *recorderStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.rec.record(s.query)
+	return &recorderRows{}, nil
+}
+
+type recorderResult int64
+
+func (r recorderResult) LastInsertId() (int64, error) { return 0, nil }
+func (r recorderResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+type recorderRows struct{}
+
+func (r *recorderRows) Columns() []string         { return nil }
+func (r *recorderRows) Close() error              { return nil }
+func (r *recorderRows) Next([]driver.Value) error { return io.EOF }
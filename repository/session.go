@@ -15,6 +15,18 @@ import (
 	"gorm.io/gorm"
 )
 
+// rotateRefreshTokenScript replaces the refresh token of a family only while
+// it still holds the hash the caller presented, so two concurrent refreshes
+// with the same token cannot both succeed.
+var rotateRefreshTokenScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if not current or cjson.decode(current)["TokenHash"] ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+return 1
+`)
+
 type sessionRepository struct {
 	i           *do.Injector
 	db          *gorm.DB
@@ -39,7 +51,7 @@ func NewSessionRepository(i *do.Injector) (domain.SessionRepository, error) {
 	}, nil
 }
 
-func (u *sessionRepository) Create(ctx context.Context, user domain.User, token string) error {
+func (u *sessionRepository) Create(ctx context.Context, session domain.Session) error {
 	log := slog.With(
 		slog.String("repository", "token"),
 		slog.String("func", "Create"),
@@ -47,21 +59,26 @@ func (u *sessionRepository) Create(ctx context.Context, user domain.User, token
 
 	log.Info("Initializing token creation process")
 
-	userSession := domain.Session{
-		Token:     token,
-		Name:      user.Name,
-		UserID:    user.ID,
-		Email:     user.Email,
-		AvatarURL: user.AvatarURL,
-	}
-
-	userJSON, err := jsoniter.Marshal(userSession)
+	sessionJSON, err := jsoniter.Marshal(session)
 	if err != nil {
 		log.Error("Failed to marshal user data", slog.String("error", err.Error()))
 		return err
 	}
 
-	if err := u.redisClient.Set(ctx, u.getTokenKey(user.ID.String()), userJSON, time.Duration(config.Env.TokenExp)*time.Hour).Err(); err != nil {
+	expiration := u.getSessionExpiration(session.CreatedAt)
+	if expiration <= 0 {
+		log.Warn("Session expired")
+		return domain.ErrSessionExpired
+	}
+
+	indexKey := u.getSessionIndexKey(session.UserID.String())
+
+	pipe := u.redisClient.TxPipeline()
+	pipe.Set(ctx, u.getTokenKey(session.UserID.String(), session.ID.String()), sessionJSON, expiration)
+	pipe.SAdd(ctx, indexKey, session.ID.String())
+	pipe.Expire(ctx, indexKey, time.Duration(config.Env.TokenExp)*time.Hour)
+
+	if _, err := pipe.Exec(ctx); err != nil {
 		log.Error("Failed to save token", slog.String("error", err.Error()))
 		return err
 	}
@@ -69,7 +86,7 @@ func (u *sessionRepository) Create(ctx context.Context, user domain.User, token
 	return nil
 }
 
-func (u *sessionRepository) GetUser(ctx context.Context, userID string) (*domain.Session, error) {
+func (u *sessionRepository) GetUser(ctx context.Context, userID string, sessionID string) (*domain.Session, error) {
 	log := slog.With(
 		slog.String("repository", "token"),
 		slog.String("func", "GetUser"),
@@ -77,7 +94,7 @@ func (u *sessionRepository) GetUser(ctx context.Context, userID string) (*domain
 
 	log.Info("Initializing token retrieval process")
 
-	userJSON, err := u.redisClient.Get(ctx, u.getTokenKey(userID)).Result()
+	userJSON, err := u.redisClient.Get(ctx, u.getTokenKey(userID, sessionID)).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			log.Error("Token not found")
@@ -96,7 +113,50 @@ func (u *sessionRepository) GetUser(ctx context.Context, userID string) (*domain
 	return &user, nil
 }
 
-func (u *sessionRepository) Update(ctx context.Context, user domain.User, token string) error {
+func (u *sessionRepository) GetAll(ctx context.Context, userID string) ([]*domain.Session, error) {
+	log := slog.With(
+		slog.String("repository", "token"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all sessions process")
+
+	indexKey := u.getSessionIndexKey(userID)
+
+	sessionIDs, err := u.redisClient.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		log.Error("Failed to retrieve session index", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var sessions []*domain.Session
+	for _, sessionID := range sessionIDs {
+		sessionJSON, err := u.redisClient.Get(ctx, u.getTokenKey(userID, sessionID)).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				if err := u.redisClient.SRem(ctx, indexKey, sessionID).Err(); err != nil {
+					log.Warn("Failed to remove expired session from index", slog.String("error", err.Error()))
+				}
+				continue
+			}
+			log.Error("Failed to retrieve session", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		var session domain.Session
+		if err := jsoniter.UnmarshalFromString(sessionJSON, &session); err != nil {
+			log.Error("Failed to unmarshal session data", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		sessions = append(sessions, &session)
+	}
+
+	log.Info("Sessions retrieved successfully", slog.Int("sessionCount", len(sessions)))
+	return sessions, nil
+}
+
+func (u *sessionRepository) Update(ctx context.Context, session domain.Session) error {
 	log := slog.With(
 		slog.String("repository", "token"),
 		slog.String("func", "Update"),
@@ -104,27 +164,26 @@ func (u *sessionRepository) Update(ctx context.Context, user domain.User, token
 
 	log.Info("Initializing token update process")
 
-	ttl, err := u.redisClient.TTL(ctx, u.getTokenKey(user.ID.String())).Result()
+	tokenKey := u.getTokenKey(session.UserID.String(), session.ID.String())
+
+	ttl, err := u.redisClient.TTL(ctx, tokenKey).Result()
 	if err != nil {
 		log.Error("Failed to get TTL for token", slog.String("error", err.Error()))
 		return err
 	}
 
-	userSession := domain.Session{
-		Token:     token,
-		Name:      user.Name,
-		UserID:    user.ID,
-		Email:     user.Email,
-		AvatarURL: user.AvatarURL,
+	if ttl <= 0 {
+		log.Warn("Session expired before update")
+		return domain.ErrSessionNotFound
 	}
 
-	userJSON, err := jsoniter.Marshal(userSession)
+	sessionJSON, err := jsoniter.Marshal(session)
 	if err != nil {
 		log.Error("Failed to marshal user data", slog.String("error", err.Error()))
 		return err
 	}
 
-	if err := u.redisClient.Set(ctx, u.getTokenKey(user.ID.String()), userJSON, ttl).Err(); err != nil {
+	if err := u.redisClient.Set(ctx, tokenKey, sessionJSON, ttl).Err(); err != nil {
 		log.Error("Failed to save token", slog.String("error", err.Error()))
 		return err
 	}
@@ -133,7 +192,58 @@ func (u *sessionRepository) Update(ctx context.Context, user domain.User, token
 	return nil
 }
 
-func (u *sessionRepository) SaveOTP(ctx context.Context, email string, otp string) error {
+func (u *sessionRepository) Delete(ctx context.Context, userID string, sessionID string) error {
+	log := slog.With(
+		slog.String("repository", "token"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing session delete process")
+
+	pipe := u.redisClient.TxPipeline()
+	pipe.Del(ctx, u.getTokenKey(userID, sessionID), u.getRefreshTokenKey(sessionID))
+	pipe.SRem(ctx, u.getSessionIndexKey(userID), sessionID)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Error("Failed to delete session", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Session deleted successfully")
+	return nil
+}
+
+func (u *sessionRepository) DeleteAll(ctx context.Context, userID string) error {
+	log := slog.With(
+		slog.String("repository", "token"),
+		slog.String("func", "DeleteAll"),
+	)
+
+	log.Info("Initializing delete all sessions process")
+
+	indexKey := u.getSessionIndexKey(userID)
+
+	sessionIDs, err := u.redisClient.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		log.Error("Failed to retrieve session index", slog.String("error", err.Error()))
+		return err
+	}
+
+	keys := []string{indexKey}
+	for _, sessionID := range sessionIDs {
+		keys = append(keys, u.getTokenKey(userID, sessionID), u.getRefreshTokenKey(sessionID))
+	}
+
+	if err := u.redisClient.Del(ctx, keys...).Err(); err != nil {
+		log.Error("Failed to delete sessions", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("All sessions deleted successfully", slog.Int("sessionCount", len(sessionIDs)))
+	return nil
+}
+
+func (u *sessionRepository) SaveOTP(ctx context.Context, purpose domain.OTPPurpose, email string, otp string) error {
 	log := slog.With(
 		slog.String("repository", "user_session"),
 		slog.String("func", "SaveOTP"),
@@ -141,7 +251,7 @@ func (u *sessionRepository) SaveOTP(ctx context.Context, email string, otp strin
 
 	log.Info("Initializing OTP save process")
 
-	if err := u.redisClient.Set(ctx, u.getOTPKey(email), otp, time.Duration(config.Env.OTPExp)*time.Minute).Err(); err != nil {
+	if err := u.redisClient.Set(ctx, u.getOTPKey(purpose, email), otp, time.Duration(config.Env.OTPExp)*time.Minute).Err(); err != nil {
 		log.Error("Failed to save OTP", slog.String("error", err.Error()))
 		return err
 	}
@@ -150,7 +260,7 @@ func (u *sessionRepository) SaveOTP(ctx context.Context, email string, otp strin
 	return nil
 }
 
-func (u *sessionRepository) GetOTP(ctx context.Context, email string) (string, error) {
+func (u *sessionRepository) GetOTP(ctx context.Context, purpose domain.OTPPurpose, email string) (string, error) {
 	log := slog.With(
 		slog.String("repository", "user_session"),
 		slog.String("func", "VerifyOTP"),
@@ -158,7 +268,7 @@ func (u *sessionRepository) GetOTP(ctx context.Context, email string) (string, e
 
 	log.Info("Initializing OTP verification process")
 
-	storedOTP, err := u.redisClient.Get(ctx, u.getOTPKey(email)).Result()
+	storedOTP, err := u.redisClient.Get(ctx, u.getOTPKey(purpose, email)).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			log.Warn("OTP not found")
@@ -172,12 +282,275 @@ func (u *sessionRepository) GetOTP(ctx context.Context, email string) (string, e
 	return storedOTP, nil
 }
 
-func (t *sessionRepository) getTokenKey(id string) string {
-	tokenKey := fmt.Sprintf("usersession_%s", id)
+func (u *sessionRepository) DeleteOTP(ctx context.Context, purpose domain.OTPPurpose, email string) error {
+	log := slog.With(
+		slog.String("repository", "user_session"),
+		slog.String("func", "DeleteOTP"),
+	)
+
+	log.Info("Initializing OTP delete process")
+
+	if err := u.redisClient.Del(ctx, u.getOTPKey(purpose, email)).Err(); err != nil {
+		log.Error("Failed to delete OTP", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("OTP deleted successfully")
+	return nil
+}
+
+func (u *sessionRepository) SaveRefreshToken(ctx context.Context, refreshToken domain.RefreshToken) error {
+	log := slog.With(
+		slog.String("repository", "token"),
+		slog.String("func", "SaveRefreshToken"),
+	)
+
+	log.Info("Initializing refresh token save process")
+
+	refreshTokenJSON, err := jsoniter.Marshal(refreshToken)
+	if err != nil {
+		log.Error("Failed to marshal refresh token", slog.String("error", err.Error()))
+		return err
+	}
+
+	expiration := u.getSessionExpiration(refreshToken.CreatedAt)
+	if expiration <= 0 {
+		log.Warn("Refresh token family expired")
+		return domain.ErrSessionExpired
+	}
+
+	if err := u.redisClient.Set(ctx, u.getRefreshTokenKey(refreshToken.FamilyID.String()), refreshTokenJSON, expiration).Err(); err != nil {
+		log.Error("Failed to save refresh token", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Refresh token saved successfully")
+	return nil
+}
+
+func (u *sessionRepository) GetRefreshToken(ctx context.Context, familyID string) (*domain.RefreshToken, error) {
+	log := slog.With(
+		slog.String("repository", "token"),
+		slog.String("func", "GetRefreshToken"),
+	)
+
+	log.Info("Initializing refresh token retrieval process")
+
+	refreshTokenJSON, err := u.redisClient.Get(ctx, u.getRefreshTokenKey(familyID)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			log.Warn("Refresh token not found")
+			return nil, nil
+		}
+		log.Error("Failed to retrieve refresh token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var refreshToken domain.RefreshToken
+	if err := jsoniter.UnmarshalFromString(refreshTokenJSON, &refreshToken); err != nil {
+		log.Error("Failed to unmarshal refresh token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &refreshToken, nil
+}
+
+func (u *sessionRepository) RotateRefreshToken(ctx context.Context, currentTokenHash string, refreshToken domain.RefreshToken) (bool, error) {
+	log := slog.With(
+		slog.String("repository", "token"),
+		slog.String("func", "RotateRefreshToken"),
+	)
+
+	log.Info("Initializing refresh token rotation process")
+
+	refreshTokenJSON, err := jsoniter.Marshal(refreshToken)
+	if err != nil {
+		log.Error("Failed to marshal refresh token", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	expiration := u.getSessionExpiration(refreshToken.CreatedAt)
+	if expiration <= 0 {
+		log.Warn("Refresh token family expired")
+		return false, domain.ErrSessionExpired
+	}
+
+	rotated, err := rotateRefreshTokenScript.Run(ctx, u.redisClient, []string{u.getRefreshTokenKey(refreshToken.FamilyID.String())}, currentTokenHash, refreshTokenJSON, expiration.Milliseconds()).Int()
+	if err != nil {
+		log.Error("Failed to rotate refresh token", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	if rotated == 0 {
+		log.Warn("Refresh token changed before rotation")
+		return false, nil
+	}
+
+	log.Info("Refresh token rotated successfully")
+	return true, nil
+}
+
+func (u *sessionRepository) DeleteRefreshToken(ctx context.Context, familyID string) error {
+	log := slog.With(
+		slog.String("repository", "token"),
+		slog.String("func", "DeleteRefreshToken"),
+	)
+
+	log.Info("Initializing refresh token delete process")
+
+	if err := u.redisClient.Del(ctx, u.getRefreshTokenKey(familyID)).Err(); err != nil {
+		log.Error("Failed to delete refresh token", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Refresh token deleted successfully")
+	return nil
+}
+
+func (u *sessionRepository) SaveTwoFactorChallenge(ctx context.Context, challengeHash string, userID string) error {
+	log := slog.With(
+		slog.String("repository", "token"),
+		slog.String("func", "SaveTwoFactorChallenge"),
+	)
+
+	log.Info("Initializing two factor challenge save process")
+
+	if err := u.redisClient.Set(ctx, u.getTwoFactorChallengeKey(challengeHash), userID, time.Duration(config.Env.OTPExp)*time.Minute).Err(); err != nil {
+		log.Error("Failed to save two factor challenge", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Two factor challenge saved successfully")
+	return nil
+}
+
+func (u *sessionRepository) GetTwoFactorChallenge(ctx context.Context, challengeHash string) (string, error) {
+	log := slog.With(
+		slog.String("repository", "token"),
+		slog.String("func", "GetTwoFactorChallenge"),
+	)
+
+	log.Info("Initializing two factor challenge retrieval process")
+
+	userID, err := u.redisClient.Get(ctx, u.getTwoFactorChallengeKey(challengeHash)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			log.Warn("Two factor challenge not found")
+			return "", nil
+		}
+		log.Error("Failed to retrieve two factor challenge", slog.String("error", err.Error()))
+		return "", err
+	}
+
+	return userID, nil
+}
+
+func (u *sessionRepository) DeleteTwoFactorChallenge(ctx context.Context, challengeHash string) error {
+	log := slog.With(
+		slog.String("repository", "token"),
+		slog.String("func", "DeleteTwoFactorChallenge"),
+	)
+
+	log.Info("Initializing two factor challenge delete process")
+
+	if err := u.redisClient.Del(ctx, u.getTwoFactorChallengeKey(challengeHash)).Err(); err != nil {
+		log.Error("Failed to delete two factor challenge", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Two factor challenge deleted successfully")
+	return nil
+}
+
+func (u *sessionRepository) SaveSuspension(ctx context.Context, userID string) error {
+	log := slog.With(
+		slog.String("repository", "token"),
+		slog.String("func", "SaveSuspension"),
+	)
+
+	log.Info("Initializing suspension save process")
+
+	if err := u.redisClient.Set(ctx, u.getSuspensionKey(userID), time.Now().UTC().Unix(), 0).Err(); err != nil {
+		log.Error("Failed to save suspension", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Suspension saved successfully")
+	return nil
+}
+
+func (u *sessionRepository) DeleteSuspension(ctx context.Context, userID string) error {
+	log := slog.With(
+		slog.String("repository", "token"),
+		slog.String("func", "DeleteSuspension"),
+	)
+
+	log.Info("Initializing suspension delete process")
+
+	if err := u.redisClient.Del(ctx, u.getSuspensionKey(userID)).Err(); err != nil {
+		log.Error("Failed to delete suspension", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Suspension deleted successfully")
+	return nil
+}
+
+func (u *sessionRepository) IsSuspended(ctx context.Context, userID string) (bool, error) {
+	log := slog.With(
+		slog.String("repository", "token"),
+		slog.String("func", "IsSuspended"),
+	)
+
+	exists, err := u.redisClient.Exists(ctx, u.getSuspensionKey(userID)).Result()
+	if err != nil {
+		log.Error("Failed to check suspension", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	return exists > 0, nil
+}
+
+// getSessionExpiration caps the TTL of a session and its refresh token family
+// at TOKEN_EXP after the sign in, so refreshing cannot keep it alive forever.
+func (u *sessionRepository) getSessionExpiration(createdAt time.Time) time.Duration {
+	expiration := time.Duration(config.Env.TokenExp) * time.Hour
+	if createdAt.IsZero() {
+		return expiration
+	}
+
+	if remaining := time.Until(createdAt.Add(expiration)); remaining < expiration {
+		return remaining
+	}
+	return expiration
+}
+
+func (t *sessionRepository) getTokenKey(userID string, sessionID string) string {
+	tokenKey := fmt.Sprintf("usersession_%s_%s", userID, sessionID)
 	return tokenKey
 }
 
-func (t *sessionRepository) getOTPKey(email string) string {
-	OTPKey := fmt.Sprintf("usersession_OTP_%s", email)
+func (t *sessionRepository) getSessionIndexKey(userID string) string {
+	sessionIndexKey := fmt.Sprintf("usersession_index_%s", userID)
+	return sessionIndexKey
+}
+
+func (t *sessionRepository) getOTPKey(purpose domain.OTPPurpose, email string) string {
+	OTPKey := fmt.Sprintf("usersession_OTP_%s_%s", purpose, email)
 	return OTPKey
 }
+
+func (t *sessionRepository) getRefreshTokenKey(familyID string) string {
+	refreshTokenKey := fmt.Sprintf("usersession_refresh_%s", familyID)
+	return refreshTokenKey
+}
+
+func (t *sessionRepository) getTwoFactorChallengeKey(challengeHash string) string {
+	twoFactorChallengeKey := fmt.Sprintf("usersession_2fa_%s", challengeHash)
+	return twoFactorChallengeKey
+}
+
+func (t *sessionRepository) getSuspensionKey(userID string) string {
+	suspensionKey := fmt.Sprintf("usersession_suspended_%s", userID)
+	return suspensionKey
+}
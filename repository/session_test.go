@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/config"
+)
+
+func TestSessionRepositoryExpirationIsCappedAtSignIn(t *testing.T) {
+	env := config.Env
+	t.Cleanup(func() { config.Env = env })
+	config.Env.TokenExp = 24
+
+	repository := &sessionRepository{}
+	tokenExp := 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		createdAt time.Time
+		want      time.Duration
+	}{
+		{name: "unknown creation", want: tokenExp},
+		{name: "new session", createdAt: time.Now().Add(time.Minute), want: tokenExp},
+		{name: "session halfway through", createdAt: time.Now().Add(-12 * time.Hour), want: 12 * time.Hour},
+		{name: "session past its lifetime", createdAt: time.Now().Add(-25 * time.Hour), want: -time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := repository.getSessionExpiration(tt.createdAt)
+			if diff := got - tt.want; diff > time.Second || diff < -time.Second {
+				t.Errorf("getSessionExpiration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,11 +3,14 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/GSVillas/e-commercer-api/domain"
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/samber/do"
 	"gorm.io/gorm"
 )
@@ -62,7 +65,7 @@ func (s *storeRepository) GetAll(ctx context.Context, userID uuid.UUID) ([]*doma
 	log.Info("Initializing get all stores process")
 
 	var stores []*domain.Store
-	if err := s.db.WithContext(ctx).Where("userId = ?", userID.String()).Find(&stores).Error; err != nil {
+	if err := s.db.WithContext(ctx).Where("userId = ? OR id IN (?)", userID.String(), s.db.Model(&domain.StoreMember{}).Select("storeId").Where("userId = ?", userID.String())).Find(&stores).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			log.Warn("store not found")
 			return nil, nil
@@ -99,6 +102,291 @@ func (s *storeRepository) GetByID(ctx context.Context, ID uuid.UUID) (*domain.St
 	return store, nil
 }
 
+func (s *storeRepository) GetDeleted(ctx context.Context, userID uuid.UUID) ([]*domain.Store, error) {
+	log := slog.With(
+		slog.String("repository", "store"),
+		slog.String("func", "GetDeleted"),
+	)
+
+	log.Info("Initializing get deleted stores process")
+
+	var stores []*domain.Store
+	if err := s.db.WithContext(ctx).Unscoped().Where("userId = ? AND deletedAt IS NOT NULL", userID.String()).Order("deletedAt DESC").Find(&stores).Error; err != nil {
+		log.Error("Failed to get deleted stores", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("deleted stores found successfully")
+	return stores, nil
+}
+
+func (s *storeRepository) GetDeletedByID(ctx context.Context, storeID uuid.UUID) (*domain.Store, error) {
+	log := slog.With(
+		slog.String("repository", "store"),
+		slog.String("func", "GetDeletedByID"),
+	)
+
+	log.Info("Initializing get deleted store by id process")
+
+	var store *domain.Store
+	if err := s.db.WithContext(ctx).Unscoped().Where("id = ? AND deletedAt IS NOT NULL", storeID.String()).First(&store).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("deleted store not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get deleted store", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("deleted store found successfully")
+	return store, nil
+}
+
+func (s *storeRepository) GetDeletedBefore(ctx context.Context, before time.Time) ([]*domain.Store, error) {
+	log := slog.With(
+		slog.String("repository", "store"),
+		slog.String("func", "GetDeletedBefore"),
+	)
+
+	log.Info("Initializing get stores deleted before process")
+
+	var stores []*domain.Store
+	if err := s.db.WithContext(ctx).Unscoped().Where("deletedAt IS NOT NULL AND deletedAt < ?", before).Find(&stores).Error; err != nil {
+		log.Error("Failed to get deleted stores", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("stores deleted before found successfully", slog.Int("count", len(stores)))
+	return stores, nil
+}
+
+func (s *storeRepository) GetBySlug(ctx context.Context, slug string) (*domain.Store, error) {
+	log := slog.With(
+		slog.String("repository", "store"),
+		slog.String("func", "GetBySlug"),
+	)
+
+	log.Info("Initializing get store by slug process")
+
+	var store *domain.Store
+	if err := s.db.WithContext(ctx).Where("slug = ?", slug).First(&store).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("store not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get store by slug", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("get store by slug executed successfully")
+	return store, nil
+}
+
+// ExistsBySlug also looks at deleted stores and old slugs kept as redirects,
+// so a slug is never handed to another store while links to it still exist.
+func (s *storeRepository) ExistsBySlug(ctx context.Context, slug string) (bool, error) {
+	log := slog.With(
+		slog.String("repository", "store"),
+		slog.String("func", "ExistsBySlug"),
+	)
+
+	var storeCount int64
+	if err := s.db.WithContext(ctx).Unscoped().Model(&domain.Store{}).Where("slug = ?", slug).Count(&storeCount).Error; err != nil {
+		log.Error("Failed to count stores by slug", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	if storeCount > 0 {
+		return true, nil
+	}
+
+	var redirectCount int64
+	if err := s.db.WithContext(ctx).Model(&domain.StoreSlugRedirect{}).Where("slug = ?", slug).Count(&redirectCount).Error; err != nil {
+		log.Error("Failed to count store slug redirects", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	return redirectCount > 0, nil
+}
+
+func (s *storeRepository) GetSlugRedirect(ctx context.Context, slug string) (*domain.StoreSlugRedirect, error) {
+	log := slog.With(
+		slog.String("repository", "store"),
+		slog.String("func", "GetSlugRedirect"),
+	)
+
+	log.Info("Initializing get store slug redirect process")
+
+	var storeSlugRedirect *domain.StoreSlugRedirect
+	if err := s.db.WithContext(ctx).Where("slug = ?", slug).First(&storeSlugRedirect).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("store slug redirect not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get store slug redirect", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("store slug redirect found successfully")
+	return storeSlugRedirect, nil
+}
+
+func (s *storeRepository) GetPublicByOwner(ctx context.Context, userID uuid.UUID) ([]*domain.Store, error) {
+	log := slog.With(
+		slog.String("repository", "store"),
+		slog.String("func", "GetPublicByOwner"),
+	)
+
+	log.Info("Initializing get public stores by owner process")
+
+	var stores []*domain.Store
+	if err := s.db.WithContext(ctx).Where("userId = ? AND isPublic = ?", userID.String(), true).Order("createdAt DESC").Find(&stores).Error; err != nil {
+		log.Error("Failed to get public stores", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("public stores found successfully")
+	return stores, nil
+}
+
+// GetPublicByID only returns published stores of owners who are not
+// suspended, with the billboards that have not been deleted.
+func (s *storeRepository) GetPublicByID(ctx context.Context, storeID uuid.UUID) (*domain.Store, error) {
+	log := slog.With(
+		slog.String("repository", "store"),
+		slog.String("func", "GetPublicByID"),
+	)
+
+	log.Info("Initializing get public store by id process")
+
+	var store *domain.Store
+	if err := s.db.WithContext(ctx).Preload("Billboards", func(db *gorm.DB) *gorm.DB {
+		return db.Order("createdAt DESC")
+	}).Where("id = ? AND isPublic = ? AND userId NOT IN (?)", storeID.String(), true, s.db.Model(&domain.User{}).Select("id").Where("suspendedAt IS NOT NULL")).First(&store).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("public store not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get public store", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("public store found successfully")
+	return store, nil
+}
+
+func (s *storeRepository) GetPublicCache(ctx context.Context, storeID uuid.UUID) (*domain.PublicStoreCache, error) {
+	log := slog.With(
+		slog.String("repository", "store"),
+		slog.String("func", "GetPublicCache"),
+	)
+
+	publicStoreCacheJSON, err := s.redisClient.Get(ctx, getPublicStoreKey(storeID)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+
+		log.Error("Failed to get public store cache", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var publicStoreCache domain.PublicStoreCache
+	if err := jsoniter.UnmarshalFromString(publicStoreCacheJSON, &publicStoreCache); err != nil {
+		log.Error("Failed to unmarshal public store cache", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &publicStoreCache, nil
+}
+
+func (s *storeRepository) SavePublicCache(ctx context.Context, storeID uuid.UUID, publicStoreCache domain.PublicStoreCache, expiration time.Duration) error {
+	log := slog.With(
+		slog.String("repository", "store"),
+		slog.String("func", "SavePublicCache"),
+	)
+
+	publicStoreCacheJSON, err := jsoniter.Marshal(publicStoreCache)
+	if err != nil {
+		log.Error("Failed to marshal public store cache", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.redisClient.Set(ctx, getPublicStoreKey(storeID), publicStoreCacheJSON, expiration).Err(); err != nil {
+		log.Error("Failed to save public store cache", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (s *storeRepository) DeletePublicCache(ctx context.Context, storeID uuid.UUID) error {
+	log := slog.With(
+		slog.String("repository", "store"),
+		slog.String("func", "DeletePublicCache"),
+	)
+
+	if err := s.redisClient.Del(ctx, getPublicStoreKey(storeID)).Err(); err != nil {
+		log.Error("Failed to delete public store cache", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// DeletePublicCacheByOwner drops the cached public payload of every store the
+// user owns, deleted ones included.
+func (s *storeRepository) DeletePublicCacheByOwner(ctx context.Context, userID uuid.UUID) error {
+	log := slog.With(
+		slog.String("repository", "store"),
+		slog.String("func", "DeletePublicCacheByOwner"),
+	)
+
+	var storeIDs []uuid.UUID
+	if err := s.db.WithContext(ctx).Unscoped().Model(&domain.Store{}).Where("userId = ?", userID.String()).Pluck("id", &storeIDs).Error; err != nil {
+		log.Error("Failed to get owned stores", slog.String("error", err.Error()))
+		return err
+	}
+
+	if len(storeIDs) == 0 {
+		return nil
+	}
+
+	publicStoreKeys := make([]string, 0, len(storeIDs))
+	for _, storeID := range storeIDs {
+		publicStoreKeys = append(publicStoreKeys, getPublicStoreKey(storeID))
+	}
+
+	if err := s.redisClient.Del(ctx, publicStoreKeys...).Err(); err != nil {
+		log.Error("Failed to delete public store cache", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (s *storeRepository) GetOverview(ctx context.Context, storeID uuid.UUID) (*domain.StoreOverview, error) {
+	log := slog.With(
+		slog.String("repository", "store"),
+		slog.String("func", "GetOverview"),
+	)
+
+	log.Info("Initializing get store overview process")
+
+	var storeOverview domain.StoreOverview
+	if err := s.db.WithContext(ctx).Model(&domain.Billboard{}).Where("storeId = ?", storeID.String()).Count(&storeOverview.BillboardCount).Error; err != nil {
+		log.Error("Failed to count billboards", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("store overview retrieved successfully")
+	return &storeOverview, nil
+}
+
 func (s *storeRepository) UpdateName(ctx context.Context, name string, storeID uuid.UUID) error {
 	log := slog.With(
 		slog.String("func", "UpdateName"),
@@ -112,10 +400,116 @@ func (s *storeRepository) UpdateName(ctx context.Context, name string, storeID u
 		return err
 	}
 
+	if err := s.DeletePublicCache(ctx, storeID); err != nil {
+		log.Warn("Failed to invalidate public store cache", slog.String("error", err.Error()))
+	}
+
 	log.Info("store name updated successfully")
 	return nil
 }
 
+// UpdateSlug keeps the previous slug as a redirect to the store. A store
+// going back to one of its own previous slugs reclaims it from the redirects.
+func (s *storeRepository) UpdateSlug(ctx context.Context, oldSlug string, newSlug string, storeID uuid.UUID) error {
+	log := slog.With(
+		slog.String("func", "UpdateSlug"),
+		slog.String("repository", "store"),
+	)
+
+	log.Info("Initializing update store slug process")
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("storeId = ? AND slug = ?", storeID.String(), newSlug).Delete(&domain.StoreSlugRedirect{}).Error; err != nil {
+			return err
+		}
+
+		if oldSlug != "" {
+			storeSlugRedirect := domain.StoreSlugRedirect{
+				ID:        uuid.New(),
+				StoreID:   storeID,
+				Slug:      oldSlug,
+				CreatedAt: time.Now().UTC(),
+			}
+
+			if err := tx.Create(&storeSlugRedirect).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(domain.Store{}).Where("id = ?", storeID.String()).Update("slug", newSlug).Error
+	})
+	if err != nil {
+		log.Error("Failed to update store slug", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.DeletePublicCache(ctx, storeID); err != nil {
+		log.Warn("Failed to invalidate public store cache", slog.String("error", err.Error()))
+	}
+
+	log.Info("store slug updated successfully")
+	return nil
+}
+
+func (s *storeRepository) UpdateVisibility(ctx context.Context, isPublic bool, storeID uuid.UUID) error {
+	log := slog.With(
+		slog.String("func", "UpdateVisibility"),
+		slog.String("repository", "store"),
+	)
+
+	log.Info("Initializing update store visibility process")
+
+	if err := s.db.WithContext(ctx).Model(domain.Store{}).Where("id = ?", storeID.String()).Update("isPublic", isPublic).Error; err != nil {
+		log.Error("Failed to update store visibility", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.DeletePublicCache(ctx, storeID); err != nil {
+		log.Warn("Failed to invalidate public store cache", slog.String("error", err.Error()))
+	}
+
+	log.Info("store visibility updated successfully")
+	return nil
+}
+
+func (s *storeRepository) UpdateSettings(ctx context.Context, storeSettings domain.StoreSettings, storeID uuid.UUID) error {
+	log := slog.With(
+		slog.String("func", "UpdateSettings"),
+		slog.String("repository", "store"),
+	)
+
+	log.Info("Initializing update store settings process")
+
+	updates := map[string]interface{}{
+		"currency":          storeSettings.Currency,
+		"locale":            storeSettings.Locale,
+		"timezone":          storeSettings.Timezone,
+		"supportEmail":      storeSettings.SupportEmail,
+		"supportPhone":      storeSettings.SupportPhone,
+		"addressLine1":      storeSettings.Address.Line1,
+		"addressLine2":      storeSettings.Address.Line2,
+		"addressCity":       storeSettings.Address.City,
+		"addressState":      storeSettings.Address.State,
+		"addressPostalCode": storeSettings.Address.PostalCode,
+		"addressCountry":    storeSettings.Address.Country,
+		"orderPrefix":       storeSettings.OrderPrefix,
+	}
+
+	if err := s.db.WithContext(ctx).Model(domain.Store{}).Where("id = ?", storeID.String()).Updates(updates).Error; err != nil {
+		log.Error("Failed to update store settings", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.DeletePublicCache(ctx, storeID); err != nil {
+		log.Warn("Failed to invalidate public store cache", slog.String("error", err.Error()))
+	}
+
+	log.Info("store settings updated successfully")
+	return nil
+}
+
+// Delete soft-deletes the store together with its live billboards, stamping
+// both with the same time so Restore brings back only what this delete took.
 func (s *storeRepository) Delete(ctx context.Context, storeID uuid.UUID) error {
 	log := slog.With(
 		slog.String("repository", "store"),
@@ -124,11 +518,116 @@ func (s *storeRepository) Delete(ctx context.Context, storeID uuid.UUID) error {
 
 	log.Info("Initializing delete store process")
 
-	if err := s.db.WithContext(ctx).Where("id = ?", storeID.String()).Delete(&domain.Store{}).Error; err != nil {
+	deletedAt := time.Now().UTC().Truncate(time.Millisecond)
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.Billboard{}).Where("storeId = ?", storeID.String()).Update("deletedAt", deletedAt).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&domain.Store{}).Where("id = ?", storeID.String()).Update("deletedAt", deletedAt).Error
+	})
+	if err != nil {
 		log.Error("Failed to delete store", slog.String("error", err.Error()))
 		return err
 	}
 
+	if err := s.DeletePublicCache(ctx, storeID); err != nil {
+		log.Warn("Failed to invalidate public store cache", slog.String("error", err.Error()))
+	}
+
 	log.Info("store deleted successfully")
 	return nil
 }
+
+func (s *storeRepository) Restore(ctx context.Context, storeID uuid.UUID, deletedAt time.Time) error {
+	log := slog.With(
+		slog.String("repository", "store"),
+		slog.String("func", "Restore"),
+	)
+
+	log.Info("Initializing restore store process")
+
+	err := s.db.WithContext(ctx).Unscoped().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.Billboard{}).Where("storeId = ? AND deletedAt = ?", storeID.String(), deletedAt).Update("deletedAt", nil).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&domain.Store{}).Where("id = ?", storeID.String()).Update("deletedAt", nil).Error
+	})
+	if err != nil {
+		log.Error("Failed to restore store", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.DeletePublicCache(ctx, storeID); err != nil {
+		log.Warn("Failed to invalidate public store cache", slog.String("error", err.Error()))
+	}
+
+	log.Info("store restored successfully")
+	return nil
+}
+
+// Purge hard-deletes a store and everything that belongs to it, returning the
+// billboard image URLs so the caller can remove them from the image host.
+// Audit events are kept on purpose.
+func (s *storeRepository) Purge(ctx context.Context, storeID uuid.UUID) ([]string, error) {
+	log := slog.With(
+		slog.String("repository", "store"),
+		slog.String("func", "Purge"),
+	)
+
+	log.Info("Initializing store purge process")
+
+	var imageURLs []string
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Without a new session every statement below would share, and keep
+		// adding to, the conditions of the first one.
+		tx = tx.Unscoped().Session(&gorm.Session{})
+
+		if err := tx.Model(&domain.Billboard{}).Where("storeId = ?", storeID.String()).Pluck("imageUrl", &imageURLs).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("storeId = ?", storeID.String()).Delete(&domain.Billboard{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("storeId = ?", storeID.String()).Delete(&domain.APIKey{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("storeId = ?", storeID.String()).Delete(&domain.StoreMember{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("storeId = ?", storeID.String()).Delete(&domain.StoreInvite{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("storeId = ?", storeID.String()).Delete(&domain.StoreSlugRedirect{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("storeId = ?", storeID.String()).Delete(&domain.StoreDomain{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", storeID.String()).Delete(&domain.Store{}).Error
+	})
+	if err != nil {
+		log.Error("Failed to purge store", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := s.DeletePublicCache(ctx, storeID); err != nil {
+		log.Warn("Failed to invalidate public store cache", slog.String("error", err.Error()))
+	}
+
+	log.Info("store purged successfully")
+	return imageURLs, nil
+}
+
+func getPublicStoreKey(storeID uuid.UUID) string {
+	publicStoreKey := fmt.Sprintf("publicstore_%s", storeID.String())
+	return publicStoreKey
+}
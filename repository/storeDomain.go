@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+type storeDomainRepository struct {
+	i  *do.Injector
+	db *gorm.DB
+}
+
+func NewStoreDomainRepository(i *do.Injector) (domain.StoreDomainRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storeDomainRepository{
+		i:  i,
+		db: db,
+	}, nil
+}
+
+func (s *storeDomainRepository) Create(ctx context.Context, storeDomain domain.StoreDomain) error {
+	log := slog.With(
+		slog.String("repository", "storeDomain"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing store domain creation process")
+
+	if err := s.db.WithContext(ctx).Omit("Store").Create(&storeDomain).Error; err != nil {
+		log.Error("Failed to create store domain", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Store domain created successfully")
+	return nil
+}
+
+func (s *storeDomainRepository) GetAll(ctx context.Context, storeID uuid.UUID) ([]*domain.StoreDomain, error) {
+	log := slog.With(
+		slog.String("repository", "storeDomain"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all store domains process")
+
+	var storeDomains []*domain.StoreDomain
+	if err := s.db.WithContext(ctx).Where("storeId = ?", storeID.String()).Order("createdAt").Find(&storeDomains).Error; err != nil {
+		log.Error("Failed to get store domains", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Store domains found successfully")
+	return storeDomains, nil
+}
+
+func (s *storeDomainRepository) GetByID(ctx context.Context, storeDomainID uuid.UUID) (*domain.StoreDomain, error) {
+	log := slog.With(
+		slog.String("repository", "storeDomain"),
+		slog.String("func", "GetByID"),
+	)
+
+	log.Info("Initializing get store domain by id process")
+
+	var storeDomain *domain.StoreDomain
+	if err := s.db.WithContext(ctx).Where("id = ?", storeDomainID.String()).First(&storeDomain).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("Store domain not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get store domain", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Store domain found successfully")
+	return storeDomain, nil
+}
+
+func (s *storeDomainRepository) GetByStoreAndHostname(ctx context.Context, storeID uuid.UUID, hostname string) (*domain.StoreDomain, error) {
+	log := slog.With(
+		slog.String("repository", "storeDomain"),
+		slog.String("func", "GetByStoreAndHostname"),
+	)
+
+	log.Info("Initializing get store domain by hostname process")
+
+	var storeDomain *domain.StoreDomain
+	if err := s.db.WithContext(ctx).Where("storeId = ? AND hostname = ?", storeID.String(), hostname).First(&storeDomain).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("Store domain not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get store domain", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Store domain found successfully")
+	return storeDomain, nil
+}
+
+func (s *storeDomainRepository) GetVerifiedByHostname(ctx context.Context, hostname string) (*domain.StoreDomain, error) {
+	log := slog.With(
+		slog.String("repository", "storeDomain"),
+		slog.String("func", "GetVerifiedByHostname"),
+	)
+
+	log.Info("Initializing get verified store domain process")
+
+	var storeDomain *domain.StoreDomain
+	if err := s.db.WithContext(ctx).Joins("Store").Where("StoreDomain.hostname = ? AND StoreDomain.verifiedAt IS NOT NULL", hostname).First(&storeDomain).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("Verified store domain not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get verified store domain", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Verified store domain found successfully")
+	return storeDomain, nil
+}
+
+func (s *storeDomainRepository) UpdateVerifiedAt(ctx context.Context, storeDomainID uuid.UUID, verifiedAt time.Time) error {
+	log := slog.With(
+		slog.String("repository", "storeDomain"),
+		slog.String("func", "UpdateVerifiedAt"),
+	)
+
+	log.Info("Initializing store domain verification update process")
+
+	if err := s.db.WithContext(ctx).Model(&domain.StoreDomain{}).Where("id = ?", storeDomainID.String()).Update("verifiedAt", verifiedAt).Error; err != nil {
+		log.Error("Failed to update store domain verification", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Store domain verification updated successfully")
+	return nil
+}
+
+func (s *storeDomainRepository) Delete(ctx context.Context, storeDomainID uuid.UUID) error {
+	log := slog.With(
+		slog.String("repository", "storeDomain"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing store domain delete process")
+
+	if err := s.db.WithContext(ctx).Where("id = ?", storeDomainID.String()).Delete(&domain.StoreDomain{}).Error; err != nil {
+		log.Error("Failed to delete store domain", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Store domain deleted successfully")
+	return nil
+}
@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+const pendingInviteCondition = "id = ? AND acceptedAt IS NULL AND declinedAt IS NULL AND revokedAt IS NULL AND expiresAt > ?"
+
+type storeInviteRepository struct {
+	i  *do.Injector
+	db *gorm.DB
+}
+
+func NewStoreInviteRepository(i *do.Injector) (domain.StoreInviteRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storeInviteRepository{
+		i:  i,
+		db: db,
+	}, nil
+}
+
+func (s *storeInviteRepository) Create(ctx context.Context, storeInvite domain.StoreInvite) error {
+	log := slog.With(
+		slog.String("repository", "storeInvite"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing store invite creation process")
+
+	if err := s.db.WithContext(ctx).Omit("Store", "InvitedBy").Create(&storeInvite).Error; err != nil {
+		log.Error("Failed to create store invite", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Store invite created successfully")
+	return nil
+}
+
+func (s *storeInviteRepository) GetByID(ctx context.Context, inviteID uuid.UUID) (*domain.StoreInvite, error) {
+	log := slog.With(
+		slog.String("repository", "storeInvite"),
+		slog.String("func", "GetByID"),
+	)
+
+	log.Info("Initializing get store invite by id process")
+
+	var storeInvite domain.StoreInvite
+	if err := s.db.WithContext(ctx).Joins("Store").Joins("InvitedBy").Where("StoreInvite.id = ?", inviteID.String()).First(&storeInvite).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("Store invite not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get store invite", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &storeInvite, nil
+}
+
+func (s *storeInviteRepository) GetPending(ctx context.Context, storeID uuid.UUID, email string) (*domain.StoreInvite, error) {
+	log := slog.With(
+		slog.String("repository", "storeInvite"),
+		slog.String("func", "GetPending"),
+	)
+
+	log.Info("Initializing get pending store invite process")
+
+	var storeInvite domain.StoreInvite
+	if err := s.db.WithContext(ctx).
+		Where("storeId = ? AND email = ?", storeID.String(), email).
+		Where("acceptedAt IS NULL AND declinedAt IS NULL AND revokedAt IS NULL AND expiresAt > ?", time.Now().UTC()).
+		First(&storeInvite).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		log.Error("Failed to get pending store invite", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &storeInvite, nil
+}
+
+func (s *storeInviteRepository) GetAll(ctx context.Context, storeID uuid.UUID) ([]*domain.StoreInvite, error) {
+	log := slog.With(
+		slog.String("repository", "storeInvite"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all store invites process")
+
+	var storeInvites []*domain.StoreInvite
+	if err := s.db.WithContext(ctx).
+		Where("storeId = ? AND acceptedAt IS NULL AND declinedAt IS NULL AND revokedAt IS NULL", storeID.String()).
+		Order("createdAt DESC").
+		Find(&storeInvites).Error; err != nil {
+		log.Error("Failed to get store invites", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Store invites found successfully")
+	return storeInvites, nil
+}
+
+func (s *storeInviteRepository) UpdateExpiration(ctx context.Context, inviteID uuid.UUID, expiresAt time.Time) error {
+	log := slog.With(
+		slog.String("repository", "storeInvite"),
+		slog.String("func", "UpdateExpiration"),
+	)
+
+	log.Info("Initializing store invite expiration update process")
+
+	if err := s.db.WithContext(ctx).Model(&domain.StoreInvite{}).Where("id = ?", inviteID.String()).Update("expiresAt", expiresAt).Error; err != nil {
+		log.Error("Failed to update store invite expiration", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Store invite expiration updated successfully")
+	return nil
+}
+
+func (s *storeInviteRepository) Accept(ctx context.Context, inviteID uuid.UUID, storeMember domain.StoreMember) error {
+	log := slog.With(
+		slog.String("repository", "storeInvite"),
+		slog.String("func", "Accept"),
+	)
+
+	log.Info("Initializing store invite accept process")
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return s.accept(tx, inviteID, storeMember)
+	})
+	if err != nil {
+		log.Error("Failed to accept store invite", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Store invite accepted successfully")
+	return nil
+}
+
+func (s *storeInviteRepository) AcceptWithUser(ctx context.Context, inviteID uuid.UUID, user domain.User, storeMember domain.StoreMember) error {
+	log := slog.With(
+		slog.String("repository", "storeInvite"),
+		slog.String("func", "AcceptWithUser"),
+	)
+
+	log.Info("Initializing store invite accept with user process")
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+
+		return s.accept(tx, inviteID, storeMember)
+	})
+	if err != nil {
+		log.Error("Failed to accept store invite with user", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Store invite accepted with user successfully")
+	return nil
+}
+
+// accept only marks the invite while it is still pending, so an invite
+// revoked, declined or accepted after it was read is never turned into a
+// membership.
+func (s *storeInviteRepository) accept(tx *gorm.DB, inviteID uuid.UUID, storeMember domain.StoreMember) error {
+	now := time.Now().UTC()
+	result := tx.Model(&domain.StoreInvite{}).Where(pendingInviteCondition, inviteID.String(), now).Update("acceptedAt", now)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrStoreInviteInvalid
+	}
+
+	return tx.Omit("Store", "User").Create(&storeMember).Error
+}
+
+func (s *storeInviteRepository) Decline(ctx context.Context, inviteID uuid.UUID) error {
+	log := slog.With(
+		slog.String("repository", "storeInvite"),
+		slog.String("func", "Decline"),
+	)
+
+	log.Info("Initializing store invite decline process")
+
+	now := time.Now().UTC()
+	result := s.db.WithContext(ctx).Model(&domain.StoreInvite{}).Where(pendingInviteCondition, inviteID.String(), now).Update("declinedAt", now)
+	if result.Error != nil {
+		log.Error("Failed to decline store invite", slog.String("error", result.Error.Error()))
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		log.Warn("Store invite is no longer pending")
+		return domain.ErrStoreInviteInvalid
+	}
+
+	log.Info("Store invite declined successfully")
+	return nil
+}
+
+func (s *storeInviteRepository) Revoke(ctx context.Context, inviteID uuid.UUID) error {
+	log := slog.With(
+		slog.String("repository", "storeInvite"),
+		slog.String("func", "Revoke"),
+	)
+
+	log.Info("Initializing store invite revoke process")
+
+	if err := s.db.WithContext(ctx).Model(&domain.StoreInvite{}).Where("id = ?", inviteID.String()).Update("revokedAt", time.Now().UTC()).Error; err != nil {
+		log.Error("Failed to revoke store invite", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Store invite revoked successfully")
+	return nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+)
+
+func TestStoreInviteRepositoryAnswersOnlyPendingInvites(t *testing.T) {
+	user := domain.User{ID: uuid.New(), Email: "member@example.com"}
+	storeMember := domain.StoreMember{ID: uuid.New(), StoreID: uuid.New(), UserID: user.ID, Role: domain.StoreRoleEditor}
+
+	tests := []struct {
+		name       string
+		answer     func(repository *storeInviteRepository) error
+		wantInsert string
+	}{
+		{
+			name: "accept",
+			answer: func(repository *storeInviteRepository) error {
+				return repository.Accept(context.Background(), uuid.New(), storeMember)
+			},
+			wantInsert: "INSERT INTO `StoreMember`",
+		},
+		{
+			name: "accept with user",
+			answer: func(repository *storeInviteRepository) error {
+				return repository.AcceptWithUser(context.Background(), uuid.New(), user, storeMember)
+			},
+			wantInsert: "INSERT INTO `StoreMember`",
+		},
+		{
+			name: "decline",
+			answer: func(repository *storeInviteRepository) error {
+				return repository.Decline(context.Background(), uuid.New())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, pending := range []bool{true, false} {
+			name := tt.name + " pending"
+			if !pending {
+				name = tt.name + " answered concurrently"
+			}
+
+			t.Run(name, func(t *testing.T) {
+				db, rec := newRecorderDB(t)
+				if pending {
+					rec.rowsAffected = 1
+				}
+
+				err := tt.answer(&storeInviteRepository{db: db})
+				if pending && err != nil {
+					t.Fatalf("error = %v, want nil", err)
+				}
+
+				if !pending && !errors.Is(err, domain.ErrStoreInviteInvalid) {
+					t.Fatalf("error = %v, want %v", err, domain.ErrStoreInviteInvalid)
+				}
+
+				updates := rec.find("UPDATE `StoreInvite`")
+				if len(updates) != 1 || !strings.Contains(updates[0], pendingInviteCondition) {
+					t.Fatalf("updates = %q, want one update guarded by the pending condition", updates)
+				}
+
+				if tt.wantInsert == "" {
+					return
+				}
+
+				inserts := len(rec.find(tt.wantInsert))
+				if pending && inserts != 1 || !pending && inserts != 0 {
+					t.Errorf("statements = %q, want a membership only for a pending invite", rec.statements)
+				}
+			})
+		}
+	}
+}
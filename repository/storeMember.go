@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+type storeMemberRepository struct {
+	i  *do.Injector
+	db *gorm.DB
+}
+
+func NewStoreMemberRepository(i *do.Injector) (domain.StoreMemberRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storeMemberRepository{
+		i:  i,
+		db: db,
+	}, nil
+}
+
+func (s *storeMemberRepository) Create(ctx context.Context, storeMember domain.StoreMember) error {
+	log := slog.With(
+		slog.String("repository", "storeMember"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing store member creation process")
+
+	if err := s.db.WithContext(ctx).Omit("Store", "User").Create(&storeMember).Error; err != nil {
+		log.Error("Failed to create store member", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Store member created successfully")
+	return nil
+}
+
+func (s *storeMemberRepository) GetAll(ctx context.Context, storeID uuid.UUID) ([]*domain.StoreMember, error) {
+	log := slog.With(
+		slog.String("repository", "storeMember"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all store members process")
+
+	var storeMembers []*domain.StoreMember
+	if err := s.db.WithContext(ctx).Joins("User").Where("StoreMember.storeId = ?", storeID.String()).Order("StoreMember.createdAt").Find(&storeMembers).Error; err != nil {
+		log.Error("Failed to get store members", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Store members found successfully")
+	return storeMembers, nil
+}
+
+func (s *storeMemberRepository) GetAllByUser(ctx context.Context, userID uuid.UUID) ([]*domain.StoreMember, error) {
+	log := slog.With(
+		slog.String("repository", "storeMember"),
+		slog.String("func", "GetAllByUser"),
+	)
+
+	log.Info("Initializing get store memberships by user process")
+
+	var storeMembers []*domain.StoreMember
+	if err := s.db.WithContext(ctx).Where("userId = ?", userID.String()).Find(&storeMembers).Error; err != nil {
+		log.Error("Failed to get store memberships", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return storeMembers, nil
+}
+
+func (s *storeMemberRepository) GetByStoreAndUser(ctx context.Context, storeID uuid.UUID, userID uuid.UUID) (*domain.StoreMember, error) {
+	log := slog.With(
+		slog.String("repository", "storeMember"),
+		slog.String("func", "GetByStoreAndUser"),
+	)
+
+	log.Info("Initializing get store member process")
+
+	var storeMember domain.StoreMember
+	if err := s.db.WithContext(ctx).Where("storeId = ? AND userId = ?", storeID.String(), userID.String()).First(&storeMember).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("Store member not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get store member", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &storeMember, nil
+}
+
+func (s *storeMemberRepository) UpdateRole(ctx context.Context, storeID uuid.UUID, userID uuid.UUID, role domain.StoreRole) error {
+	log := slog.With(
+		slog.String("repository", "storeMember"),
+		slog.String("func", "UpdateRole"),
+	)
+
+	log.Info("Initializing store member role update process")
+
+	if err := s.db.WithContext(ctx).Model(&domain.StoreMember{}).Where("storeId = ? AND userId = ?", storeID.String(), userID.String()).Update("role", role).Error; err != nil {
+		log.Error("Failed to update store member role", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Store member role updated successfully")
+	return nil
+}
+
+func (s *storeMemberRepository) Delete(ctx context.Context, storeID uuid.UUID, userID uuid.UUID) error {
+	log := slog.With(
+		slog.String("repository", "storeMember"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing store member delete process")
+
+	if err := s.db.WithContext(ctx).Where("storeId = ? AND userId = ?", storeID.String(), userID.String()).Delete(&domain.StoreMember{}).Error; err != nil {
+		log.Error("Failed to delete store member", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Store member deleted successfully")
+	return nil
+}
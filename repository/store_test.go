@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+func TestStoreRepositoryPurge(t *testing.T) {
+	db, rec := newRecorderDB(t)
+	repository := &storeRepository{
+		db:          db,
+		redisClient: redis.NewClient(&redis.Options{Addr: "127.0.0.1:0", DialTimeout: time.Millisecond, MaxRetries: -1}),
+	}
+
+	if _, err := repository.Purge(context.Background(), uuid.New()); err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+
+	deletes := rec.find("DELETE")
+	if len(deletes) != 7 {
+		t.Fatalf("got %d DELETE statements, want 7: %q", len(deletes), deletes)
+	}
+
+	for _, statement := range deletes {
+		if strings.Count(statement, "WHERE") != 1 || strings.Contains(statement, " AND ") {
+			t.Errorf("statement leaked conditions from a previous query: %q", statement)
+		}
+	}
+
+	if last := deletes[len(deletes)-1]; !strings.Contains(last, "`Store`") || !strings.Contains(last, "id = ?") {
+		t.Errorf("last statement = %q, want the store row delete", last)
+	}
+}
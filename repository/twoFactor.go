@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+	"gorm.io/gorm"
+)
+
+// totpStepExp outlives the window a TOTP code is accepted in, so a code
+// cannot be replayed once the step that accepted it has expired here.
+const totpStepExp = 2 * time.Minute
+
+// useTOTPStepScript records the last accepted time step of a user and only
+// succeeds for steps newer than it.
+var useTOTPStepScript = redis.NewScript(`
+local last = tonumber(redis.call("GET", KEYS[1]))
+if last and last >= tonumber(ARGV[1]) then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+return 1
+`)
+
+type twoFactorRepository struct {
+	i           *do.Injector
+	db          *gorm.DB
+	redisClient *redis.Client
+}
+
+func NewTwoFactorRepository(i *do.Injector) (domain.TwoFactorRepository, error) {
+	db, err := do.Invoke[*gorm.DB](i)
+	if err != nil {
+		return nil, err
+	}
+
+	redisClient, err := do.Invoke[*redis.Client](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &twoFactorRepository{
+		i:           i,
+		db:          db,
+		redisClient: redisClient,
+	}, nil
+}
+
+func (t *twoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, recoveryCodes []domain.RecoveryCode) error {
+	log := slog.With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "ReplaceRecoveryCodes"),
+	)
+
+	log.Info("Initializing replace recovery codes process")
+
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("userId = ?", userID.String()).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&recoveryCodes).Error
+	})
+	if err != nil {
+		log.Error("Failed to replace recovery codes", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Recovery codes replaced successfully")
+	return nil
+}
+
+func (t *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	log := slog.With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "UseRecoveryCode"),
+	)
+
+	log.Info("Initializing use recovery code process")
+
+	result := t.db.WithContext(ctx).
+		Model(&domain.RecoveryCode{}).
+		Where("userId = ? AND codeHash = ? AND usedAt IS NULL", userID.String(), codeHash).
+		Update("usedAt", time.Now().UTC())
+	if result.Error != nil {
+		log.Error("Failed to use recovery code", slog.String("error", result.Error.Error()))
+		return false, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		log.Warn("Recovery code not found or already used")
+		return false, nil
+	}
+
+	log.Info("Recovery code used successfully")
+	return true, nil
+}
+
+func (t *twoFactorRepository) DeleteRecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	log := slog.With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "DeleteRecoveryCodes"),
+	)
+
+	log.Info("Initializing delete recovery codes process")
+
+	if err := t.db.WithContext(ctx).Where("userId = ?", userID.String()).Delete(&domain.RecoveryCode{}).Error; err != nil {
+		log.Error("Failed to delete recovery codes", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Recovery codes deleted successfully")
+	return nil
+}
+
+func (t *twoFactorRepository) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	log := slog.With(
+		slog.String("repository", "twoFactor"),
+		slog.String("func", "UseTOTPStep"),
+	)
+
+	log.Info("Initializing use totp step process")
+
+	used, err := useTOTPStepScript.Run(ctx, t.redisClient, []string{t.getTOTPStepKey(userID)}, step, totpStepExp.Milliseconds()).Int()
+	if err != nil {
+		log.Error("Failed to use totp step", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	if used == 0 {
+		log.Warn("TOTP code already used")
+		return false, nil
+	}
+
+	log.Info("TOTP step used successfully")
+	return true, nil
+}
+
+func (t *twoFactorRepository) getTOTPStepKey(userID uuid.UUID) string {
+	totpStepKey := fmt.Sprintf("usersession_totp_step_%s", userID.String())
+	return totpStepKey
+}
@@ -3,15 +3,21 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/GSVillas/e-commercer-api/config"
 	"github.com/GSVillas/e-commercer-api/domain"
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/samber/do"
 	"gorm.io/gorm"
 )
 
+const emailChangeCancelExp = 7 * 24 * time.Hour
+
 type userRepository struct {
 	i           *do.Injector
 	db          *gorm.DB
@@ -76,6 +82,63 @@ func (u *userRepository) GetByEmail(ctx context.Context, email string) (*domain.
 	return &user, nil
 }
 
+func (u *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	log := slog.With(
+		slog.String("repository", "user"),
+		slog.String("func", "ExistsByEmail"),
+	)
+
+	log.Info("Initializing check email exists process")
+
+	var count int64
+	if err := u.db.WithContext(ctx).Unscoped().Model(&domain.User{}).Where("email = ?", email).Count(&count).Error; err != nil {
+		log.Error("Failed to check email exists", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (u *userRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	log := slog.With(
+		slog.String("repository", "user"),
+		slog.String("func", "GetByUsername"),
+	)
+
+	log.Info("Initializing get user by username process")
+
+	var user domain.User
+	if err := u.db.WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("User not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to get user by username", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("User found successfully")
+	return &user, nil
+}
+
+func (u *userRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	log := slog.With(
+		slog.String("repository", "user"),
+		slog.String("func", "ExistsByUsername"),
+	)
+
+	log.Info("Initializing check username exists process")
+
+	var count int64
+	if err := u.db.WithContext(ctx).Unscoped().Model(&domain.User{}).Where("username = ?", username).Count(&count).Error; err != nil {
+		log.Error("Failed to check username exists", slog.String("error", err.Error()))
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
 func (u *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	log := slog.With(
 		slog.String("repository", "user"),
@@ -116,6 +179,23 @@ func (u *userRepository) UpdateName(ctx context.Context, id uuid.UUID, name stri
 	return nil
 }
 
+func (u *userRepository) UpdateUsername(ctx context.Context, id uuid.UUID, username string) error {
+	log := slog.With(
+		slog.String("repository", "user"),
+		slog.String("func", "UpdateUsername"),
+	)
+
+	log.Info("Initializing user username update process")
+
+	if err := u.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("username", username).Error; err != nil {
+		log.Error("Failed to update user username", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("User username updated successfully")
+	return nil
+}
+
 func (u *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
 	log := slog.With(
 		slog.String("repository", "user"),
@@ -149,3 +229,249 @@ func (u *userRepository) UpdateConfirmEmail(ctx context.Context, id uuid.UUID) e
 	log.Info("User email confirm updated successfully")
 	return nil
 }
+
+// ReclaimUnconfirmed confirms an account for the owner of its email and drops
+// every credential whoever registered it may have set up meanwhile.
+func (u *userRepository) ReclaimUnconfirmed(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	log := slog.With(
+		slog.String("repository", "user"),
+		slog.String("func", "ReclaimUnconfirmed"),
+	)
+
+	log.Info("Initializing reclaim unconfirmed user process")
+
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.User{}).Where("id = ?", id.String()).Updates(map[string]any{
+			"emailConfirmed":   true,
+			"passwordHash":     passwordHash,
+			"twoFactorEnabled": false,
+			"twoFactorSecret":  "",
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("userId = ?", id.String()).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("userId = ?", id.String()).Delete(&domain.UserIdentity{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&domain.APIKey{}).Where("userId = ? AND revokedAt IS NULL", id.String()).Update("revokedAt", time.Now().UTC()).Error
+	})
+	if err != nil {
+		log.Error("Failed to reclaim unconfirmed user", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Unconfirmed user reclaimed successfully")
+	return nil
+}
+
+func (u *userRepository) UpdatePasswordResetRequired(ctx context.Context, id uuid.UUID, required bool) error {
+	log := slog.With(
+		slog.String("repository", "user"),
+		slog.String("func", "UpdatePasswordResetRequired"),
+	)
+
+	log.Info("Initializing password reset required update process")
+
+	if err := u.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("passwordResetRequired", required).Error; err != nil {
+		log.Error("Failed to update password reset required", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Password reset required updated successfully")
+	return nil
+}
+
+func (u *userRepository) UpdateTwoFactor(ctx context.Context, id uuid.UUID, secret string, enabled bool) error {
+	log := slog.With(
+		slog.String("repository", "user"),
+		slog.String("func", "UpdateTwoFactor"),
+	)
+
+	log.Info("Initializing user two factor update process")
+
+	updates := map[string]interface{}{
+		"twoFactorSecret":  secret,
+		"twoFactorEnabled": enabled,
+	}
+
+	if err := u.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		log.Error("Failed to update user two factor", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("User two factor updated successfully")
+	return nil
+}
+
+func (u *userRepository) UpdateEmail(ctx context.Context, id uuid.UUID, email string) error {
+	log := slog.With(
+		slog.String("repository", "user"),
+		slog.String("func", "UpdateEmail"),
+	)
+
+	log.Info("Initializing user email update process")
+
+	updates := map[string]interface{}{
+		"email":          email,
+		"emailConfirmed": true,
+	}
+
+	if err := u.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		log.Error("Failed to update user email", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("User email updated successfully")
+	return nil
+}
+
+func (u *userRepository) UpdateAvatar(ctx context.Context, id uuid.UUID, avatarURL string) error {
+	log := slog.With(
+		slog.String("repository", "user"),
+		slog.String("func", "UpdateAvatar"),
+	)
+
+	log.Info("Initializing user avatar update process")
+
+	if err := u.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("AvatarUrl", avatarURL).Error; err != nil {
+		log.Error("Failed to update user avatar", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("User avatar updated successfully")
+	return nil
+}
+
+func (u *userRepository) SaveEmailChange(ctx context.Context, emailChange domain.EmailChange) error {
+	log := slog.With(
+		slog.String("repository", "user"),
+		slog.String("func", "SaveEmailChange"),
+	)
+
+	log.Info("Initializing save email change process")
+
+	emailChangeJSON, err := jsoniter.Marshal(emailChange)
+	if err != nil {
+		log.Error("Failed to marshal email change", slog.String("error", err.Error()))
+		return err
+	}
+
+	expiration := time.Duration(config.Env.OTPExp) * time.Minute
+	if err := u.redisClient.Set(ctx, u.getEmailChangeKey(emailChange.UserID), emailChangeJSON, expiration).Err(); err != nil {
+		log.Error("Failed to save email change", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Email change saved successfully")
+	return nil
+}
+
+func (u *userRepository) GetEmailChange(ctx context.Context, userID uuid.UUID) (*domain.EmailChange, error) {
+	log := slog.With(
+		slog.String("repository", "user"),
+		slog.String("func", "GetEmailChange"),
+	)
+
+	log.Info("Initializing email change retrieval process")
+
+	emailChangeJSON, err := u.redisClient.Get(ctx, u.getEmailChangeKey(userID)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			log.Warn("Email change not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to retrieve email change", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var emailChange domain.EmailChange
+	if err := jsoniter.UnmarshalFromString(emailChangeJSON, &emailChange); err != nil {
+		log.Error("Failed to unmarshal email change", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &emailChange, nil
+}
+
+func (u *userRepository) DeleteEmailChange(ctx context.Context, userID uuid.UUID) error {
+	log := slog.With(
+		slog.String("repository", "user"),
+		slog.String("func", "DeleteEmailChange"),
+	)
+
+	log.Info("Initializing delete email change process")
+
+	if err := u.redisClient.Del(ctx, u.getEmailChangeKey(userID)).Err(); err != nil {
+		log.Error("Failed to delete email change", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (u *userRepository) SaveEmailChangeCancelToken(ctx context.Context, tokenHash string, emailChange domain.EmailChange) error {
+	log := slog.With(
+		slog.String("repository", "user"),
+		slog.String("func", "SaveEmailChangeCancelToken"),
+	)
+
+	log.Info("Initializing save email change cancel token process")
+
+	emailChangeJSON, err := jsoniter.Marshal(emailChange)
+	if err != nil {
+		log.Error("Failed to marshal email change", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := u.redisClient.Set(ctx, u.getEmailChangeCancelKey(tokenHash), emailChangeJSON, emailChangeCancelExp).Err(); err != nil {
+		log.Error("Failed to save email change cancel token", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Email change cancel token saved successfully")
+	return nil
+}
+
+func (u *userRepository) GetEmailChangeCancelToken(ctx context.Context, tokenHash string) (*domain.EmailChange, error) {
+	log := slog.With(
+		slog.String("repository", "user"),
+		slog.String("func", "GetEmailChangeCancelToken"),
+	)
+
+	log.Info("Initializing email change cancel token retrieval process")
+
+	emailChangeJSON, err := u.redisClient.GetDel(ctx, u.getEmailChangeCancelKey(tokenHash)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			log.Warn("Email change cancel token not found")
+			return nil, nil
+		}
+
+		log.Error("Failed to retrieve email change cancel token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var emailChange domain.EmailChange
+	if err := jsoniter.UnmarshalFromString(emailChangeJSON, &emailChange); err != nil {
+		log.Error("Failed to unmarshal email change", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &emailChange, nil
+}
+
+func (u *userRepository) getEmailChangeKey(userID uuid.UUID) string {
+	emailChangeKey := fmt.Sprintf("usersession_emailchange_%s", userID.String())
+	return emailChangeKey
+}
+
+func (u *userRepository) getEmailChangeCancelKey(tokenHash string) string {
+	emailChangeCancelKey := fmt.Sprintf("usersession_emailchange_cancel_%s", tokenHash)
+	return emailChangeCancelKey
+}
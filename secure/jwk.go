@@ -0,0 +1,98 @@
+package secure
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var ErrUnsupportedJSONWebKey = errors.New("unsupported json web key")
+
+type JSONWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+func NewECJSONWebKey(kid string, publicKey *ecdsa.PublicKey) JSONWebKey {
+	x, y := encodeECCoordinates(publicKey)
+	return JSONWebKey{
+		Kid: kid,
+		Kty: "EC",
+		Alg: "ES256",
+		Use: "sig",
+		Crv: "P-256",
+		X:   x,
+		Y:   y,
+	}
+}
+
+func ECThumbprint(publicKey *ecdsa.PublicKey) string {
+	x, y := encodeECCoordinates(publicKey)
+	thumbprint := sha256.Sum256([]byte(fmt.Sprintf(`{"crv":"P-256","kty":"EC","x":"%s","y":"%s"}`, x, y)))
+	return base64.RawURLEncoding.EncodeToString(thumbprint[:])
+}
+
+func ParseJSONWebKey(jwk JSONWebKey) (interface{}, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		if jwk.Crv != "P-256" {
+			return nil, ErrUnsupportedJSONWebKey
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	default:
+		return nil, ErrUnsupportedJSONWebKey
+	}
+}
+
+func encodeECCoordinates(publicKey *ecdsa.PublicKey) (string, string) {
+	size := (publicKey.Curve.Params().BitSize + 7) / 8
+	x := publicKey.X.FillBytes(make([]byte, size))
+	y := publicKey.Y.FillBytes(make([]byte, size))
+	return base64.RawURLEncoding.EncodeToString(x), base64.RawURLEncoding.EncodeToString(y)
+}
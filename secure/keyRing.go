@@ -0,0 +1,136 @@
+package secure
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/golang-jwt/jwt"
+)
+
+var (
+	ErrSigningKeyNotFound      = errors.New("active signing key not found")
+	ErrVerificationKeyNotFound = errors.New("verification key not found")
+	ErrUnexpectedSigningMethod = errors.New("unexpected signing method")
+	ErrInvalidPEM              = errors.New("failed to decode PEM block")
+	ErrNotECDSAKey             = errors.New("not ECDSA key")
+)
+
+type KeyRing struct {
+	mutex            sync.RWMutex
+	activeKID        string
+	signingKey       *ecdsa.PrivateKey
+	verificationKeys map[string]*ecdsa.PublicKey
+}
+
+func NewKeyRing(activeKID string, signingKey *ecdsa.PrivateKey, verificationKeys map[string]*ecdsa.PublicKey) (*KeyRing, error) {
+	if activeKID == "" || signingKey == nil {
+		return nil, ErrSigningKeyNotFound
+	}
+
+	keys := make(map[string]*ecdsa.PublicKey, len(verificationKeys)+1)
+	for kid, publicKey := range verificationKeys {
+		keys[kid] = publicKey
+	}
+	keys[activeKID] = &signingKey.PublicKey
+
+	return &KeyRing{
+		activeKID:        activeKID,
+		signingKey:       signingKey,
+		verificationKeys: keys,
+	}, nil
+}
+
+func (k *KeyRing) Replace(keyRing *KeyRing) {
+	keyRing.mutex.RLock()
+	activeKID, signingKey, verificationKeys := keyRing.activeKID, keyRing.signingKey, keyRing.verificationKeys
+	keyRing.mutex.RUnlock()
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	k.activeKID = activeKID
+	k.signingKey = signingKey
+	k.verificationKeys = verificationKeys
+}
+
+func (k *KeyRing) SigningKey() (string, *ecdsa.PrivateKey) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	return k.activeKID, k.signingKey
+}
+
+func (k *KeyRing) VerificationKey(kid string) (*ecdsa.PublicKey, error) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	if kid == "" {
+		return &k.signingKey.PublicKey, nil
+	}
+
+	publicKey, ok := k.verificationKeys[kid]
+	if !ok {
+		return nil, ErrVerificationKeyNotFound
+	}
+
+	return publicKey, nil
+}
+
+func (k *KeyRing) Keyfunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodECDSA); !ok {
+		return nil, ErrUnexpectedSigningMethod
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	return k.VerificationKey(kid)
+}
+
+func (k *KeyRing) JSONWebKeySet() JSONWebKeySet {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	kids := make([]string, 0, len(k.verificationKeys))
+	for kid := range k.verificationKeys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	keySet := JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(kids))}
+	for _, kid := range kids {
+		keySet.Keys = append(keySet.Keys, NewECJSONWebKey(kid, k.verificationKeys[kid]))
+	}
+
+	return keySet
+}
+
+func ParseECPrivateKeyPEM(keyData []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(keyData)
+	if block == nil || block.Type != "EC PRIVATE KEY" {
+		return nil, ErrInvalidPEM
+	}
+
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+func ParseECPublicKeyPEM(keyData []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(keyData)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, ErrInvalidPEM
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	ecdsaPublicKey, ok := publicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, ErrNotECDSAKey
+	}
+
+	return ecdsaPublicKey, nil
+}
@@ -0,0 +1,222 @@
+package secure
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+)
+
+func generateTestKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return privateKey
+}
+
+func mustKeyRing(t *testing.T, kid string, privateKey *ecdsa.PrivateKey) *KeyRing {
+	t.Helper()
+
+	keyRing, err := NewKeyRing(kid, privateKey, nil)
+	if err != nil {
+		t.Fatalf("NewKeyRing() error = %v", err)
+	}
+	return keyRing
+}
+
+func signTestToken(t *testing.T, privateKey *ecdsa.PrivateKey, kid string) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"sub": "user"})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(privateKey)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func TestNewKeyRingRequiresSigningKey(t *testing.T) {
+	if _, err := NewKeyRing("", generateTestKey(t), nil); !errors.Is(err, ErrSigningKeyNotFound) {
+		t.Errorf("NewKeyRing() without kid error = %v, want %v", err, ErrSigningKeyNotFound)
+	}
+
+	if _, err := NewKeyRing("active", nil, nil); !errors.Is(err, ErrSigningKeyNotFound) {
+		t.Errorf("NewKeyRing() without key error = %v, want %v", err, ErrSigningKeyNotFound)
+	}
+}
+
+func TestKeyRingVerificationKey(t *testing.T) {
+	activeKey := generateTestKey(t)
+	retiredKey := generateTestKey(t)
+
+	keyRing, err := NewKeyRing("active", activeKey, map[string]*ecdsa.PublicKey{"retired": &retiredKey.PublicKey})
+	if err != nil {
+		t.Fatalf("NewKeyRing() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		kid     string
+		want    *ecdsa.PublicKey
+		wantErr error
+	}{
+		{name: "missing kid falls back to the active key", kid: "", want: &activeKey.PublicKey},
+		{name: "active kid", kid: "active", want: &activeKey.PublicKey},
+		{name: "retired kid", kid: "retired", want: &retiredKey.PublicKey},
+		{name: "unknown kid", kid: "unknown", wantErr: ErrVerificationKeyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publicKey, err := keyRing.VerificationKey(tt.kid)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerificationKey(%q) error = %v, want %v", tt.kid, err, tt.wantErr)
+			}
+
+			if tt.want != nil && !publicKey.Equal(tt.want) {
+				t.Errorf("VerificationKey(%q) returned the wrong key", tt.kid)
+			}
+		})
+	}
+}
+
+func TestKeyRingKeyfunc(t *testing.T) {
+	activeKey := generateTestKey(t)
+	retiredKey := generateTestKey(t)
+
+	keyRing, err := NewKeyRing("active", activeKey, map[string]*ecdsa.PublicKey{"retired": &retiredKey.PublicKey})
+	if err != nil {
+		t.Fatalf("NewKeyRing() error = %v", err)
+	}
+
+	hmacToken, err := jwt.New(jwt.SigningMethodHS256).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("sign hmac token: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		token     string
+		wantValid bool
+	}{
+		{name: "token without kid", token: signTestToken(t, activeKey, ""), wantValid: true},
+		{name: "token from retired key", token: signTestToken(t, retiredKey, "retired"), wantValid: true},
+		{name: "token signed by another key", token: signTestToken(t, generateTestKey(t), "active")},
+		{name: "token with unknown kid", token: signTestToken(t, activeKey, "unknown")},
+		{name: "hmac token", token: hmacToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := jwt.Parse(tt.token, keyRing.Keyfunc)
+			if valid := err == nil && token.Valid; valid != tt.wantValid {
+				t.Errorf("Parse() valid = %v, want %v (error = %v)", valid, tt.wantValid, err)
+			}
+		})
+	}
+}
+
+func TestKeyRingReplace(t *testing.T) {
+	oldKey := generateTestKey(t)
+	newKey := generateTestKey(t)
+
+	keyRing, err := NewKeyRing("old", oldKey, nil)
+	if err != nil {
+		t.Fatalf("NewKeyRing() error = %v", err)
+	}
+	oldToken := signTestToken(t, oldKey, "old")
+
+	reloaded, err := NewKeyRing("new", newKey, map[string]*ecdsa.PublicKey{"old": &oldKey.PublicKey})
+	if err != nil {
+		t.Fatalf("NewKeyRing() error = %v", err)
+	}
+	keyRing.Replace(reloaded)
+
+	activeKID, signingKey := keyRing.SigningKey()
+	if activeKID != "new" || !signingKey.Equal(newKey) {
+		t.Fatalf("SigningKey() = %q, want the reloaded key", activeKID)
+	}
+
+	if _, err := jwt.Parse(oldToken, keyRing.Keyfunc); err != nil {
+		t.Errorf("token signed before the reload error = %v, want it to still verify", err)
+	}
+
+	publicKey, err := keyRing.VerificationKey("")
+	if err != nil || !publicKey.Equal(&newKey.PublicKey) {
+		t.Errorf("VerificationKey(\"\") after reload did not fall back to the new active key")
+	}
+
+	keyRing.Replace(mustKeyRing(t, "newer", generateTestKey(t)))
+	if _, err := jwt.Parse(oldToken, keyRing.Keyfunc); err == nil {
+		t.Error("token signed by a dropped key still verifies after reload")
+	}
+}
+
+func TestKeyRingJSONWebKeySet(t *testing.T) {
+	activeKey := generateTestKey(t)
+	retiredKey := generateTestKey(t)
+
+	keyRing, err := NewKeyRing("b-active", activeKey, map[string]*ecdsa.PublicKey{"a-retired": &retiredKey.PublicKey})
+	if err != nil {
+		t.Fatalf("NewKeyRing() error = %v", err)
+	}
+
+	keySet := keyRing.JSONWebKeySet()
+	if len(keySet.Keys) != 2 || keySet.Keys[0].Kid != "a-retired" || keySet.Keys[1].Kid != "b-active" {
+		t.Fatalf("JSONWebKeySet() = %+v, want both keys sorted by kid", keySet)
+	}
+
+	publicKey, err := ParseJSONWebKey(keySet.Keys[1])
+	if err != nil {
+		t.Fatalf("ParseJSONWebKey() error = %v", err)
+	}
+
+	if !activeKey.PublicKey.Equal(publicKey) {
+		t.Error("published key does not match the active key")
+	}
+}
+
+func TestParseECKeyPEM(t *testing.T) {
+	privateKey := generateTestKey(t)
+
+	privateKeyData, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("marshal private key: %v", err)
+	}
+
+	publicKeyData, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privateKeyData})
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyData})
+
+	if parsed, err := ParseECPrivateKeyPEM(privateKeyPEM); err != nil || !parsed.Equal(privateKey) {
+		t.Errorf("ParseECPrivateKeyPEM() error = %v", err)
+	}
+
+	if parsed, err := ParseECPublicKeyPEM(publicKeyPEM); err != nil || !parsed.Equal(&privateKey.PublicKey) {
+		t.Errorf("ParseECPublicKeyPEM() error = %v", err)
+	}
+
+	if _, err := ParseECPrivateKeyPEM(publicKeyPEM); !errors.Is(err, ErrInvalidPEM) {
+		t.Errorf("ParseECPrivateKeyPEM() of a public key error = %v, want %v", err, ErrInvalidPEM)
+	}
+
+	if _, err := ParseECPublicKeyPEM([]byte("not pem")); !errors.Is(err, ErrInvalidPEM) {
+		t.Errorf("ParseECPublicKeyPEM() of garbage error = %v, want %v", err, ErrInvalidPEM)
+	}
+}
@@ -1,23 +1,37 @@
 package secure
 
 import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"image/png"
 	"time"
 
 	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
 )
 
+const (
+	totpPeriod = 30
+	totpSkew   = 1
+)
+
 func GenerateSecret(email string) (string, error) {
-	key, err := totp.Generate(totp.GenerateOpts{
-		Issuer:      "E-commercer.com",
-		AccountName: email,
-	})
+	key, err := GenerateTOTPKey(email)
 	if err != nil {
 		return "", err
 	}
 	return key.Secret(), nil
 }
 
+func GenerateTOTPKey(email string) (*otp.Key, error) {
+	return totp.Generate(totp.GenerateOpts{
+		Issuer:      "E-commercer.com",
+		AccountName: email,
+	})
+}
+
 func GenerateNumericOTP(secret string) (string, error) {
 	otp, err := totp.GenerateCodeCustom(secret, time.Now().UTC(), totp.ValidateOpts{
 		Period:    30,
@@ -30,3 +44,39 @@ func GenerateNumericOTP(secret string) (string, error) {
 	}
 	return otp, nil
 }
+
+// ValidateTOTP checks code against the steps around now and returns the time
+// step it matched, so callers can refuse a code that was already accepted.
+func ValidateTOTP(code string, secret string, now time.Time) (int64, bool) {
+	currentStep := now.Unix() / totpPeriod
+	for step := currentStep - totpSkew; step <= currentStep+totpSkew; step++ {
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*totpPeriod, 0).UTC(), totp.ValidateOpts{
+			Period:    totpPeriod,
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+func GenerateQRCode(key *otp.Key, size int) (string, error) {
+	image, err := key.Image(size, size)
+	if err != nil {
+		return "", err
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, image); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("data:image/png;base64,%s", base64.StdEncoding.EncodeToString(buffer.Bytes())), nil
+}
@@ -0,0 +1,49 @@
+package secure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+func TestValidateTOTP(t *testing.T) {
+	secret, err := GenerateSecret("user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+
+	now := time.Unix(1_700_000_010, 0).UTC()
+	currentStep := now.Unix() / totpPeriod
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current step", at: now, wantStep: currentStep, wantOK: true},
+		{name: "previous step", at: now.Add(-totpPeriod * time.Second), wantStep: currentStep - 1, wantOK: true},
+		{name: "next step", at: now.Add(totpPeriod * time.Second), wantStep: currentStep + 1, wantOK: true},
+		{name: "outside skew", at: now.Add(-2 * totpPeriod * time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := totp.GenerateCodeCustom(secret, tt.at, totp.ValidateOpts{
+				Period:    totpPeriod,
+				Digits:    otp.DigitsSix,
+				Algorithm: otp.AlgorithmSHA1,
+			})
+			if err != nil {
+				t.Fatalf("generate code: %v", err)
+			}
+
+			step, ok := ValidateTOTP(code, secret, now)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("ValidateTOTP() = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package secure
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	defaultPasswordMinLength = 8
+	defaultPasswordMinScore  = 3
+	// bcrypt ignores everything after the 72nd byte, so longer passwords
+	// would give a false sense of strength.
+	maxPasswordBytes = 72
+)
+
+type PasswordViolationCode string
+
+const (
+	PasswordTooShort         PasswordViolationCode = "too_short"
+	PasswordTooLong          PasswordViolationCode = "too_long"
+	PasswordMissingUppercase PasswordViolationCode = "missing_uppercase"
+	PasswordMissingLowercase PasswordViolationCode = "missing_lowercase"
+	PasswordMissingDigit     PasswordViolationCode = "missing_digit"
+	PasswordMissingSymbol    PasswordViolationCode = "missing_symbol"
+	PasswordTooWeak          PasswordViolationCode = "too_weak"
+	PasswordBreached         PasswordViolationCode = "breached"
+)
+
+type PasswordViolation struct {
+	Code   PasswordViolationCode
+	Reason string
+}
+
+type PasswordPolicy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	MinScore      int
+	breached      map[string]struct{}
+}
+
+func DefaultPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength: defaultPasswordMinLength,
+		MaxLength: maxPasswordBytes,
+		MinScore:  defaultPasswordMinScore,
+	}
+}
+
+func (p *PasswordPolicy) LoadBreachedPasswords(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	breached := map[string]struct{}{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		breached[strings.ToLower(line)] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read breached passwords: %w", err)
+	}
+
+	p.breached = breached
+	return nil
+}
+
+func (p *PasswordPolicy) IsBreached(password string) bool {
+	_, ok := p.breached[strings.ToLower(password)]
+	return ok
+}
+
+func (p *PasswordPolicy) Check(password string, userInputs ...string) []PasswordViolation {
+	var violations []PasswordViolation
+
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		violations = append(violations, PasswordViolation{
+			Code:   PasswordTooShort,
+			Reason: fmt.Sprintf("must be at least %d characters long", p.MinLength),
+		})
+	}
+
+	if length > p.MaxLength || len(password) > maxPasswordBytes {
+		violations = append(violations, PasswordViolation{
+			Code:   PasswordTooLong,
+			Reason: fmt.Sprintf("must be at most %d characters long", min(p.MaxLength, maxPasswordBytes)),
+		})
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		default:
+			hasSymbol = true
+		}
+	}
+
+	if p.RequireUpper && !hasUpper {
+		violations = append(violations, PasswordViolation{Code: PasswordMissingUppercase, Reason: "must contain an uppercase letter"})
+	}
+
+	if p.RequireLower && !hasLower {
+		violations = append(violations, PasswordViolation{Code: PasswordMissingLowercase, Reason: "must contain a lowercase letter"})
+	}
+
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, PasswordViolation{Code: PasswordMissingDigit, Reason: "must contain a digit"})
+	}
+
+	if p.RequireSymbol && !hasSymbol {
+		violations = append(violations, PasswordViolation{Code: PasswordMissingSymbol, Reason: "must contain a symbol"})
+	}
+
+	if p.IsBreached(password) {
+		violations = append(violations, PasswordViolation{
+			Code:   PasswordBreached,
+			Reason: "has appeared in a data breach and cannot be used",
+		})
+	}
+
+	if score := p.Score(password, userInputs...); score < p.MinScore {
+		violations = append(violations, PasswordViolation{
+			Code:   PasswordTooWeak,
+			Reason: fmt.Sprintf("is too easy to guess (strength %d of 4, at least %d required)", score, p.MinScore),
+		})
+	}
+
+	return violations
+}
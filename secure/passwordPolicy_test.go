@@ -0,0 +1,138 @@
+package secure
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func violationCodes(violations []PasswordViolation) []PasswordViolationCode {
+	codes := make([]PasswordViolationCode, 0, len(violations))
+	for _, violation := range violations {
+		codes = append(codes, violation.Code)
+	}
+	return codes
+}
+
+func TestPasswordPolicyCheck(t *testing.T) {
+	strictPolicy := &PasswordPolicy{
+		MinLength:     8,
+		MaxLength:     maxPasswordBytes,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+
+	tests := []struct {
+		name           string
+		passwordPolicy *PasswordPolicy
+		password       string
+		userInputs     []string
+		want           []PasswordViolationCode
+	}{
+		{name: "strong password", passwordPolicy: DefaultPasswordPolicy(), password: "N8v!qL4@rT6#"},
+		{name: "too short", passwordPolicy: DefaultPasswordPolicy(), password: "kX9#mQ2", want: []PasswordViolationCode{PasswordTooShort}},
+		{name: "too long", passwordPolicy: DefaultPasswordPolicy(), password: strings.Repeat("kX9#mQ2$", 10), want: []PasswordViolationCode{PasswordTooLong}},
+		{name: "too many bytes", passwordPolicy: DefaultPasswordPolicy(), password: strings.Repeat("kX9#mQ2ü", 9), want: []PasswordViolationCode{PasswordTooLong}},
+		{name: "too weak", passwordPolicy: DefaultPasswordPolicy(), password: "password", want: []PasswordViolationCode{PasswordTooWeak}},
+		{name: "built from user inputs", passwordPolicy: DefaultPasswordPolicy(), password: "jsmith2024", userInputs: []string{"jsmith@example.com"}, want: []PasswordViolationCode{PasswordTooWeak}},
+		{name: "character classes satisfied", passwordPolicy: strictPolicy, password: "kX9#mQ2$"},
+		{
+			name:           "character classes missing",
+			passwordPolicy: strictPolicy,
+			password:       "        ",
+			want:           []PasswordViolationCode{PasswordMissingUppercase, PasswordMissingLowercase, PasswordMissingDigit},
+		},
+		{name: "missing symbol", passwordPolicy: strictPolicy, password: "kX9bmQ2z", want: []PasswordViolationCode{PasswordMissingSymbol}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := violationCodes(tt.passwordPolicy.Check(tt.password, tt.userInputs...)); !slices.Equal(got, tt.want) {
+				t.Errorf("Check(%q) = %v, want %v", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicyLoadBreachedPasswords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	content := "# leaked passwords\n\nN8v!qL4@rT6#\n  Xkqvmzpwjr  \n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write breached list: %v", err)
+	}
+
+	passwordPolicy := DefaultPasswordPolicy()
+	if err := passwordPolicy.LoadBreachedPasswords(path); err != nil {
+		t.Fatalf("LoadBreachedPasswords() error = %v", err)
+	}
+
+	tests := []struct {
+		password string
+		want     bool
+	}{
+		{password: "N8v!qL4@rT6#", want: true},
+		{password: "n8v!ql4@rt6#", want: true},
+		{password: "xkqvmzpwjr", want: true},
+		{password: "# leaked passwords"},
+		{password: ""},
+		{password: "kX9#mQ2$"},
+	}
+
+	for _, tt := range tests {
+		if got := passwordPolicy.IsBreached(tt.password); got != tt.want {
+			t.Errorf("IsBreached(%q) = %v, want %v", tt.password, got, tt.want)
+		}
+	}
+
+	if got := violationCodes(passwordPolicy.Check("N8v!qL4@rT6#")); !slices.Contains(got, PasswordBreached) {
+		t.Errorf("Check() of breached password = %v, want %s", got, PasswordBreached)
+	}
+
+	if err := passwordPolicy.LoadBreachedPasswords(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("LoadBreachedPasswords() of a missing file error = nil, want an error")
+	}
+
+	if !passwordPolicy.IsBreached("xkqvmzpwjr") {
+		t.Error("failed reload discarded the previously loaded list")
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	cost := hashCost
+	t.Cleanup(func() { hashCost = cost })
+
+	if err := SetHashCost(bcrypt.MinCost); err != nil {
+		t.Fatalf("SetHashCost() error = %v", err)
+	}
+
+	passwordHash, err := Hash("kX9#mQ2$")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	if NeedsRehash(string(passwordHash)) {
+		t.Error("NeedsRehash() = true for a hash at the current cost")
+	}
+
+	if err := SetHashCost(bcrypt.MinCost + 1); err != nil {
+		t.Fatalf("SetHashCost() error = %v", err)
+	}
+
+	if !NeedsRehash(string(passwordHash)) {
+		t.Error("NeedsRehash() = false for a hash below the current cost")
+	}
+
+	if NeedsRehash("not-a-bcrypt-hash") {
+		t.Error("NeedsRehash() = true for an invalid hash")
+	}
+
+	if err := SetHashCost(bcrypt.MaxCost + 1); err == nil {
+		t.Error("SetHashCost() above the maximum error = nil, want an error")
+	}
+}
@@ -0,0 +1,285 @@
+package secure
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	minPatternLength     = 3
+	maxDictionaryWordLen = 32
+)
+
+// Score thresholds in bits of estimated guessing entropy, mirroring the
+// 0-4 scale used by zxcvbn.
+var scoreThresholds = []float64{20, 28, 36, 44}
+
+var commonPasswords = []string{
+	"password", "passw0rd", "qwerty", "letmein", "welcome", "admin", "administrator",
+	"login", "master", "dragon", "monkey", "football", "baseball", "soccer", "iloveyou",
+	"sunshine", "princess", "shadow", "superman", "batman", "trustno", "secret", "hello",
+	"freedom", "whatever", "starwars", "pokemon", "summer", "winter", "spring", "autumn",
+	"flower", "cookie", "cheese", "computer", "internet", "michael", "jennifer", "jordan",
+	"hunter", "ranger", "buster", "thomas", "charlie", "george", "andrew", "daniel",
+	"jessica", "ashley", "amanda", "love", "lovely", "angel", "family", "loveme",
+	"killer", "pepper", "ginger", "tigger", "orange", "purple", "yellow", "silver",
+	"golden", "blue", "green", "black", "white", "red", "money", "change", "access",
+	"default", "guest", "user", "test", "demo", "root", "pass", "shop", "store",
+	"ecommerce", "commerce", "company", "market", "brasil", "brazil", "senha",
+}
+
+var keyboardRows = []string{
+	"`1234567890-=",
+	"qwertyuiop[]\\",
+	"asdfghjkl;'",
+	"zxcvbnm,./",
+	"qazwsxedcrfvtgbyhnujmikolp",
+}
+
+var leetReplacer = strings.NewReplacer(
+	"0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s", "!", "i",
+)
+
+var commonPasswordSet = func() map[string]struct{} {
+	words := make(map[string]struct{}, len(commonPasswords))
+	for _, word := range commonPasswords {
+		words[word] = struct{}{}
+	}
+	return words
+}()
+
+// Score estimates how hard the password is to guess on a 0 (trivial) to
+// 4 (strong) scale. Known words, user inputs, keyboard walks, sequences,
+// repeats and years are priced as a single guess each instead of per
+// character, so "Password2024!" scores far below a random string of the
+// same length.
+func (p *PasswordPolicy) Score(password string, userInputs ...string) int {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return 0
+	}
+
+	lowered := []rune(strings.ToLower(password))
+	if len(lowered) != len(runes) {
+		lowered = runes
+	}
+
+	normalized := []rune(leetReplacer.Replace(string(lowered)))
+	if len(normalized) != len(runes) {
+		normalized = lowered
+	}
+
+	inputs := p.normalizeUserInputs(userInputs)
+	cardinality := math.Log2(float64(charsetSize(runes)))
+
+	var bits float64
+	for i := 0; i < len(runes); {
+		if length, cost := p.matchDictionary(runes, normalized, i, inputs); length > 0 {
+			bits += cost
+			i += length
+			continue
+		}
+
+		if length := matchRepeat(lowered, i); length > 0 {
+			bits += cardinality + math.Log2(float64(length))
+			i += length
+			continue
+		}
+
+		if length := matchSequence(lowered, i); length > 0 {
+			bits += math.Log2(float64(len(keyboardRows))*26) + math.Log2(float64(length))
+			i += length
+			continue
+		}
+
+		if matchYear(runes, i) {
+			bits += math.Log2(200)
+			i += 4
+			continue
+		}
+
+		bits += cardinality
+		i++
+	}
+
+	score := 0
+	for _, threshold := range scoreThresholds {
+		if bits >= threshold {
+			score++
+		}
+	}
+
+	return score
+}
+
+func (p *PasswordPolicy) normalizeUserInputs(userInputs []string) map[string]struct{} {
+	inputs := map[string]struct{}{}
+	for _, input := range userInputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+		if local, _, ok := strings.Cut(input, "@"); ok {
+			input = local
+		}
+
+		for _, part := range strings.FieldsFunc(input, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if len([]rune(part)) >= minPatternLength {
+				inputs[part] = struct{}{}
+			}
+		}
+	}
+	return inputs
+}
+
+func (p *PasswordPolicy) matchDictionary(runes []rune, normalized []rune, start int, inputs map[string]struct{}) (int, float64) {
+	end := min(len(normalized), start+maxDictionaryWordLen)
+	for j := end; j-start >= minPatternLength; j-- {
+		word := string(normalized[start:j])
+		raw := strings.ToLower(string(runes[start:j]))
+
+		var cost float64
+		switch {
+		case contains(inputs, word), contains(inputs, raw):
+			cost = 1
+		case contains(commonPasswordSet, word), contains(commonPasswordSet, raw):
+			cost = math.Log2(float64(len(commonPasswordSet)))
+		case p.IsBreached(word), p.IsBreached(raw):
+			cost = math.Log2(float64(len(p.breached)))
+		default:
+			continue
+		}
+
+		return j - start, cost + variationCost(runes[start:j])
+	}
+	return 0, 0
+}
+
+func matchRepeat(lowered []rune, start int) int {
+	j := start + 1
+	for j < len(lowered) && lowered[j] == lowered[start] {
+		j++
+	}
+
+	if j-start < minPatternLength {
+		return 0
+	}
+	return j - start
+}
+
+func matchSequence(lowered []rune, start int) int {
+	best := 0
+
+	if start+1 < len(lowered) {
+		delta := lowered[start+1] - lowered[start]
+		if delta == 1 || delta == -1 {
+			j := start + 1
+			for j < len(lowered) && lowered[j]-lowered[j-1] == delta {
+				j++
+			}
+			best = j - start
+		}
+	}
+
+	for _, row := range keyboardRows {
+		for _, candidate := range []string{row, reverse(row)} {
+			index := strings.IndexRune(candidate, lowered[start])
+			if index < 0 {
+				continue
+			}
+
+			keys := []rune(candidate)
+			length := 0
+			for index+length < len(keys) && start+length < len(lowered) && keys[index+length] == lowered[start+length] {
+				length++
+			}
+			best = max(best, length)
+		}
+	}
+
+	if best < minPatternLength {
+		return 0
+	}
+	return best
+}
+
+func matchYear(runes []rune, start int) bool {
+	if start+4 > len(runes) {
+		return false
+	}
+
+	year := string(runes[start : start+4])
+	for _, r := range year {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return strings.HasPrefix(year, "19") || strings.HasPrefix(year, "20")
+}
+
+func variationCost(runes []rune) float64 {
+	upper := 0
+	for _, r := range runes {
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+
+	switch {
+	case upper == 0:
+		return 0
+	case upper == 1 && unicode.IsUpper(runes[0]), upper == len(runes):
+		return 1
+	default:
+		return float64(upper)
+	}
+}
+
+func charsetSize(runes []rune) int {
+	var hasUpper, hasLower, hasDigit, hasSymbol, hasOther bool
+	for _, r := range runes {
+		switch {
+		case r > unicode.MaxASCII:
+			hasOther = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		default:
+			hasSymbol = true
+		}
+	}
+
+	size := 0
+	if hasUpper {
+		size += 26
+	}
+	if hasLower {
+		size += 26
+	}
+	if hasDigit {
+		size += 10
+	}
+	if hasSymbol {
+		size += 33
+	}
+	if hasOther {
+		size += 100
+	}
+	return size
+}
+
+func contains(set map[string]struct{}, value string) bool {
+	_, ok := set[value]
+	return ok
+}
+
+func reverse(value string) string {
+	runes := []rune(value)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
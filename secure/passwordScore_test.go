@@ -0,0 +1,47 @@
+package secure
+
+import "testing"
+
+func TestPasswordPolicyScore(t *testing.T) {
+	tests := []struct {
+		name       string
+		password   string
+		userInputs []string
+		want       int
+	}{
+		{name: "empty", password: "", want: 0},
+		{name: "common password", password: "password", want: 0},
+		{name: "leet common password", password: "P@ssw0rd", want: 0},
+		{name: "keyboard walk", password: "qwerty123", want: 0},
+		{name: "repeat", password: "aaaaaaaa", want: 0},
+		{name: "sequence", password: "abcdefgh", want: 0},
+		{name: "user input and year", password: "jsmith2024", userInputs: []string{"jsmith@example.com"}, want: 0},
+		{name: "decorated common password", password: "Password2024!", want: 1},
+		{name: "five random letters", password: "xkqvm", want: 1},
+		{name: "seven random letters", password: "xkqvmzp", want: 2},
+		{name: "eight random letters", password: "xkqvmzpw", want: 3},
+		{name: "ten random letters", password: "xkqvmzpwjr", want: 4},
+		{name: "mixed charset", password: "kX9#mQ2$", want: 4},
+		{name: "passphrase", password: "correct horse battery staple", want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultPasswordPolicy().Score(tt.password, tt.userInputs...); got != tt.want {
+				t.Errorf("Score(%q) = %d, want %d", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicyScoreUsesBreachedList(t *testing.T) {
+	passwordPolicy := DefaultPasswordPolicy()
+	if got := passwordPolicy.Score("xkqvmzpwjr"); got != 4 {
+		t.Fatalf("Score() before loading breached list = %d, want 4", got)
+	}
+
+	passwordPolicy.breached = map[string]struct{}{"xkqvmzpwjr": {}}
+	if got := passwordPolicy.Score("xkqvmzpwjr"); got != 0 {
+		t.Errorf("Score() of breached password = %d, want 0", got)
+	}
+}
@@ -0,0 +1,55 @@
+package secure
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestSecretBox(t *testing.T) {
+	secretBox, err := NewSecretBox(base64.StdEncoding.EncodeToString(make([]byte, 32)))
+	if err != nil {
+		t.Fatalf("NewSecretBox() error = %v", err)
+	}
+
+	sealed, err := secretBox.Seal("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+
+	if sealed == "JBSWY3DPEHPK3PXP" {
+		t.Fatal("Seal() returned the plaintext")
+	}
+
+	if again, _ := secretBox.Seal("JBSWY3DPEHPK3PXP"); again == sealed {
+		t.Error("Seal() reused the nonce")
+	}
+
+	opened, err := secretBox.Open(sealed)
+	if err != nil || opened != "JBSWY3DPEHPK3PXP" {
+		t.Fatalf("Open() = (%q, %v), want the plaintext", opened, err)
+	}
+
+	otherBox, err := NewSecretBox(base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")))
+	if err != nil {
+		t.Fatalf("NewSecretBox() error = %v", err)
+	}
+
+	for _, ciphertext := range []string{"JBSWY3DPEHPK3PXP", "", sealed[:len(sealed)-4]} {
+		if _, err := secretBox.Open(ciphertext); !errors.Is(err, ErrSecretCiphertextInvalid) {
+			t.Errorf("Open(%q) error = %v, want %v", ciphertext, err, ErrSecretCiphertextInvalid)
+		}
+	}
+
+	if _, err := otherBox.Open(sealed); !errors.Is(err, ErrSecretCiphertextInvalid) {
+		t.Errorf("Open() with another key error = %v, want %v", err, ErrSecretCiphertextInvalid)
+	}
+}
+
+func TestNewSecretBoxRejectsInvalidKey(t *testing.T) {
+	for _, key := range []string{"", "not base64!", base64.StdEncoding.EncodeToString(make([]byte, 16))} {
+		if _, err := NewSecretBox(key); !errors.Is(err, ErrSecretKeyInvalid) {
+			t.Errorf("NewSecretBox(%q) error = %v, want %v", key, err, ErrSecretKeyInvalid)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/client"
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/middleware"
+	"github.com/samber/do"
+)
+
+const defaultAccountPurgeGraceDays = 30
+
+type accountService struct {
+	i                 *do.Injector
+	accountRepository domain.AccountRepository
+	userRepository    domain.UserRepository
+	sessionService    domain.SessionService
+	emailService      domain.EmailService
+	attemptService    domain.AttemptService
+	cloudFlareService client.CloudFlareService
+	auditService      domain.AuditService
+}
+
+func NewAccountService(i *do.Injector) (domain.AccountService, error) {
+	accountRepository, err := do.Invoke[domain.AccountRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	userRepository, err := do.Invoke[domain.UserRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionService, err := do.Invoke[domain.SessionService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	emailService, err := do.Invoke[domain.EmailService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	attemptService, err := do.Invoke[domain.AttemptService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	cloudFlareService, err := do.Invoke[client.CloudFlareService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := do.Invoke[domain.AuditService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &accountService{
+		i:                 i,
+		accountRepository: accountRepository,
+		userRepository:    userRepository,
+		sessionService:    sessionService,
+		emailService:      emailService,
+		attemptService:    attemptService,
+		cloudFlareService: cloudFlareService,
+		auditService:      auditService,
+	}, nil
+}
+
+func (a *accountService) SendDeletionCode(ctx context.Context) error {
+	log := slog.With(
+		slog.String("service", "account"),
+		slog.String("func", "SendDeletionCode"),
+	)
+
+	log.Info("Initializing send account deletion code process")
+
+	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || session == nil {
+		log.Warn("User not found in context")
+		return domain.ErrUserNotFoundInContext
+	}
+
+	if err := a.attemptService.Limit(ctx, domain.AttemptPurposeResendCode, session.Email); err != nil {
+		log.Warn("Send deletion code request rate limited", slog.String("error", err.Error()))
+		return err
+	}
+
+	user, err := a.userRepository.GetByID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get user by ID", slog.String("error", err.Error()))
+		return err
+	}
+
+	if user == nil {
+		log.Warn("User not found")
+		return domain.ErrUserNotFound
+	}
+
+	if err := a.emailService.SendAccountDeletionCode(ctx, *user); err != nil {
+		log.Error("Failed to send account deletion code", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Account deletion code sent successfully")
+	return nil
+}
+
+func (a *accountService) Delete(ctx context.Context, deleteAccountPayload domain.DeleteAccountPayload) error {
+	log := slog.With(
+		slog.String("service", "account"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing account delete process")
+
+	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || session == nil {
+		log.Warn("User not found in context")
+		return domain.ErrUserNotFoundInContext
+	}
+
+	user, err := a.userRepository.GetByID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get user by ID", slog.String("error", err.Error()))
+		return err
+	}
+
+	if user == nil {
+		log.Warn("User not found")
+		return domain.ErrUserNotFound
+	}
+
+	if err := a.reauthenticate(ctx, *user, deleteAccountPayload); err != nil {
+		log.Warn("Account deletion not confirmed", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := a.accountRepository.SoftDelete(ctx, user.ID); err != nil {
+		log.Error("Failed to soft delete account", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := a.sessionService.RevokeUserSessions(ctx, user.ID); err != nil {
+		log.Error("Failed to revoke user sessions", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := a.auditService.Record(ctx, domain.AuditEntry{
+		ActorID:    user.ID,
+		Action:     domain.AuditActionUserDelete,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID.String(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Account deleted successfully", slog.String("userID", user.ID.String()), slog.Time("purgeAfter", time.Now().UTC().Add(a.getPurgeGracePeriod())))
+	return nil
+}
+
+func (a *accountService) Export(ctx context.Context) (*domain.AccountExport, error) {
+	log := slog.With(
+		slog.String("service", "account"),
+		slog.String("func", "Export"),
+	)
+
+	log.Info("Initializing account export process")
+
+	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || session == nil {
+		log.Warn("User not found in context")
+		return nil, domain.ErrUserNotFoundInContext
+	}
+
+	accountData, err := a.accountRepository.GetData(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get account data", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if accountData == nil {
+		log.Warn("User not found")
+		return nil, domain.ErrUserNotFound
+	}
+
+	sessions, err := a.sessionService.GetAll(ctx)
+	if err != nil {
+		log.Error("Failed to get sessions", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Account export executed successfully")
+	return accountData.ToExport(sessions), nil
+}
+
+func (a *accountService) PurgeDeleted(ctx context.Context) (int, error) {
+	log := slog.With(
+		slog.String("service", "account"),
+		slog.String("func", "PurgeDeleted"),
+	)
+
+	log.Info("Initializing deleted accounts purge process")
+
+	users, err := a.accountRepository.GetDeletedBefore(ctx, time.Now().UTC().Add(-a.getPurgeGracePeriod()))
+	if err != nil {
+		log.Error("Failed to get deleted accounts", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	purged := 0
+	for _, user := range users {
+		imageURLs, err := a.accountRepository.Purge(ctx, user.ID)
+		if err != nil {
+			log.Error("Failed to purge account", slog.String("userID", user.ID.String()), slog.String("error", err.Error()))
+			continue
+		}
+
+		for _, imageURL := range imageURLs {
+			if err := a.cloudFlareService.DeleteImage(imageURL); err != nil {
+				log.Error("Failed to delete image from cloud", slog.String("imageURL", imageURL), slog.String("error", err.Error()))
+			}
+		}
+
+		purged++
+	}
+
+	log.Info("Deleted accounts purge executed successfully", slog.Int("purged", purged))
+	return purged, nil
+}
+
+func (a *accountService) reauthenticate(ctx context.Context, user domain.User, deleteAccountPayload domain.DeleteAccountPayload) error {
+	if deleteAccountPayload.Password != "" {
+		return a.attemptService.VerifyPassword(ctx, user, deleteAccountPayload.Password)
+	}
+
+	if deleteAccountPayload.OTP == "" {
+		return domain.ErrReauthenticationRequired
+	}
+
+	if err := a.attemptService.Check(ctx, domain.AttemptPurposeAccountDeletion, user.ID.String()); err != nil {
+		return err
+	}
+
+	OTP, err := a.sessionService.GetOTP(ctx, domain.OTPPurposeAccountDeletion, user.Email)
+	if err != nil {
+		return err
+	}
+
+	if OTP == "" {
+		return domain.ErrOTPExpires
+	}
+
+	if OTP != deleteAccountPayload.OTP {
+		if err := a.attemptService.Fail(ctx, domain.AttemptPurposeAccountDeletion, user.ID.String(), &user); err != nil {
+			return err
+		}
+		return domain.ErrOTPInvalid
+	}
+
+	if err := a.attemptService.Reset(ctx, domain.AttemptPurposeAccountDeletion, user.ID.String()); err != nil {
+		return err
+	}
+
+	return a.sessionService.DeleteOTP(ctx, domain.OTPPurposeAccountDeletion, user.Email)
+}
+
+func (a *accountService) getPurgeGracePeriod() time.Duration {
+	if config.Env.AccountPurgeGraceDays <= 0 {
+		return defaultAccountPurgeGraceDays * 24 * time.Hour
+	}
+	return time.Duration(config.Env.AccountPurgeGraceDays) * 24 * time.Hour
+}
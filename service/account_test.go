@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+)
+
+func (f *fakeSessionService) GetOTP(context.Context, domain.OTPPurpose, string) (string, error) {
+	return f.otp, nil
+}
+
+func (f *fakeSessionService) DeleteOTP(context.Context, domain.OTPPurpose, string) error {
+	f.otp = ""
+	return nil
+}
+
+func TestAccountServiceDeletionCodeKeepsTwoFactorFailures(t *testing.T) {
+	user := domain.User{ID: uuid.New(), Email: "user@example.com"}
+	attemptRepository := newFakeAttemptRepository()
+	accountService := &accountService{
+		sessionService: &fakeSessionService{otp: "123456"},
+		attemptService: &attemptService{attemptRepository: attemptRepository},
+	}
+
+	ctx := context.Background()
+	twoFactorFailures := string(domain.AttemptPurposeOTP) + string(domain.AttemptScopeAccount) + user.ID.String()
+	attemptRepository.failures[twoFactorFailures] = 2
+
+	if err := accountService.reauthenticate(ctx, user, domain.DeleteAccountPayload{OTP: "123456"}); err != nil {
+		t.Fatalf("reauthenticate() error = %v", err)
+	}
+
+	if got := attemptRepository.failures[twoFactorFailures]; got != 2 {
+		t.Errorf("two factor failures after a deletion code = %d, want 2", got)
+	}
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/middleware"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+type adminService struct {
+	i               *do.Injector
+	adminRepository domain.AdminRepository
+	storeRepository domain.StoreRepository
+	userRepository  domain.UserRepository
+	sessionService  domain.SessionService
+	auditService    domain.AuditService
+}
+
+func NewAdminService(i *do.Injector) (domain.AdminService, error) {
+	adminRepository, err := do.Invoke[domain.AdminRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	storeRepository, err := do.Invoke[domain.StoreRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	userRepository, err := do.Invoke[domain.UserRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionService, err := do.Invoke[domain.SessionService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := do.Invoke[domain.AuditService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &adminService{
+		i:               i,
+		adminRepository: adminRepository,
+		storeRepository: storeRepository,
+		userRepository:  userRepository,
+		sessionService:  sessionService,
+		auditService:    auditService,
+	}, nil
+}
+
+func (a *adminService) GetUsers(ctx context.Context, adminSearchPayload domain.AdminSearchPayload) (*domain.AdminUsersResponse, error) {
+	log := slog.With(
+		slog.String("service", "admin"),
+		slog.String("func", "GetUsers"),
+	)
+
+	log.Info("Initializing get users process")
+
+	users, total, err := a.adminRepository.GetUsers(ctx, adminSearchPayload.Search, adminSearchPayload.Offset(), adminSearchPayload.PageSize)
+	if err != nil {
+		log.Error("Failed to get users", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	items := make([]*domain.AdminUserResponse, 0, len(users))
+	for _, user := range users {
+		items = append(items, user.ToAdminResponse())
+	}
+
+	log.Info("Users retrieved successfully", slog.Int64("total", total))
+	return &domain.AdminUsersResponse{
+		Items:    items,
+		Page:     adminSearchPayload.Page,
+		PageSize: adminSearchPayload.PageSize,
+		Total:    total,
+	}, nil
+}
+
+func (a *adminService) GetStores(ctx context.Context, adminStoreSearchPayload domain.AdminStoreSearchPayload) (*domain.AdminStoresResponse, error) {
+	log := slog.With(
+		slog.String("service", "admin"),
+		slog.String("func", "GetStores"),
+	)
+
+	log.Info("Initializing get stores process")
+
+	stores, total, err := a.adminRepository.GetStores(ctx, adminStoreSearchPayload.Search, adminStoreSearchPayload.Deleted, adminStoreSearchPayload.Offset(), adminStoreSearchPayload.PageSize)
+	if err != nil {
+		log.Error("Failed to get stores", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	items := make([]*domain.AdminStoreResponse, 0, len(stores))
+	for _, store := range stores {
+		items = append(items, store.ToAdminResponse())
+	}
+
+	log.Info("Stores retrieved successfully", slog.Int64("total", total))
+	return &domain.AdminStoresResponse{
+		Items:    items,
+		Page:     adminStoreSearchPayload.Page,
+		PageSize: adminStoreSearchPayload.PageSize,
+		Total:    total,
+	}, nil
+}
+
+func (a *adminService) SuspendUser(ctx context.Context, userID uuid.UUID) error {
+	log := slog.With(
+		slog.String("service", "admin"),
+		slog.String("func", "SuspendUser"),
+	)
+
+	log.Info("Initializing suspend user process")
+
+	user, err := a.getModeratableUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.IsSuspended() {
+		log.Warn("User already suspended")
+		return domain.ErrUserAlreadySuspended
+	}
+
+	suspendedAt := time.Now().UTC()
+	if err := a.adminRepository.UpdateSuspension(ctx, userID, &suspendedAt); err != nil {
+		log.Error("Failed to suspend user", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := a.sessionService.SuspendUser(ctx, userID); err != nil {
+		log.Error("Failed to revoke user sessions", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := a.storeRepository.DeletePublicCacheByOwner(ctx, userID); err != nil {
+		log.Warn("Failed to invalidate public store cache", slog.String("error", err.Error()))
+	}
+
+	if err := a.auditService.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditActionUserSuspend,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   userID.String(),
+		After:      map[string]any{"suspendedAt": suspendedAt},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("User suspended successfully", slog.String("userID", userID.String()))
+	return nil
+}
+
+func (a *adminService) UnsuspendUser(ctx context.Context, userID uuid.UUID) error {
+	log := slog.With(
+		slog.String("service", "admin"),
+		slog.String("func", "UnsuspendUser"),
+	)
+
+	log.Info("Initializing unsuspend user process")
+
+	user, err := a.getModeratableUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsSuspended() {
+		log.Warn("User is not suspended")
+		return domain.ErrUserNotSuspended
+	}
+
+	if err := a.adminRepository.UpdateSuspension(ctx, userID, nil); err != nil {
+		log.Error("Failed to unsuspend user", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := a.sessionService.UnsuspendUser(ctx, userID); err != nil {
+		log.Error("Failed to lift user session suspension", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := a.auditService.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditActionUserUnsuspend,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   userID.String(),
+		Before:     map[string]any{"suspendedAt": user.SuspendedAt},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("User unsuspended successfully", slog.String("userID", userID.String()))
+	return nil
+}
+
+func (a *adminService) ConfirmUserEmail(ctx context.Context, userID uuid.UUID) error {
+	log := slog.With(
+		slog.String("service", "admin"),
+		slog.String("func", "ConfirmUserEmail"),
+	)
+
+	log.Info("Initializing force email confirmation process")
+
+	user, err := a.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get user by id", slog.String("error", err.Error()))
+		return err
+	}
+
+	if user == nil {
+		log.Warn("User not found")
+		return domain.ErrUserNotFound
+	}
+
+	if user.EmailConfirmed {
+		log.Warn("Email already confirmed")
+		return domain.ErrEmailAlreadyConfirmed
+	}
+
+	if err := a.userRepository.UpdateConfirmEmail(ctx, userID); err != nil {
+		log.Error("Failed to confirm user email", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := a.auditService.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditActionUserConfirmEmail,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   userID.String(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("User email confirmed successfully", slog.String("userID", userID.String()))
+	return nil
+}
+
+func (a *adminService) UpdateUserRole(ctx context.Context, userID uuid.UUID, adminUserRolePayload domain.AdminUserRolePayload) error {
+	log := slog.With(
+		slog.String("service", "admin"),
+		slog.String("func", "UpdateUserRole"),
+	)
+
+	log.Info("Initializing user role update process")
+
+	user, err := a.getModeratableUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Role == adminUserRolePayload.Role {
+		log.Info("User already has this role")
+		return nil
+	}
+
+	if err := a.adminRepository.UpdateRole(ctx, userID, adminUserRolePayload.Role); err != nil {
+		log.Error("Failed to update user role", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := a.sessionService.RevokeUserSessions(ctx, userID); err != nil {
+		log.Error("Failed to revoke user sessions", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := a.auditService.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditActionUserUpdateRole,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   userID.String(),
+		Before:     map[string]any{"role": user.Role},
+		After:      map[string]any{"role": adminUserRolePayload.Role},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("User role updated successfully", slog.String("userID", userID.String()), slog.String("role", string(adminUserRolePayload.Role)))
+	return nil
+}
+
+func (a *adminService) RestoreStore(ctx context.Context, storeID uuid.UUID) (*domain.AdminStoreResponse, error) {
+	log := slog.With(
+		slog.String("service", "admin"),
+		slog.String("func", "RestoreStore"),
+	)
+
+	log.Info("Initializing store restore process")
+
+	store, err := a.adminRepository.GetStoreWithDeleted(ctx, storeID)
+	if err != nil {
+		log.Error("Failed to get store", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if store == nil {
+		log.Warn("Store not found")
+		return nil, domain.ErrStoreNotFound
+	}
+
+	if !store.DeletedAt.Valid {
+		log.Warn("Store is not deleted")
+		return nil, domain.ErrStoreNotDeleted
+	}
+
+	owner, err := a.userRepository.GetByID(ctx, store.UserID)
+	if err != nil {
+		log.Error("Failed to get store owner", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if owner == nil {
+		log.Warn("Store owner not found")
+		return nil, domain.ErrUserNotFound
+	}
+
+	if err := a.storeRepository.Restore(ctx, storeID, store.DeletedAt.Time); err != nil {
+		log.Error("Failed to restore store", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := a.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    storeID,
+		Action:     domain.AuditActionStoreRestore,
+		TargetType: domain.AuditTargetStore,
+		TargetID:   storeID.String(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	store.DeletedAt.Valid = false
+
+	log.Info("Store restored successfully", slog.String("storeID", storeID.String()))
+	return store.ToAdminResponse(), nil
+}
+
+func (a *adminService) getModeratableUser(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	log := slog.With(
+		slog.String("service", "admin"),
+		slog.String("func", "getModeratableUser"),
+	)
+
+	if session, ok := ctx.Value(middleware.UserKey).(*domain.Session); ok && session != nil && session.UserID == userID {
+		log.Warn("Superadmin tried to moderate own account")
+		return nil, domain.ErrCannotModerateSelf
+	}
+
+	user, err := a.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get user by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if user == nil {
+		log.Warn("User not found")
+		return nil, domain.ErrUserNotFound
+	}
+
+	return user, nil
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+const (
+	apiKeyPrefix       = "eca_"
+	apiKeyPrefixLength = 12
+	apiKeyUsedInterval = time.Minute
+)
+
+type apiKeyService struct {
+	i                     *do.Injector
+	apiKeyRepository      domain.APIKeyRepository
+	storeMemberRepository domain.StoreMemberRepository
+	storeAuthorizer       domain.StoreAuthorizer
+	auditService          domain.AuditService
+}
+
+func NewAPIKeyService(i *do.Injector) (domain.APIKeyService, error) {
+	apiKeyRepository, err := do.Invoke[domain.APIKeyRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	storeMemberRepository, err := do.Invoke[domain.StoreMemberRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	storeAuthorizer, err := do.Invoke[domain.StoreAuthorizer](i)
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := do.Invoke[domain.AuditService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiKeyService{
+		i:                     i,
+		apiKeyRepository:      apiKeyRepository,
+		storeMemberRepository: storeMemberRepository,
+		storeAuthorizer:       storeAuthorizer,
+		auditService:          auditService,
+	}, nil
+}
+
+func (a *apiKeyService) Create(ctx context.Context, storeID uuid.UUID, apiKeyPayload domain.APIKeyPayload) (*domain.APIKeyCreatedResponse, error) {
+	log := slog.With(
+		slog.String("service", "apiKey"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing api key creation process")
+
+	storeAccess, err := a.checkStoreOwner(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := secure.GenerateRandomToken(32)
+	if err != nil {
+		log.Error("Failed to generate api key", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	key := apiKeyPrefix + secret
+	apiKey := apiKeyPayload.ToAPIKey(storeID, storeAccess.Session.UserID, key[:apiKeyPrefixLength], secure.HashToken(key))
+
+	if err := a.apiKeyRepository.Create(ctx, *apiKey); err != nil {
+		log.Error("Failed to create api key", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := a.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    storeID,
+		Action:     domain.AuditActionAPIKeyCreate,
+		TargetType: domain.AuditTargetAPIKey,
+		TargetID:   apiKey.ID.String(),
+		After:      apiKey.ToResponse(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("API key created successfully", slog.String("apiKeyID", apiKey.ID.String()))
+	return &domain.APIKeyCreatedResponse{
+		APIKeyResponse: *apiKey.ToResponse().In(storeAccess.Store.Settings.Location()),
+		Key:            key,
+	}, nil
+}
+
+func (a *apiKeyService) GetAll(ctx context.Context, storeID uuid.UUID) ([]*domain.APIKeyResponse, error) {
+	log := slog.With(
+		slog.String("service", "apiKey"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all api keys process")
+
+	storeAccess, err := a.checkStoreOwner(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeys, err := a.apiKeyRepository.GetAll(ctx, storeID)
+	if err != nil {
+		log.Error("Failed to get api keys", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	location := storeAccess.Store.Settings.Location()
+	apiKeysResponse := make([]*domain.APIKeyResponse, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		apiKeysResponse = append(apiKeysResponse, apiKey.ToResponse().In(location))
+	}
+
+	log.Info("API keys retrieved successfully", slog.Int("apiKeyCount", len(apiKeysResponse)))
+	return apiKeysResponse, nil
+}
+
+func (a *apiKeyService) Revoke(ctx context.Context, storeID uuid.UUID, apiKeyID uuid.UUID) error {
+	log := slog.With(
+		slog.String("service", "apiKey"),
+		slog.String("func", "Revoke"),
+	)
+
+	log.Info("Initializing api key revoke process")
+
+	if _, err := a.checkStoreOwner(ctx, storeID); err != nil {
+		return err
+	}
+
+	apiKey, err := a.apiKeyRepository.GetByID(ctx, apiKeyID)
+	if err != nil {
+		log.Error("Failed to get api key", slog.String("error", err.Error()))
+		return err
+	}
+
+	if apiKey == nil || apiKey.StoreID != storeID {
+		log.Warn("API key not found for this store")
+		return domain.ErrAPIKeyNotFound
+	}
+
+	if err := a.apiKeyRepository.Revoke(ctx, apiKeyID); err != nil {
+		log.Error("Failed to revoke api key", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := a.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    storeID,
+		Action:     domain.AuditActionAPIKeyRevoke,
+		TargetType: domain.AuditTargetAPIKey,
+		TargetID:   apiKeyID.String(),
+		Before:     apiKey.ToResponse(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("API key revoked successfully", slog.String("apiKeyID", apiKeyID.String()))
+	return nil
+}
+
+func (a *apiKeyService) Authenticate(ctx context.Context, key string) (*domain.Session, error) {
+	log := slog.With(
+		slog.String("service", "apiKey"),
+		slog.String("func", "Authenticate"),
+	)
+
+	log.Info("Initializing api key authentication process")
+
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		log.Warn("API key has an unknown format")
+		return nil, domain.ErrAPIKeyInvalid
+	}
+
+	apiKey, err := a.apiKeyRepository.GetByHash(ctx, secure.HashToken(key))
+	if err != nil {
+		log.Error("Failed to get api key", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if apiKey == nil {
+		log.Warn("API key not found or revoked")
+		return nil, domain.ErrAPIKeyInvalid
+	}
+
+	if apiKey.User.ID == uuid.Nil {
+		log.Warn("API key creator no longer exists", slog.String("apiKeyID", apiKey.ID.String()))
+		return nil, domain.ErrAPIKeyInvalid
+	}
+
+	if apiKey.User.IsSuspended() {
+		log.Warn("API key creator is suspended", slog.String("apiKeyID", apiKey.ID.String()))
+		return nil, domain.ErrUserSuspended
+	}
+
+	canManage, err := a.creatorCanManage(ctx, *apiKey)
+	if err != nil {
+		log.Error("Failed to check api key creator access", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if !canManage {
+		log.Warn("API key creator lost access to the store", slog.String("apiKeyID", apiKey.ID.String()))
+		return nil, domain.ErrAPIKeyInvalid
+	}
+
+	now := time.Now().UTC()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > apiKeyUsedInterval {
+		if err := a.apiKeyRepository.UpdateLastUsed(ctx, apiKey.ID, now); err != nil {
+			log.Warn("Failed to update api key last used", slog.String("error", err.Error()))
+		}
+	}
+
+	log.Info("API key authenticated successfully", slog.String("apiKeyID", apiKey.ID.String()))
+	return apiKey.ToSession(), nil
+}
+
+// creatorCanManage reports whether the user who created the key could still
+// create it today. A key never outlives its creator's access to the store.
+func (a *apiKeyService) creatorCanManage(ctx context.Context, apiKey domain.APIKey) (bool, error) {
+	if apiKey.Store.UserID == apiKey.UserID {
+		return true, nil
+	}
+
+	storeMember, err := a.storeMemberRepository.GetByStoreAndUser(ctx, apiKey.StoreID, apiKey.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	return storeMember != nil && storeMember.Role.Can(domain.PermissionAPIKeysManage), nil
+}
+
+func (a *apiKeyService) checkStoreOwner(ctx context.Context, storeID uuid.UUID) (*domain.StoreAccess, error) {
+	log := slog.With(
+		slog.String("service", "apiKey"),
+		slog.String("func", "checkStoreOwner"),
+	)
+
+	storeAccess, err := a.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionAPIKeysManage)
+	if err != nil {
+		log.Warn("API key management not authorized", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return storeAccess, nil
+}
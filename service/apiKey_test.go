@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/google/uuid"
+)
+
+type fakeAPIKeyRepository struct {
+	domain.APIKeyRepository
+	apiKeys map[string]domain.APIKey
+}
+
+func (f *fakeAPIKeyRepository) GetByHash(_ context.Context, keyHash string) (*domain.APIKey, error) {
+	apiKey, ok := f.apiKeys[keyHash]
+	if !ok {
+		return nil, nil
+	}
+	return &apiKey, nil
+}
+
+func (f *fakeAPIKeyRepository) UpdateLastUsed(context.Context, uuid.UUID, time.Time) error {
+	return nil
+}
+
+type fakeStoreMemberRepository struct {
+	domain.StoreMemberRepository
+	storeMembers []domain.StoreMember
+}
+
+func (f *fakeStoreMemberRepository) GetByStoreAndUser(_ context.Context, storeID uuid.UUID, userID uuid.UUID) (*domain.StoreMember, error) {
+	for _, storeMember := range f.storeMembers {
+		if storeMember.StoreID == storeID && storeMember.UserID == userID {
+			return &storeMember, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestAPIKeyServiceAuthenticate(t *testing.T) {
+	ownerID := uuid.New()
+	memberID := uuid.New()
+	storeID := uuid.New()
+	suspendedAt := time.Now().UTC()
+
+	tests := []struct {
+		name         string
+		creator      domain.User
+		storeMembers []domain.StoreMember
+		wantErr      error
+	}{
+		{name: "owner", creator: domain.User{ID: ownerID}},
+		{
+			name:         "admin member",
+			creator:      domain.User{ID: memberID},
+			storeMembers: []domain.StoreMember{{StoreID: storeID, UserID: memberID, Role: domain.StoreRoleAdmin}},
+		},
+		{name: "suspended owner", creator: domain.User{ID: ownerID, SuspendedAt: &suspendedAt}, wantErr: domain.ErrUserSuspended},
+		{name: "deleted creator", creator: domain.User{}, wantErr: domain.ErrAPIKeyInvalid},
+		{name: "removed member", creator: domain.User{ID: memberID}, wantErr: domain.ErrAPIKeyInvalid},
+		{
+			name:         "demoted member",
+			creator:      domain.User{ID: memberID},
+			storeMembers: []domain.StoreMember{{StoreID: storeID, UserID: memberID, Role: domain.StoreRoleEditor}},
+			wantErr:      domain.ErrAPIKeyInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := apiKeyPrefix + "secret"
+			creatorID := tt.creator.ID
+			if creatorID == uuid.Nil {
+				creatorID = memberID
+			}
+
+			apiKeyService := &apiKeyService{
+				apiKeyRepository: &fakeAPIKeyRepository{apiKeys: map[string]domain.APIKey{
+					secure.HashToken(key): {
+						ID:      uuid.New(),
+						StoreID: storeID,
+						Store:   domain.Store{ID: storeID, UserID: ownerID},
+						UserID:  creatorID,
+						User:    tt.creator,
+						Scopes:  domain.ScopeStoresRead,
+					},
+				}},
+				storeMemberRepository: &fakeStoreMemberRepository{storeMembers: tt.storeMembers},
+			}
+
+			session, err := apiKeyService.Authenticate(context.Background(), key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && (session == nil || session.StoreID != storeID) {
+				t.Errorf("Authenticate() session = %+v, want a session for store %s", session, storeID)
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/middleware"
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/samber/do"
+)
+
+const (
+	defaultLockoutMaxAttempts   = 5
+	defaultLockoutIPMaxAttempts = 20
+	defaultLockoutDuration      = 15
+	throttleAfterFailures       = 3
+	defaultRequestRateLimit     = 5
+	defaultRequestRateIPLimit   = 20
+	defaultRequestRateWindow    = 15
+	maxThrottleDelay            = 30 * time.Second
+)
+
+type attemptService struct {
+	i                 *do.Injector
+	attemptRepository domain.AttemptRepository
+	emailService      domain.EmailService
+}
+
+func NewAttemptService(i *do.Injector) (domain.AttemptService, error) {
+	attemptRepository, err := do.Invoke[domain.AttemptRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	emailService, err := do.Invoke[domain.EmailService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &attemptService{
+		i:                 i,
+		attemptRepository: attemptRepository,
+		emailService:      emailService,
+	}, nil
+}
+
+func (a *attemptService) Check(ctx context.Context, purpose domain.AttemptPurpose, account string) error {
+	log := slog.With(
+		slog.String("service", "attempt"),
+		slog.String("func", "Check"),
+	)
+
+	account = a.normalizeAccount(account)
+
+	lock, err := a.attemptRepository.GetLock(ctx, purpose, domain.AttemptScopeAccount, account)
+	if err != nil {
+		log.Error("Failed to get account lock", slog.String("error", err.Error()))
+		return err
+	}
+
+	if lock > 0 {
+		log.Warn("Account locked", slog.String("purpose", string(purpose)))
+		return &domain.AttemptBlockedError{Err: domain.ErrAccountLocked, RetryAfter: lock}
+	}
+
+	if ip := a.getIP(ctx); ip != "" {
+		lock, err := a.attemptRepository.GetLock(ctx, purpose, domain.AttemptScopeIP, ip)
+		if err != nil {
+			log.Error("Failed to get ip lock", slog.String("error", err.Error()))
+			return err
+		}
+
+		if lock > 0 {
+			log.Warn("IP locked", slog.String("purpose", string(purpose)), slog.String("ip", ip))
+			return &domain.AttemptBlockedError{Err: domain.ErrIPLocked, RetryAfter: lock}
+		}
+	}
+
+	throttle, err := a.attemptRepository.GetThrottle(ctx, purpose, account)
+	if err != nil {
+		log.Error("Failed to get throttle", slog.String("error", err.Error()))
+		return err
+	}
+
+	if throttle > 0 {
+		log.Warn("Attempt throttled", slog.String("purpose", string(purpose)))
+		return &domain.AttemptBlockedError{Err: domain.ErrAttemptThrottled, RetryAfter: throttle}
+	}
+
+	return nil
+}
+
+func (a *attemptService) Fail(ctx context.Context, purpose domain.AttemptPurpose, account string, user *domain.User) error {
+	log := slog.With(
+		slog.String("service", "attempt"),
+		slog.String("func", "Fail"),
+	)
+
+	log.Info("Initializing register failed attempt process")
+
+	account = a.normalizeAccount(account)
+	lockoutDuration := a.getLockoutDuration()
+
+	if ip := a.getIP(ctx); ip != "" {
+		ipFailures, err := a.attemptRepository.IncrementFailures(ctx, purpose, domain.AttemptScopeIP, ip, lockoutDuration)
+		if err != nil {
+			log.Error("Failed to increment ip failures", slog.String("error", err.Error()))
+			return err
+		}
+
+		if ipFailures >= int64(a.getIPMaxAttempts()) {
+			if err := a.attemptRepository.SaveLock(ctx, purpose, domain.AttemptScopeIP, ip, lockoutDuration); err != nil {
+				log.Error("Failed to lock ip", slog.String("error", err.Error()))
+				return err
+			}
+
+			if err := a.attemptRepository.DeleteFailures(ctx, purpose, domain.AttemptScopeIP, ip); err != nil {
+				log.Error("Failed to delete ip failures", slog.String("error", err.Error()))
+				return err
+			}
+
+			log.Warn("IP locked after too many failures", slog.String("purpose", string(purpose)), slog.String("ip", ip))
+			return &domain.AttemptBlockedError{Err: domain.ErrIPLocked, RetryAfter: lockoutDuration}
+		}
+	}
+
+	failures, err := a.attemptRepository.IncrementFailures(ctx, purpose, domain.AttemptScopeAccount, account, lockoutDuration)
+	if err != nil {
+		log.Error("Failed to increment account failures", slog.String("error", err.Error()))
+		return err
+	}
+
+	if failures >= int64(a.getMaxAttempts()) {
+		if err := a.attemptRepository.SaveLock(ctx, purpose, domain.AttemptScopeAccount, account, lockoutDuration); err != nil {
+			log.Error("Failed to lock account", slog.String("error", err.Error()))
+			return err
+		}
+
+		if err := a.attemptRepository.DeleteFailures(ctx, purpose, domain.AttemptScopeAccount, account); err != nil {
+			log.Error("Failed to delete account failures", slog.String("error", err.Error()))
+			return err
+		}
+
+		if user != nil {
+			if err := a.sendUnlockLink(ctx, purpose, account, *user, lockoutDuration); err != nil {
+				log.Error("Failed to send unlock link", slog.String("error", err.Error()))
+			}
+		}
+
+		log.Warn("Account locked after too many failures", slog.String("purpose", string(purpose)))
+		return &domain.AttemptBlockedError{Err: domain.ErrAccountLocked, RetryAfter: lockoutDuration}
+	}
+
+	if failures >= throttleAfterFailures {
+		delay := time.Second << (failures - throttleAfterFailures)
+		if delay > maxThrottleDelay {
+			delay = maxThrottleDelay
+		}
+
+		if err := a.attemptRepository.SaveThrottle(ctx, purpose, account, delay); err != nil {
+			log.Error("Failed to save throttle", slog.String("error", err.Error()))
+			return err
+		}
+	}
+
+	log.Info("Failed attempt registered", slog.String("purpose", string(purpose)), slog.Int64("failures", failures))
+	return nil
+}
+
+func (a *attemptService) Reset(ctx context.Context, purpose domain.AttemptPurpose, account string) error {
+	log := slog.With(
+		slog.String("service", "attempt"),
+		slog.String("func", "Reset"),
+	)
+
+	if err := a.attemptRepository.DeleteFailures(ctx, purpose, domain.AttemptScopeAccount, a.normalizeAccount(account)); err != nil {
+		log.Error("Failed to reset failures", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+// Limit counts requests that send an email or a code in a fixed window per
+// account and per IP. Unlike Fail it never locks the account, so a flood of
+// requests cannot keep the owner from signing in.
+func (a *attemptService) Limit(ctx context.Context, purpose domain.AttemptPurpose, account string) error {
+	log := slog.With(
+		slog.String("service", "attempt"),
+		slog.String("func", "Limit"),
+	)
+
+	window := a.getRequestRateWindow()
+
+	if ip := a.getIP(ctx); ip != "" {
+		requests, retryAfter, err := a.attemptRepository.IncrementRequests(ctx, purpose, domain.AttemptScopeIP, ip, window)
+		if err != nil {
+			log.Error("Failed to increment ip requests", slog.String("error", err.Error()))
+			return err
+		}
+
+		if requests > int64(a.getRequestRateIPLimit()) {
+			log.Warn("IP request rate exceeded", slog.String("purpose", string(purpose)), slog.String("ip", ip))
+			return &domain.AttemptBlockedError{Err: domain.ErrRequestRateLimited, RetryAfter: retryAfter}
+		}
+	}
+
+	requests, retryAfter, err := a.attemptRepository.IncrementRequests(ctx, purpose, domain.AttemptScopeAccount, a.normalizeAccount(account), window)
+	if err != nil {
+		log.Error("Failed to increment account requests", slog.String("error", err.Error()))
+		return err
+	}
+
+	if requests > int64(a.getRequestRateLimit()) {
+		log.Warn("Account request rate exceeded", slog.String("purpose", string(purpose)))
+		return &domain.AttemptBlockedError{Err: domain.ErrRequestRateLimited, RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// VerifyPassword checks the password of a signed in user against the sign in
+// limiter, so reauthentication cannot be used to guess it without lockout.
+func (a *attemptService) VerifyPassword(ctx context.Context, user domain.User, password string) error {
+	log := slog.With(
+		slog.String("service", "attempt"),
+		slog.String("func", "VerifyPassword"),
+	)
+
+	if err := a.Check(ctx, domain.AttemptPurposeSignIn, user.Email); err != nil {
+		log.Warn("Password attempt blocked", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := secure.CheckPassword(user.PasswordHash, password); err != nil {
+		log.Warn("Invalid password")
+		if err := a.Fail(ctx, domain.AttemptPurposeSignIn, user.Email, &user); err != nil {
+			return err
+		}
+		return domain.ErrInvalidPassword
+	}
+
+	return a.Reset(ctx, domain.AttemptPurposeSignIn, user.Email)
+}
+
+func (a *attemptService) Unlock(ctx context.Context, unlockAccountPayload domain.UnlockAccountPayload) error {
+	log := slog.With(
+		slog.String("service", "attempt"),
+		slog.String("func", "Unlock"),
+	)
+
+	log.Info("Initializing account unlock process")
+
+	unlockToken, err := a.attemptRepository.GetUnlockToken(ctx, secure.HashToken(unlockAccountPayload.Token))
+	if err != nil {
+		log.Error("Failed to get unlock token", slog.String("error", err.Error()))
+		return err
+	}
+
+	if unlockToken == nil {
+		log.Warn("Unlock token not found or expired")
+		return domain.ErrUnlockTokenInvalid
+	}
+
+	if err := a.attemptRepository.DeleteLock(ctx, unlockToken.Purpose, domain.AttemptScopeAccount, unlockToken.Account); err != nil {
+		log.Error("Failed to delete account lock", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := a.attemptRepository.DeleteFailures(ctx, unlockToken.Purpose, domain.AttemptScopeAccount, unlockToken.Account); err != nil {
+		log.Error("Failed to delete account failures", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Account unlocked successfully", slog.String("purpose", string(unlockToken.Purpose)))
+	return nil
+}
+
+func (a *attemptService) sendUnlockLink(ctx context.Context, purpose domain.AttemptPurpose, account string, user domain.User, expiration time.Duration) error {
+	token, err := secure.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	unlockToken := domain.UnlockToken{
+		Purpose: purpose,
+		Account: account,
+	}
+
+	if err := a.attemptRepository.SaveUnlockToken(ctx, secure.HashToken(token), unlockToken, expiration); err != nil {
+		return err
+	}
+
+	unlockURL := fmt.Sprintf("%s/unlock?token=%s", strings.TrimSuffix(config.Env.URLFront, "/"), url.QueryEscape(token))
+	return a.emailService.SendUnlockLink(ctx, user, unlockURL)
+}
+
+func (a *attemptService) normalizeAccount(account string) string {
+	return strings.ToLower(strings.TrimSpace(account))
+}
+
+func (a *attemptService) getIP(ctx context.Context) string {
+	clientInfo, ok := ctx.Value(middleware.ClientInfoKey).(*domain.ClientInfo)
+	if !ok || clientInfo == nil {
+		return ""
+	}
+	return clientInfo.IP
+}
+
+func (a *attemptService) getMaxAttempts() int {
+	if config.Env.LockoutMaxAttempts <= 0 {
+		return defaultLockoutMaxAttempts
+	}
+	return config.Env.LockoutMaxAttempts
+}
+
+func (a *attemptService) getIPMaxAttempts() int {
+	if config.Env.LockoutIPMaxAttempts <= 0 {
+		return defaultLockoutIPMaxAttempts
+	}
+	return config.Env.LockoutIPMaxAttempts
+}
+
+func (a *attemptService) getLockoutDuration() time.Duration {
+	if config.Env.LockoutDuration <= 0 {
+		return defaultLockoutDuration * time.Minute
+	}
+	return time.Duration(config.Env.LockoutDuration) * time.Minute
+}
+
+func (a *attemptService) getRequestRateLimit() int {
+	if config.Env.RequestRateLimit <= 0 {
+		return defaultRequestRateLimit
+	}
+	return config.Env.RequestRateLimit
+}
+
+func (a *attemptService) getRequestRateIPLimit() int {
+	if config.Env.RequestRateIPLimit <= 0 {
+		return defaultRequestRateIPLimit
+	}
+	return config.Env.RequestRateIPLimit
+}
+
+func (a *attemptService) getRequestRateWindow() time.Duration {
+	if config.Env.RequestRateWindow <= 0 {
+		return defaultRequestRateWindow * time.Minute
+	}
+	return time.Duration(config.Env.RequestRateWindow) * time.Minute
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/middleware"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+const auditExportBatchSize = 500
+
+type auditService struct {
+	i               *do.Injector
+	auditRepository domain.AuditRepository
+	storeAuthorizer domain.StoreAuthorizer
+}
+
+func NewAuditService(i *do.Injector) (domain.AuditService, error) {
+	auditRepository, err := do.Invoke[domain.AuditRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	storeAuthorizer, err := do.Invoke[domain.StoreAuthorizer](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auditService{
+		i:               i,
+		auditRepository: auditRepository,
+		storeAuthorizer: storeAuthorizer,
+	}, nil
+}
+
+func (a *auditService) Record(ctx context.Context, auditEntry domain.AuditEntry) error {
+	log := slog.With(
+		slog.String("service", "audit"),
+		slog.String("func", "Record"),
+	)
+
+	before, after, err := auditEntry.Diff()
+	if err != nil {
+		log.Error("Failed to build audit diff", slog.String("error", err.Error()))
+		return err
+	}
+
+	auditEvent := domain.AuditEvent{
+		ID:         uuid.New(),
+		ActorType:  domain.AuditActorSystem,
+		Action:     auditEntry.Action,
+		TargetType: auditEntry.TargetType,
+		TargetID:   auditEntry.TargetID,
+		Before:     before,
+		After:      after,
+		CreatedAt:  time.Now().UTC().Truncate(time.Millisecond),
+	}
+
+	if auditEntry.StoreID != uuid.Nil {
+		auditEvent.StoreID = &auditEntry.StoreID
+	}
+
+	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	switch {
+	case ok && session != nil && session.IsAPIKey():
+		auditEvent.ActorType = domain.AuditActorAPIKey
+		auditEvent.ActorID = &session.APIKeyID
+	case ok && session != nil:
+		auditEvent.ActorType = domain.AuditActorUser
+		auditEvent.ActorID = &session.UserID
+	case auditEntry.ActorID != uuid.Nil:
+		auditEvent.ActorType = domain.AuditActorUser
+		auditEvent.ActorID = &auditEntry.ActorID
+	case ctx.Value(middleware.AdminKeyKey) != nil:
+		auditEvent.ActorType = domain.AuditActorAdminKey
+	}
+
+	if clientInfo, ok := ctx.Value(middleware.ClientInfoKey).(*domain.ClientInfo); ok && clientInfo != nil {
+		auditEvent.IP = clientInfo.IP
+		auditEvent.RequestID = clientInfo.RequestID
+	}
+
+	if err := a.auditRepository.Create(ctx, auditEvent); err != nil {
+		log.Error("Failed to save audit event", slog.String("error", err.Error()), slog.String("action", string(auditEntry.Action)))
+		return err
+	}
+
+	return nil
+}
+
+func (a *auditService) GetAll(ctx context.Context, storeID uuid.UUID, auditQueryPayload domain.AuditQueryPayload) (*domain.AuditEventsResponse, error) {
+	log := slog.With(
+		slog.String("service", "audit"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all audit events process")
+
+	storeAccess, err := a.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionAuditRead)
+	if err != nil {
+		log.Warn("Audit listing not authorized", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var auditCursor *domain.AuditCursor
+	if auditQueryPayload.Cursor != "" {
+		cursor, err := domain.DecodeAuditCursor(auditQueryPayload.Cursor)
+		if err != nil {
+			log.Warn("Invalid audit cursor")
+			return nil, err
+		}
+		auditCursor = cursor
+	}
+
+	auditEvents, err := a.auditRepository.GetAll(ctx, storeID, auditQueryPayload.ToFilter(), auditCursor, auditQueryPayload.Limit+1)
+	if err != nil {
+		log.Error("Failed to get audit events", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	auditEventsResponse := &domain.AuditEventsResponse{
+		Items: make([]*domain.AuditEventResponse, 0, len(auditEvents)),
+	}
+
+	if len(auditEvents) > auditQueryPayload.Limit {
+		auditEvents = auditEvents[:auditQueryPayload.Limit]
+		auditEventsResponse.NextCursor = auditEvents[len(auditEvents)-1].Cursor()
+	}
+
+	location := storeAccess.Store.Settings.Location()
+	for _, auditEvent := range auditEvents {
+		auditEventsResponse.Items = append(auditEventsResponse.Items, auditEvent.ToResponse().In(location))
+	}
+
+	log.Info("Audit events retrieved successfully", slog.Int("eventCount", len(auditEventsResponse.Items)))
+	return auditEventsResponse, nil
+}
+
+func (a *auditService) Export(ctx context.Context, storeID uuid.UUID, auditQueryPayload domain.AuditQueryPayload) ([]*domain.AuditEventResponse, error) {
+	log := slog.With(
+		slog.String("service", "audit"),
+		slog.String("func", "Export"),
+	)
+
+	log.Info("Initializing audit export process")
+
+	storeAccess, err := a.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionAuditRead)
+	if err != nil {
+		log.Warn("Audit export not authorized", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var auditCursor *domain.AuditCursor
+	if auditQueryPayload.Cursor != "" {
+		cursor, err := domain.DecodeAuditCursor(auditQueryPayload.Cursor)
+		if err != nil {
+			log.Warn("Invalid audit cursor")
+			return nil, err
+		}
+		auditCursor = cursor
+	}
+
+	auditFilter := auditQueryPayload.ToFilter()
+	auditEventsResponse := make([]*domain.AuditEventResponse, 0)
+	location := storeAccess.Store.Settings.Location()
+
+	for len(auditEventsResponse) < domain.MaxAuditExportRows {
+		auditEvents, err := a.auditRepository.GetAll(ctx, storeID, auditFilter, auditCursor, auditExportBatchSize)
+		if err != nil {
+			log.Error("Failed to get audit events", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		for _, auditEvent := range auditEvents {
+			if len(auditEventsResponse) == domain.MaxAuditExportRows {
+				break
+			}
+			auditEventsResponse = append(auditEventsResponse, auditEvent.ToResponse().In(location))
+		}
+
+		if len(auditEvents) < auditExportBatchSize {
+			break
+		}
+
+		lastAuditEvent := auditEvents[len(auditEvents)-1]
+		auditCursor = &domain.AuditCursor{CreatedAt: lastAuditEvent.CreatedAt, ID: lastAuditEvent.ID}
+	}
+
+	log.Info("Audit export executed successfully", slog.Int("eventCount", len(auditEventsResponse)))
+	return auditEventsResponse, nil
+}
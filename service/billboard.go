@@ -6,7 +6,6 @@ import (
 
 	"github.com/GSVillas/e-commercer-api/client"
 	"github.com/GSVillas/e-commercer-api/domain"
-	"github.com/GSVillas/e-commercer-api/middleware"
 	"github.com/google/uuid"
 	"github.com/samber/do"
 )
@@ -14,8 +13,9 @@ import (
 type billboardService struct {
 	i                   *do.Injector
 	billboardRepository domain.BillboardRepository
-	storeRepository     domain.StoreRepository
+	storeAuthorizer     domain.StoreAuthorizer
 	cloudFlareService   client.CloudFlareService
+	auditService        domain.AuditService
 }
 
 func NewBillboardService(i *do.Injector) (domain.BillboardService, error) {
@@ -24,7 +24,7 @@ func NewBillboardService(i *do.Injector) (domain.BillboardService, error) {
 		return nil, err
 	}
 
-	storeRepository, err := do.Invoke[domain.StoreRepository](i)
+	storeAuthorizer, err := do.Invoke[domain.StoreAuthorizer](i)
 	if err != nil {
 		return nil, err
 	}
@@ -34,11 +34,17 @@ func NewBillboardService(i *do.Injector) (domain.BillboardService, error) {
 		return nil, err
 	}
 
+	auditService, err := do.Invoke[domain.AuditService](i)
+	if err != nil {
+		return nil, err
+	}
+
 	return &billboardService{
 		i:                   i,
 		billboardRepository: billboardRepository,
-		storeRepository:     storeRepository,
+		storeAuthorizer:     storeAuthorizer,
 		cloudFlareService:   cloudFlareService,
+		auditService:        auditService,
 	}, nil
 }
 
@@ -50,27 +56,12 @@ func (b *billboardService) Create(ctx context.Context, storeID uuid.UUID, billbo
 
 	log.Info("Initializing create billboard process")
 
-	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
-	if !ok || session == nil {
-		return nil, domain.ErrUserNotFoundInContext
-	}
-
-	store, err := b.storeRepository.GetByID(ctx, storeID)
+	storeAccess, err := b.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionBillboardsWrite)
 	if err != nil {
-		log.Error("Failed to get store by id", slog.String("error", err.Error()))
+		log.Warn("Billboard creation not authorized", slog.String("error", err.Error()))
 		return nil, err
 	}
 
-	if store == nil {
-		log.Warn("store not found with this id")
-		return nil, domain.ErrStoreNotFound
-	}
-
-	if store.UserID != session.UserID {
-		log.Error("Unauthorized attempt to delete store", slog.String("storeID", store.ID.String()), slog.String("userID", session.UserID.String()))
-		return nil, domain.ErrUnauthorizedAction
-	}
-
 	imageURL, err := b.cloudFlareService.UploadImage(billboardPayload.Image)
 	if err != nil {
 		log.Error("Error to upload image in cloud", slog.String("error", err.Error()))
@@ -84,6 +75,16 @@ func (b *billboardService) Create(ctx context.Context, storeID uuid.UUID, billbo
 		return nil, err
 	}
 
+	if err := b.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    storeID,
+		Action:     domain.AuditActionBillboardCreate,
+		TargetType: domain.AuditTargetBillboard,
+		TargetID:   billboard.ID.String(),
+		After:      billboard.ToResponse(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
 	log.Info("Create billboard process executed succefully")
-	return billboard.ToResponse(), nil
+	return billboard.ToResponse().In(storeAccess.Store.Settings.Location()), nil
 }
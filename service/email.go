@@ -5,6 +5,7 @@ import (
 	"html/template"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/GSVillas/e-commercer-api/domain"
@@ -45,64 +46,268 @@ func (e *emailService) SendConfirmationCode(ctx context.Context, user domain.Use
 
 	log.Info("Initializing send connfirmation code in process")
 
-	key, err := secure.GenerateSecret(user.Email)
+	if err := e.sendOTP(ctx, user, domain.OTPPurposeEmailConfirmation, "otp_template.html", "Your OTP Code"); err != nil {
+		log.Error("Failed to send OTP email", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("OTP sent successfully")
+	return nil
+}
+
+func (e *emailService) SendPasswordResetCode(ctx context.Context, user domain.User) error {
+	log := slog.With(
+		slog.String("service", "userEmail"),
+		slog.String("func", "SendPasswordResetCode"),
+	)
+
+	log.Info("Initializing send password reset code process")
+
+	if err := e.sendOTP(ctx, user, domain.OTPPurposePasswordReset, "password_reset_template.html", "Reset your password"); err != nil {
+		log.Error("Failed to send password reset email", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Password reset code sent successfully")
+	return nil
+}
+
+func (e *emailService) SendAccountDeletionCode(ctx context.Context, user domain.User) error {
+	log := slog.With(
+		slog.String("service", "userEmail"),
+		slog.String("func", "SendAccountDeletionCode"),
+	)
+
+	log.Info("Initializing send account deletion code process")
+
+	if err := e.sendOTP(ctx, user, domain.OTPPurposeAccountDeletion, "account_deletion_template.html", "Confirm account deletion"); err != nil {
+		log.Error("Failed to send account deletion email", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Account deletion code sent successfully")
+	return nil
+}
+
+func (e *emailService) SendUnlockLink(ctx context.Context, user domain.User, unlockURL string) error {
+	log := slog.With(
+		slog.String("service", "userEmail"),
+		slog.String("func", "SendUnlockLink"),
+	)
+
+	log.Info("Initializing send unlock link process")
+
+	if err := e.sendLink(user, unlockURL, "unlock_template.html", "Your account has been temporarily locked"); err != nil {
+		log.Error("Failed to send unlock email", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Unlock link sent successfully")
+	return nil
+}
+
+func (e *emailService) SendMagicLink(ctx context.Context, user domain.User, magicLinkURL string) error {
+	log := slog.With(
+		slog.String("service", "userEmail"),
+		slog.String("func", "SendMagicLink"),
+	)
+
+	log.Info("Initializing send magic link process")
+
+	if err := e.sendLink(user, magicLinkURL, "magic_link_template.html", "Your sign in link"); err != nil {
+		log.Error("Failed to send magic link email", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Magic link sent successfully")
+	return nil
+}
+
+func (e *emailService) SendEmailChangeCode(ctx context.Context, user domain.User, newEmail string) error {
+	log := slog.With(
+		slog.String("service", "userEmail"),
+		slog.String("func", "SendEmailChangeCode"),
+	)
+
+	log.Info("Initializing send email change code process")
+
+	user.Email = newEmail
+	if err := e.sendOTP(ctx, user, domain.OTPPurposeEmailChange, "email_change_template.html", "Confirm your new email"); err != nil {
+		log.Error("Failed to send email change code", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Email change code sent successfully")
+	return nil
+}
+
+func (e *emailService) SendEmailChangedNotice(ctx context.Context, user domain.User, newEmail string, cancelURL string) error {
+	log := slog.With(
+		slog.String("service", "userEmail"),
+		slog.String("func", "SendEmailChangedNotice"),
+	)
+
+	log.Info("Initializing send email changed notice process")
+
+	body, err := e.renderTemplate("email_changed_template.html", domain.EmailChangedPayload{
+		Name:     user.Name,
+		NewEmail: newEmail,
+		URL:      cancelURL,
+	})
 	if err != nil {
-		log.Error("Failed to generate key user", slog.String("error", err.Error()))
+		log.Error("Failed to render email changed template", slog.String("error", err.Error()))
 		return err
 	}
 
-	OTP, err := secure.GenerateNumericOTP(key)
+	emailReq := domain.SendEmailRequest{
+		From:    "Acme <onboarding@resend.dev>",
+		To:      []string{user.Email},
+		Subject: "The email of your account was changed",
+		Html:    body,
+	}
+
+	if _, err := e.sendEmail(emailReq); err != nil {
+		log.Error("Failed to send email changed notice", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Email changed notice sent successfully")
+	return nil
+}
+
+func (e *emailService) SendNewDeviceNotice(ctx context.Context, user domain.User, newDeviceEmailPayload domain.NewDeviceEmailPayload) error {
+	log := slog.With(
+		slog.String("service", "userEmail"),
+		slog.String("func", "SendNewDeviceNotice"),
+	)
+
+	log.Info("Initializing send new device notice process")
+
+	newDeviceEmailPayload.Name = user.Name
+	body, err := e.renderTemplate("new_device_template.html", newDeviceEmailPayload)
 	if err != nil {
-		log.Error("Failed to generate OTP", slog.String("error", err.Error()))
+		log.Error("Failed to render new device template", slog.String("error", err.Error()))
 		return err
 	}
 
-	err = e.userSessionService.SaveOTP(ctx, user.Email, OTP)
+	emailReq := domain.SendEmailRequest{
+		From:    "Acme <onboarding@resend.dev>",
+		To:      []string{user.Email},
+		Subject: "New sign-in to your account",
+		Html:    body,
+	}
+
+	if _, err := e.sendEmail(emailReq); err != nil {
+		log.Error("Failed to send new device notice", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("New device notice sent successfully")
+	return nil
+}
+
+func (e *emailService) SendStoreInvite(ctx context.Context, email string, storeInviteEmailPayload domain.StoreInviteEmailPayload) error {
+	log := slog.With(
+		slog.String("service", "userEmail"),
+		slog.String("func", "SendStoreInvite"),
+	)
+
+	log.Info("Initializing send store invite process")
+
+	body, err := e.renderTemplate("store_invite_template.html", storeInviteEmailPayload)
 	if err != nil {
-		log.Error("Failed to save OTP", slog.String("error", err.Error()))
+		log.Error("Failed to render store invite template", slog.String("error", err.Error()))
 		return err
 	}
 
-	tmpl, err := os.ReadFile("templates\\otp_template.html")
+	emailReq := domain.SendEmailRequest{
+		From:    "Acme <onboarding@resend.dev>",
+		To:      []string{email},
+		Subject: "You have been invited to join " + storeInviteEmailPayload.StoreName,
+		Html:    body,
+		ReplyTo: storeInviteEmailPayload.SupportEmail,
+	}
+
+	if _, err := e.sendEmail(emailReq); err != nil {
+		log.Error("Failed to send store invite", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Store invite sent successfully")
+	return nil
+}
+
+func (e *emailService) sendLink(user domain.User, URL string, templateName string, subject string) error {
+	body, err := e.renderTemplate(templateName, domain.LinkEmailPayload{
+		Name: user.Name,
+		URL:  URL,
+	})
 	if err != nil {
-		log.Error("Failed to read email template", slog.String("error", err.Error()))
 		return err
 	}
 
-	t, err := template.New("emailTemplate").Parse(string(tmpl))
+	emailReq := domain.SendEmailRequest{
+		From:    "Acme <onboarding@resend.dev>",
+		To:      []string{user.Email},
+		Subject: subject,
+		Html:    body,
+	}
+
+	_, err = e.sendEmail(emailReq)
+	return err
+}
+
+func (e *emailService) sendOTP(ctx context.Context, user domain.User, purpose domain.OTPPurpose, templateName string, subject string) error {
+	key, err := secure.GenerateSecret(user.Email)
 	if err != nil {
-		log.Error("Failed to parse email template", slog.String("error", err.Error()))
 		return err
 	}
 
-	userOTPEmail := domain.OTPEmailPayload{
-		Name: user.Name,
-		OTP:  OTP,
+	OTP, err := secure.GenerateNumericOTP(key)
+	if err != nil {
+		return err
 	}
 
-	var body strings.Builder
-	err = t.Execute(&body, userOTPEmail)
+	if err := e.userSessionService.SaveOTP(ctx, purpose, user.Email, OTP); err != nil {
+		return err
+	}
+
+	body, err := e.renderTemplate(templateName, domain.OTPEmailPayload{
+		Name: user.Name,
+		OTP:  OTP,
+	})
 	if err != nil {
-		log.Error("Failed to execute email template", slog.String("error", err.Error()))
 		return err
 	}
 
 	emailReq := domain.SendEmailRequest{
 		From:    "Acme <onboarding@resend.dev>",
 		To:      []string{user.Email},
-		Subject: "Your OTP Code",
-		Html:    body.String(),
+		Subject: subject,
+		Html:    body,
 	}
 
 	_, err = e.sendEmail(emailReq)
+	return err
+}
+
+func (e *emailService) renderTemplate(templateName string, data any) (string, error) {
+	tmpl, err := os.ReadFile(filepath.Join("templates", templateName))
 	if err != nil {
-		log.Error("Failed to send OTP email", slog.String("error", err.Error()))
-		return err
+		return "", err
 	}
 
-	log.Info("OTP sent successfully")
-	return nil
+	t, err := template.New("emailTemplate").Parse(string(tmpl))
+	if err != nil {
+		return "", err
+	}
+
+	var body strings.Builder
+	if err := t.Execute(&body, data); err != nil {
+		return "", err
+	}
 
+	return body.String(), nil
 }
 
 func (e *emailService) sendEmail(request domain.SendEmailRequest) (*domain.SendEmailResponse, error) {
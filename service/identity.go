@@ -0,0 +1,460 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/client"
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/middleware"
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+const defaultOIDCProvider = "oidc"
+
+type identityService struct {
+	i                   *do.Injector
+	identityRepository  domain.IdentityRepository
+	userRepository      domain.UserRepository
+	sessionService      domain.SessionService
+	oidcClient          client.OIDCClient
+	auditService        domain.AuditService
+	loginHistoryService domain.LoginHistoryService
+}
+
+func NewIdentityService(i *do.Injector) (domain.IdentityService, error) {
+	identityRepository, err := do.Invoke[domain.IdentityRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	userRepository, err := do.Invoke[domain.UserRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionService, err := do.Invoke[domain.SessionService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	oidcClient, err := do.Invoke[client.OIDCClient](i)
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := do.Invoke[domain.AuditService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	loginHistoryService, err := do.Invoke[domain.LoginHistoryService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &identityService{
+		i:                   i,
+		identityRepository:  identityRepository,
+		userRepository:      userRepository,
+		sessionService:      sessionService,
+		oidcClient:          oidcClient,
+		auditService:        auditService,
+		loginHistoryService: loginHistoryService,
+	}, nil
+}
+
+func (s *identityService) Authorize(ctx context.Context) (*domain.OIDCAuthorizeResponse, error) {
+	return s.authorize(ctx, uuid.Nil)
+}
+
+// AuthorizeLink starts a flow that can only be completed by Link for the
+// signed in user, so an identity is never attached to an account its owner
+// did not authenticate to.
+func (s *identityService) AuthorizeLink(ctx context.Context) (*domain.OIDCAuthorizeResponse, error) {
+	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrUserNotFoundInContext
+	}
+
+	return s.authorize(ctx, session.UserID)
+}
+
+func (s *identityService) authorize(ctx context.Context, userID uuid.UUID) (*domain.OIDCAuthorizeResponse, error) {
+	log := slog.With(
+		slog.String("service", "identity"),
+		slog.String("func", "authorize"),
+	)
+
+	log.Info("Initializing oidc authorize process")
+
+	state, err := secure.GenerateRandomToken(32)
+	if err != nil {
+		log.Error("Failed to generate state", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	nonce, err := secure.GenerateRandomToken(32)
+	if err != nil {
+		log.Error("Failed to generate nonce", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	codeVerifier, codeChallenge, err := secure.GeneratePKCE()
+	if err != nil {
+		log.Error("Failed to generate pkce", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	authorizationURL, err := s.oidcClient.AuthCodeURL(ctx, state, nonce, codeChallenge)
+	if err != nil {
+		log.Error("Failed to build authorization url", slog.String("error", err.Error()))
+		return nil, s.mapClientError(err)
+	}
+
+	oidcState := domain.OIDCState{
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		UserID:       userID,
+	}
+
+	if err := s.identityRepository.SaveState(ctx, oidcState); err != nil {
+		log.Error("Failed to save oidc state", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("OIDC authorization url created successfully")
+	return &domain.OIDCAuthorizeResponse{
+		AuthorizationURL: authorizationURL,
+		State:            state,
+	}, nil
+}
+
+func (s *identityService) Callback(ctx context.Context, oidcCallbackPayload domain.OIDCCallbackPayload) (*domain.SessionResponse, error) {
+	log := slog.With(
+		slog.String("service", "identity"),
+		slog.String("func", "Callback"),
+	)
+
+	log.Info("Initializing oidc callback process")
+
+	claims, err := s.verifyCallback(ctx, oidcCallbackPayload, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.getOrCreateUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TwoFactorEnabled {
+		challengeToken, err := s.sessionService.CreateTwoFactorChallenge(ctx, user.ID)
+		if err != nil {
+			log.Error("Failed to create two factor challenge", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		log.Info("Two factor challenge issued")
+		if err := s.loginHistoryService.Record(ctx, domain.LoginAttempt{
+			UserID: user.ID,
+			Email:  user.Email,
+			Method: domain.LoginMethodOIDC,
+			Result: domain.LoginResultTwoFactorRequired,
+		}); err != nil {
+			log.Error("Failed to record login history", slog.String("error", err.Error()))
+		}
+		return &domain.SessionResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+		}, domain.ErrTwoFactorRequired
+	}
+
+	sessionResponse, err := s.sessionService.Create(ctx, *user)
+	if err != nil {
+		log.Error("Failed to create session", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := s.loginHistoryService.Record(ctx, domain.LoginAttempt{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Method:    domain.LoginMethodOIDC,
+		Result:    domain.LoginResultSuccess,
+		SessionID: sessionResponse.SessionID,
+	}); err != nil {
+		log.Error("Failed to record login history", slog.String("error", err.Error()))
+	}
+
+	log.Info("User signed in with oidc successfully")
+	return sessionResponse, nil
+}
+
+func (s *identityService) getOrCreateUser(ctx context.Context, claims *client.OIDCIDTokenClaims) (*domain.User, error) {
+	log := slog.With(
+		slog.String("service", "identity"),
+		slog.String("func", "getOrCreateUser"),
+	)
+
+	provider := s.getProvider()
+
+	identity, err := s.identityRepository.GetByProviderSubject(ctx, provider, claims.Subject)
+	if err != nil {
+		log.Error("Failed to get identity", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if identity != nil {
+		user, err := s.userRepository.GetByID(ctx, identity.UserID)
+		if err != nil {
+			log.Error("Failed to get user by id", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		if user == nil {
+			log.Warn("Linked user not found")
+			return nil, domain.ErrUserNotFound
+		}
+
+		return user, nil
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		log.Warn("OIDC email missing or not verified")
+		return nil, domain.ErrOIDCEmailNotVerified
+	}
+
+	user, err := s.userRepository.GetByEmail(ctx, claims.Email)
+	if err != nil {
+		log.Error("Failed to get user by email", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if user == nil {
+		user, err = s.createUser(ctx, claims)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Whoever registered this email may not be the person behind the
+		// identity provider account, so the owner has to sign in with the
+		// password and link the identity explicitly.
+		log.Warn("Email belongs to an account without this identity")
+		return nil, domain.ErrOIDCLinkRequired
+	}
+
+	if err := s.linkIdentity(ctx, *user, claims); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *identityService) Link(ctx context.Context, oidcCallbackPayload domain.OIDCCallbackPayload) error {
+	log := slog.With(
+		slog.String("service", "identity"),
+		slog.String("func", "Link"),
+	)
+
+	log.Info("Initializing oidc link process")
+
+	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || session == nil {
+		log.Warn("User not found in context")
+		return domain.ErrUserNotFoundInContext
+	}
+
+	user, err := s.userRepository.GetByID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get user by id", slog.String("error", err.Error()))
+		return err
+	}
+
+	if user == nil {
+		log.Warn("User not found")
+		return domain.ErrUserNotFound
+	}
+
+	claims, err := s.verifyCallback(ctx, oidcCallbackPayload, user.ID)
+	if err != nil {
+		return err
+	}
+
+	identity, err := s.identityRepository.GetByProviderSubject(ctx, s.getProvider(), claims.Subject)
+	if err != nil {
+		log.Error("Failed to get identity", slog.String("error", err.Error()))
+		return err
+	}
+
+	if identity != nil {
+		if identity.UserID != user.ID {
+			log.Warn("Identity linked to another user")
+			return domain.ErrIdentityAlreadyLinked
+		}
+
+		log.Info("Identity already linked")
+		return nil
+	}
+
+	if err := s.linkIdentity(ctx, *user, claims); err != nil {
+		return err
+	}
+
+	log.Info("OIDC link executed successfully")
+	return nil
+}
+
+// verifyCallback consumes the state and checks the provider response. userID
+// is the user the flow was started for, or uuid.Nil for a sign in, so a link
+// flow cannot be finished as a sign in or by another account.
+func (s *identityService) verifyCallback(ctx context.Context, oidcCallbackPayload domain.OIDCCallbackPayload, userID uuid.UUID) (*client.OIDCIDTokenClaims, error) {
+	log := slog.With(
+		slog.String("service", "identity"),
+		slog.String("func", "verifyCallback"),
+	)
+
+	oidcState, err := s.identityRepository.GetState(ctx, oidcCallbackPayload.State)
+	if err != nil {
+		log.Error("Failed to get oidc state", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if oidcState == nil || oidcState.UserID != userID {
+		log.Warn("OIDC state not found or expired")
+		return nil, domain.ErrOIDCStateInvalid
+	}
+
+	tokenResponse, err := s.oidcClient.Exchange(ctx, oidcCallbackPayload.Code, oidcState.CodeVerifier)
+	if err != nil {
+		log.Error("Failed to exchange authorization code", slog.String("error", err.Error()))
+		return nil, s.mapClientError(err)
+	}
+
+	claims, err := s.oidcClient.VerifyIDToken(ctx, tokenResponse.IDToken, oidcState.Nonce)
+	if err != nil {
+		log.Warn("Failed to verify id token", slog.String("error", err.Error()))
+		return nil, s.mapClientError(err)
+	}
+
+	return claims, nil
+}
+
+func (s *identityService) linkIdentity(ctx context.Context, user domain.User, claims *client.OIDCIDTokenClaims) error {
+	log := slog.With(
+		slog.String("service", "identity"),
+		slog.String("func", "linkIdentity"),
+	)
+
+	provider := s.getProvider()
+	identity := domain.UserIdentity{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Provider:  provider,
+		Subject:   claims.Subject,
+		Email:     claims.Email,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := s.identityRepository.Create(ctx, identity); err != nil {
+		log.Error("Failed to link identity", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		ActorID:    user.ID,
+		Action:     domain.AuditActionIdentityLink,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID.String(),
+		After:      map[string]any{"provider": provider, "email": claims.Email},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Identity linked successfully")
+	return nil
+}
+
+func (s *identityService) createUser(ctx context.Context, claims *client.OIDCIDTokenClaims) (*domain.User, error) {
+	log := slog.With(
+		slog.String("service", "identity"),
+		slog.String("func", "createUser"),
+	)
+
+	exists, err := s.userRepository.ExistsByEmail(ctx, claims.Email)
+	if err != nil {
+		log.Error("Failed to check if email exists", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if exists {
+		log.Warn("Email belongs to a deleted account")
+		return nil, domain.ErrUserAlreadyExists
+	}
+
+	password, err := secure.GenerateRandomToken(32)
+	if err != nil {
+		log.Error("Failed to generate password", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	passwordHash, err := secure.Hash(password)
+	if err != nil {
+		log.Error("Failed to hash password", slog.String("error", err.Error()))
+		return nil, domain.ErrHashingPassword
+	}
+
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name = strings.Split(claims.Email, "@")[0]
+	}
+
+	username, err := generateUsername(ctx, s.userRepository, name)
+	if err != nil {
+		log.Error("Failed to generate username", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	user := &domain.User{
+		ID:             uuid.New(),
+		Name:           name,
+		Email:          claims.Email,
+		Username:       username,
+		PasswordHash:   string(passwordHash),
+		EmailConfirmed: true,
+		AvatarURL:      claims.Picture,
+		CreatedAt:      time.Now().UTC(),
+	}
+
+	if err := s.userRepository.Create(ctx, *user); err != nil {
+		log.Error("Failed to create user", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("User created from oidc identity")
+	return user, nil
+}
+
+func (s *identityService) getProvider() string {
+	if config.Env.OIDCProvider == "" {
+		return defaultOIDCProvider
+	}
+	return config.Env.OIDCProvider
+}
+
+func (s *identityService) mapClientError(err error) error {
+	switch {
+	case errors.Is(err, client.ErrOIDCNotConfigured):
+		return domain.ErrOIDCNotConfigured
+	case errors.Is(err, client.ErrOIDCIDTokenInvalid), errors.Is(err, client.ErrOIDCIDTokenMissing), errors.Is(err, client.ErrOIDCExchangeFailed):
+		return domain.ErrOIDCTokenInvalid
+	default:
+		return domain.ErrIdentityProviderFailed
+	}
+}
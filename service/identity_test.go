@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/GSVillas/e-commercer-api/client"
+	"github.com/GSVillas/e-commercer-api/client/oidctest"
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/middleware"
+	"github.com/google/uuid"
+)
+
+// fakeIdentityRepository keeps OIDC state in memory and, like the Redis
+// GETDEL used by the real repository, hands each state out only once.
+type fakeIdentityRepository struct {
+	domain.IdentityRepository
+	mutex      sync.Mutex
+	states     map[string]domain.OIDCState
+	identities []domain.UserIdentity
+}
+
+func (f *fakeIdentityRepository) SaveState(_ context.Context, oidcState domain.OIDCState) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.states[oidcState.State] = oidcState
+	return nil
+}
+
+func (f *fakeIdentityRepository) GetState(_ context.Context, state string) (*domain.OIDCState, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	oidcState, ok := f.states[state]
+	if !ok {
+		return nil, nil
+	}
+	delete(f.states, state)
+	return &oidcState, nil
+}
+
+func (f *fakeIdentityRepository) GetByProviderSubject(_ context.Context, provider string, subject string) (*domain.UserIdentity, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, identity := range f.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return &identity, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeIdentityRepository) Create(_ context.Context, identity domain.UserIdentity) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.identities = append(f.identities, identity)
+	return nil
+}
+
+type fakeUserRepository struct {
+	domain.UserRepository
+	users []domain.User
+}
+
+func (f *fakeUserRepository) GetByID(_ context.Context, id uuid.UUID) (*domain.User, error) {
+	for _, user := range f.users {
+		if user.ID == id {
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeUserRepository) GetByEmail(_ context.Context, email string) (*domain.User, error) {
+	for _, user := range f.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	user, err := f.GetByEmail(ctx, email)
+	return user != nil, err
+}
+
+func (f *fakeUserRepository) ExistsByUsername(_ context.Context, username string) (bool, error) {
+	for _, user := range f.users {
+		if user.Username == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeUserRepository) Create(_ context.Context, user domain.User) error {
+	f.users = append(f.users, user)
+	return nil
+}
+
+type fakeSessionService struct {
+	domain.SessionService
+	revoked []uuid.UUID
+	otp     string
+}
+
+func (f *fakeSessionService) Create(_ context.Context, user domain.User) (*domain.SessionResponse, error) {
+	return &domain.SessionResponse{Token: "token-" + user.ID.String(), SessionID: uuid.New()}, nil
+}
+
+type fakeAuditService struct {
+	domain.AuditService
+}
+
+func (f *fakeAuditService) Record(context.Context, domain.AuditEntry) error {
+	return nil
+}
+
+type fakeLoginHistoryService struct {
+	domain.LoginHistoryService
+}
+
+func (f *fakeLoginHistoryService) Record(context.Context, domain.LoginAttempt) error {
+	return nil
+}
+
+func newTestIdentityService(t *testing.T) (*identityService, *oidctest.Server) {
+	t.Helper()
+
+	server, err := oidctest.NewServer("test-client")
+	if err != nil {
+		t.Fatalf("start fake idp: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	env := config.Env
+	t.Cleanup(func() { config.Env = env })
+	config.Env.OIDCIssuerURL = server.URL
+	config.Env.OIDCClientID = server.ClientID
+	config.Env.OIDCRedirectURL = "https://app.example.com/oidc/callback"
+
+	oidcClient, err := client.NewOIDCClient(nil)
+	if err != nil {
+		t.Fatalf("create oidc client: %v", err)
+	}
+
+	return &identityService{
+		identityRepository:  &fakeIdentityRepository{states: map[string]domain.OIDCState{}},
+		userRepository:      &fakeUserRepository{},
+		sessionService:      &fakeSessionService{},
+		oidcClient:          oidcClient,
+		auditService:        &fakeAuditService{},
+		loginHistoryService: &fakeLoginHistoryService{},
+	}, server
+}
+
+// signIn runs authorize against the service and approves the request at the
+// fake provider, returning the callback payload the frontend would post.
+func signIn(t *testing.T, identityService *identityService, server *oidctest.Server) domain.OIDCCallbackPayload {
+	t.Helper()
+
+	authorizeResponse, err := identityService.Authorize(context.Background())
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+
+	return approve(t, server, authorizeResponse)
+}
+
+// startLink runs the link authorize flow as the signed in user of ctx.
+func startLink(t *testing.T, ctx context.Context, identityService *identityService, server *oidctest.Server) domain.OIDCCallbackPayload {
+	t.Helper()
+
+	authorizeResponse, err := identityService.AuthorizeLink(ctx)
+	if err != nil {
+		t.Fatalf("AuthorizeLink() error = %v", err)
+	}
+
+	return approve(t, server, authorizeResponse)
+}
+
+func approve(t *testing.T, server *oidctest.Server, authorizeResponse *domain.OIDCAuthorizeResponse) domain.OIDCCallbackPayload {
+	t.Helper()
+
+	authorizationURL, err := url.Parse(authorizeResponse.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("parse authorization url: %v", err)
+	}
+
+	query := authorizationURL.Query()
+	if query.Get("state") != authorizeResponse.State || query.Get("nonce") == "" || query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("authorization url is missing state, nonce or pkce: %s", authorizeResponse.AuthorizationURL)
+	}
+
+	code, state, err := server.Authorize(authorizeResponse.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("approve at fake idp: %v", err)
+	}
+
+	return domain.OIDCCallbackPayload{Code: code, State: state}
+}
+
+func TestIdentityServiceCallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(server *oidctest.Server)
+		tamper  func(identityService *identityService, payload *domain.OIDCCallbackPayload)
+		wantErr error
+	}{
+		{name: "signs in"},
+		{
+			name:    "unknown state",
+			tamper:  func(_ *identityService, payload *domain.OIDCCallbackPayload) { payload.State = "forged" },
+			wantErr: domain.ErrOIDCStateInvalid,
+		},
+		{
+			name: "pkce verifier mismatch",
+			tamper: func(identityService *identityService, payload *domain.OIDCCallbackPayload) {
+				identityRepository := identityService.identityRepository.(*fakeIdentityRepository)
+				oidcState := identityRepository.states[payload.State]
+				oidcState.CodeVerifier = "not-the-verifier"
+				identityRepository.states[payload.State] = oidcState
+			},
+			wantErr: domain.ErrOIDCTokenInvalid,
+		},
+		{
+			name:    "nonce mismatch",
+			setup:   func(server *oidctest.Server) { server.Nonce = "replayed-nonce" },
+			wantErr: domain.ErrOIDCTokenInvalid,
+		},
+		{
+			name:    "wrong issuer",
+			setup:   func(server *oidctest.Server) { server.Issuer = "https://evil.example.com" },
+			wantErr: domain.ErrOIDCTokenInvalid,
+		},
+		{
+			name:    "wrong audience",
+			setup:   func(server *oidctest.Server) { server.Audience = "other-client" },
+			wantErr: domain.ErrOIDCTokenInvalid,
+		},
+		{
+			name:    "unverified email",
+			setup:   func(server *oidctest.Server) { server.EmailVerified = false },
+			wantErr: domain.ErrOIDCEmailNotVerified,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identityService, server := newTestIdentityService(t)
+			if tt.setup != nil {
+				tt.setup(server)
+			}
+
+			payload := signIn(t, identityService, server)
+			if tt.tamper != nil {
+				tt.tamper(identityService, &payload)
+			}
+
+			sessionResponse, err := identityService.Callback(context.Background(), payload)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Callback() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && sessionResponse.Token == "" {
+				t.Error("Callback() returned no session token")
+			}
+		})
+	}
+}
+
+func TestIdentityServiceCallbackRejectsReplayedState(t *testing.T) {
+	identityService, server := newTestIdentityService(t)
+	payload := signIn(t, identityService, server)
+
+	if _, err := identityService.Callback(context.Background(), payload); err != nil {
+		t.Fatalf("first Callback() error = %v", err)
+	}
+
+	if _, err := identityService.Callback(context.Background(), payload); !errors.Is(err, domain.ErrOIDCStateInvalid) {
+		t.Fatalf("replayed Callback() error = %v, want %v", err, domain.ErrOIDCStateInvalid)
+	}
+}
+
+func TestIdentityServiceCallbackLinksExistingIdentity(t *testing.T) {
+	identityService, server := newTestIdentityService(t)
+
+	if _, err := identityService.Callback(context.Background(), signIn(t, identityService, server)); err != nil {
+		t.Fatalf("first Callback() error = %v", err)
+	}
+
+	server.Email = "changed@example.com"
+	if _, err := identityService.Callback(context.Background(), signIn(t, identityService, server)); err != nil {
+		t.Fatalf("second Callback() error = %v", err)
+	}
+
+	userRepository := identityService.userRepository.(*fakeUserRepository)
+	if len(userRepository.users) != 1 {
+		t.Errorf("users = %d, want the identity to be reused", len(userRepository.users))
+	}
+}
+
+func TestIdentityServiceCallbackRequiresLinkForExistingAccount(t *testing.T) {
+	tests := []struct {
+		name           string
+		emailConfirmed bool
+	}{
+		{name: "unconfirmed account"},
+		{name: "confirmed account", emailConfirmed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identityService, server := newTestIdentityService(t)
+			userRepository := identityService.userRepository.(*fakeUserRepository)
+			userRepository.users = []domain.User{{ID: uuid.New(), Email: server.Email, EmailConfirmed: tt.emailConfirmed}}
+
+			if _, err := identityService.Callback(context.Background(), signIn(t, identityService, server)); !errors.Is(err, domain.ErrOIDCLinkRequired) {
+				t.Fatalf("Callback() error = %v, want %v", err, domain.ErrOIDCLinkRequired)
+			}
+
+			if userRepository.users[0].EmailConfirmed != tt.emailConfirmed {
+				t.Error("Callback() changed the email confirmation of the existing account")
+			}
+
+			if identities := identityService.identityRepository.(*fakeIdentityRepository).identities; len(identities) != 0 {
+				t.Errorf("identities = %d, want no identity linked", len(identities))
+			}
+		})
+	}
+}
+
+func TestIdentityServiceLink(t *testing.T) {
+	identityService, server := newTestIdentityService(t)
+	user := domain.User{ID: uuid.New(), Email: server.Email, EmailConfirmed: true}
+	otherUser := domain.User{ID: uuid.New(), Email: "other@example.com", EmailConfirmed: true}
+	identityService.userRepository.(*fakeUserRepository).users = []domain.User{user, otherUser}
+	ctx := context.WithValue(context.Background(), middleware.UserKey, &domain.Session{ID: uuid.New(), UserID: user.ID})
+	otherCtx := context.WithValue(context.Background(), middleware.UserKey, &domain.Session{ID: uuid.New(), UserID: otherUser.ID})
+
+	if _, err := identityService.Callback(context.Background(), startLink(t, ctx, identityService, server)); !errors.Is(err, domain.ErrOIDCStateInvalid) {
+		t.Fatalf("Callback() with a link state error = %v, want %v", err, domain.ErrOIDCStateInvalid)
+	}
+
+	if err := identityService.Link(otherCtx, startLink(t, ctx, identityService, server)); !errors.Is(err, domain.ErrOIDCStateInvalid) {
+		t.Fatalf("Link() with another user's state error = %v, want %v", err, domain.ErrOIDCStateInvalid)
+	}
+
+	if err := identityService.Link(ctx, signIn(t, identityService, server)); !errors.Is(err, domain.ErrOIDCStateInvalid) {
+		t.Fatalf("Link() with a sign in state error = %v, want %v", err, domain.ErrOIDCStateInvalid)
+	}
+
+	if err := identityService.Link(ctx, startLink(t, ctx, identityService, server)); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+
+	sessionResponse, err := identityService.Callback(context.Background(), signIn(t, identityService, server))
+	if err != nil {
+		t.Fatalf("Callback() after link error = %v", err)
+	}
+
+	if sessionResponse.Token != "token-"+user.ID.String() {
+		t.Errorf("Callback() signed in as %q, want the linked user", sessionResponse.Token)
+	}
+
+	if err := identityService.Link(otherCtx, startLink(t, otherCtx, identityService, server)); !errors.Is(err, domain.ErrIdentityAlreadyLinked) {
+		t.Errorf("Link() of an identity linked elsewhere error = %v, want %v", err, domain.ErrIdentityAlreadyLinked)
+	}
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/middleware"
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+const (
+	loginReportExp     = 7 * 24 * time.Hour
+	maxLoginUserAgent  = 255
+	newDeviceTimeStyle = "02 Jan 2006 15:04 MST"
+)
+
+type loginHistoryService struct {
+	i                      *do.Injector
+	loginHistoryRepository domain.LoginHistoryRepository
+	userRepository         domain.UserRepository
+	sessionService         domain.SessionService
+	emailService           domain.EmailService
+	auditService           domain.AuditService
+}
+
+func NewLoginHistoryService(i *do.Injector) (domain.LoginHistoryService, error) {
+	loginHistoryRepository, err := do.Invoke[domain.LoginHistoryRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	userRepository, err := do.Invoke[domain.UserRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionService, err := do.Invoke[domain.SessionService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	emailService, err := do.Invoke[domain.EmailService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := do.Invoke[domain.AuditService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loginHistoryService{
+		i:                      i,
+		loginHistoryRepository: loginHistoryRepository,
+		userRepository:         userRepository,
+		sessionService:         sessionService,
+		emailService:           emailService,
+		auditService:           auditService,
+	}, nil
+}
+
+func (l *loginHistoryService) Record(ctx context.Context, loginAttempt domain.LoginAttempt) error {
+	log := slog.With(
+		slog.String("service", "loginHistory"),
+		slog.String("func", "Record"),
+	)
+
+	loginHistory := domain.LoginHistory{
+		ID:        uuid.New(),
+		Email:     strings.ToLower(loginAttempt.Email),
+		Method:    loginAttempt.Method,
+		Result:    loginAttempt.Result,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if loginAttempt.UserID != uuid.Nil {
+		loginHistory.UserID = &loginAttempt.UserID
+	}
+
+	if loginAttempt.SessionID != uuid.Nil {
+		loginHistory.SessionID = &loginAttempt.SessionID
+	}
+
+	if clientInfo, ok := ctx.Value(middleware.ClientInfoKey).(*domain.ClientInfo); ok && clientInfo != nil {
+		loginHistory.IP = clientInfo.IP
+		loginHistory.UserAgent = clientInfo.UserAgent
+		loginHistory.Device = clientInfo.Device
+		loginHistory.Fingerprint = clientInfo.Fingerprint()
+
+		if len(loginHistory.UserAgent) > maxLoginUserAgent {
+			loginHistory.UserAgent = loginHistory.UserAgent[:maxLoginUserAgent]
+		}
+	}
+
+	newDevice, err := l.isNewDevice(ctx, loginHistory)
+	if err != nil {
+		log.Error("Failed to check login device", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := l.loginHistoryRepository.Create(ctx, loginHistory); err != nil {
+		log.Error("Failed to save login history", slog.String("error", err.Error()))
+		return err
+	}
+
+	if newDevice {
+		if err := l.notifyNewDevice(ctx, loginHistory); err != nil {
+			log.Error("Failed to notify new device", slog.String("error", err.Error()))
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *loginHistoryService) GetAll(ctx context.Context, loginHistoryQueryPayload domain.LoginHistoryQueryPayload) (*domain.LoginHistoriesResponse, error) {
+	log := slog.With(
+		slog.String("service", "loginHistory"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all login history process")
+
+	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || session == nil {
+		log.Warn("User not found in context")
+		return nil, domain.ErrUserNotFoundInContext
+	}
+
+	loginHistories, total, err := l.loginHistoryRepository.GetAll(ctx, session.UserID, loginHistoryQueryPayload.Offset(), loginHistoryQueryPayload.PageSize)
+	if err != nil {
+		log.Error("Failed to get login history", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	loginHistoriesResponse := &domain.LoginHistoriesResponse{
+		Items:    make([]*domain.LoginHistoryResponse, 0, len(loginHistories)),
+		Page:     loginHistoryQueryPayload.Page,
+		PageSize: loginHistoryQueryPayload.PageSize,
+		Total:    total,
+	}
+
+	for _, loginHistory := range loginHistories {
+		loginHistoriesResponse.Items = append(loginHistoriesResponse.Items, loginHistory.ToResponse())
+	}
+
+	log.Info("Login history retrieved successfully", slog.Int64("total", total))
+	return loginHistoriesResponse, nil
+}
+
+func (l *loginHistoryService) Report(ctx context.Context, loginReportPayload domain.LoginReportPayload) error {
+	log := slog.With(
+		slog.String("service", "loginHistory"),
+		slog.String("func", "Report"),
+	)
+
+	log.Info("Initializing login report process")
+
+	loginReport, err := l.loginHistoryRepository.ConsumeReport(ctx, secure.HashToken(loginReportPayload.Token))
+	if err != nil {
+		log.Error("Failed to consume login report", slog.String("error", err.Error()))
+		return err
+	}
+
+	if loginReport == nil {
+		log.Warn("Login report token not found or expired")
+		return domain.ErrLoginReportInvalid
+	}
+
+	user, err := l.userRepository.GetByID(ctx, loginReport.UserID)
+	if err != nil {
+		log.Error("Failed to get user by id", slog.String("error", err.Error()))
+		return err
+	}
+
+	if user == nil {
+		log.Warn("Reported user not found")
+		return domain.ErrLoginReportInvalid
+	}
+
+	if err := l.sessionService.RevokeUserSessions(ctx, user.ID); err != nil {
+		log.Error("Failed to revoke user sessions", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := l.userRepository.UpdatePasswordResetRequired(ctx, user.ID, true); err != nil {
+		log.Error("Failed to require password reset", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := l.auditService.Record(ctx, domain.AuditEntry{
+		ActorID:    user.ID,
+		Action:     domain.AuditActionUserReportLogin,
+		TargetType: domain.AuditTargetSession,
+		TargetID:   loginReport.SessionID.String(),
+		Before:     map[string]any{"passwordResetRequired": user.PasswordResetRequired},
+		After:      map[string]any{"passwordResetRequired": true},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	if err := l.emailService.SendPasswordResetCode(ctx, *user); err != nil {
+		log.Error("Failed to send password reset code", slog.String("error", err.Error()))
+	}
+
+	log.Info("Login reported successfully", slog.String("userID", user.ID.String()), slog.String("sessionID", loginReport.SessionID.String()))
+	return nil
+}
+
+func (l *loginHistoryService) isNewDevice(ctx context.Context, loginHistory domain.LoginHistory) (bool, error) {
+	if loginHistory.Result != domain.LoginResultSuccess || loginHistory.UserID == nil || loginHistory.Fingerprint == "" {
+		return false, nil
+	}
+
+	hasSignedIn, err := l.loginHistoryRepository.HasSuccess(ctx, *loginHistory.UserID, "")
+	if err != nil || !hasSignedIn {
+		return false, err
+	}
+
+	knownDevice, err := l.loginHistoryRepository.HasSuccess(ctx, *loginHistory.UserID, loginHistory.Fingerprint)
+	if err != nil {
+		return false, err
+	}
+
+	return !knownDevice, nil
+}
+
+func (l *loginHistoryService) notifyNewDevice(ctx context.Context, loginHistory domain.LoginHistory) error {
+	user, err := l.userRepository.GetByID(ctx, *loginHistory.UserID)
+	if err != nil || user == nil {
+		return err
+	}
+
+	token, err := secure.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	loginReport := domain.LoginReport{
+		UserID: user.ID,
+	}
+
+	if loginHistory.SessionID != nil {
+		loginReport.SessionID = *loginHistory.SessionID
+	}
+
+	if err := l.loginHistoryRepository.SaveReport(ctx, secure.HashToken(token), loginReport, loginReportExp); err != nil {
+		return err
+	}
+
+	reportURL := fmt.Sprintf("%s/logins/report?token=%s", strings.TrimSuffix(config.Env.URLFront, "/"), url.QueryEscape(token))
+	return l.emailService.SendNewDeviceNotice(ctx, *user, domain.NewDeviceEmailPayload{
+		Device: loginHistory.Device,
+		IP:     loginHistory.IP,
+		Time:   loginHistory.CreatedAt.Format(newDeviceTimeStyle),
+		URL:    reportURL,
+	})
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/samber/do"
+)
+
+const defaultMagicLinkExp = 15
+
+type magicLinkService struct {
+	i                   *do.Injector
+	magicLinkRepository domain.MagicLinkRepository
+	userRepository      domain.UserRepository
+	sessionService      domain.SessionService
+	emailService        domain.EmailService
+	attemptService      domain.AttemptService
+	auditService        domain.AuditService
+	loginHistoryService domain.LoginHistoryService
+}
+
+func NewMagicLinkService(i *do.Injector) (domain.MagicLinkService, error) {
+	magicLinkRepository, err := do.Invoke[domain.MagicLinkRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	userRepository, err := do.Invoke[domain.UserRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionService, err := do.Invoke[domain.SessionService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	emailService, err := do.Invoke[domain.EmailService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	attemptService, err := do.Invoke[domain.AttemptService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := do.Invoke[domain.AuditService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	loginHistoryService, err := do.Invoke[domain.LoginHistoryService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &magicLinkService{
+		i:                   i,
+		magicLinkRepository: magicLinkRepository,
+		userRepository:      userRepository,
+		sessionService:      sessionService,
+		emailService:        emailService,
+		attemptService:      attemptService,
+		auditService:        auditService,
+		loginHistoryService: loginHistoryService,
+	}, nil
+}
+
+func (m *magicLinkService) Request(ctx context.Context, magicLinkPayload domain.MagicLinkPayload) (*domain.MagicLinkResponse, error) {
+	log := slog.With(
+		slog.String("service", "magicLink"),
+		slog.String("func", "Request"),
+	)
+
+	log.Info("Initializing magic link request process")
+
+	if err := m.attemptService.Limit(ctx, domain.AttemptPurposeMagicLink, magicLinkPayload.Email); err != nil {
+		log.Warn("Magic link request rate limited", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	// The device token is returned for unknown emails too, so the response
+	// does not reveal whether an account exists.
+	deviceToken, err := secure.GenerateRandomToken(32)
+	if err != nil {
+		log.Error("Failed to generate device token", slog.String("error", err.Error()))
+		return nil, err
+	}
+	magicLinkResponse := &domain.MagicLinkResponse{DeviceToken: deviceToken}
+
+	user, err := m.userRepository.GetByEmail(ctx, magicLinkPayload.Email)
+	if err != nil {
+		log.Error("Failed to get user by email", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if user == nil {
+		log.Warn("Magic link requested for unknown email")
+		return magicLinkResponse, nil
+	}
+
+	if user.IsSuspended() {
+		log.Warn("Magic link requested for suspended user")
+		return magicLinkResponse, nil
+	}
+
+	token, err := secure.GenerateRandomToken(32)
+	if err != nil {
+		log.Error("Failed to generate magic link token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	magicLink := domain.MagicLink{
+		UserID:          user.ID,
+		Email:           user.Email,
+		DeviceTokenHash: secure.HashToken(deviceToken),
+	}
+
+	if err := m.magicLinkRepository.Save(ctx, secure.HashToken(token), magicLink, m.getExpiration()); err != nil {
+		log.Error("Failed to save magic link", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	magicLinkURL := fmt.Sprintf("%s/magic-link?token=%s", strings.TrimSuffix(config.Env.URLFront, "/"), url.QueryEscape(token))
+	if err := m.emailService.SendMagicLink(ctx, *user, magicLinkURL); err != nil {
+		log.Error("Failed to send magic link", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Magic link requested successfully", slog.String("userID", user.ID.String()))
+	return magicLinkResponse, nil
+}
+
+func (m *magicLinkService) SignIn(ctx context.Context, magicLinkSignInPayload domain.MagicLinkSignInPayload) (*domain.SessionResponse, error) {
+	log := slog.With(
+		slog.String("service", "magicLink"),
+		slog.String("func", "SignIn"),
+	)
+
+	log.Info("Initializing magic link sign in process")
+
+	magicLink, err := m.magicLinkRepository.Consume(ctx, secure.HashToken(magicLinkSignInPayload.Token))
+	if err != nil {
+		log.Error("Failed to consume magic link", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if magicLink == nil {
+		log.Warn("Magic link not found or expired")
+		return nil, domain.ErrMagicLinkInvalid
+	}
+
+	if subtle.ConstantTimeCompare([]byte(magicLink.DeviceTokenHash), []byte(secure.HashToken(magicLinkSignInPayload.DeviceToken))) != 1 {
+		log.Warn("Magic link used from another device", slog.String("userID", magicLink.UserID.String()))
+		if err := m.loginHistoryService.Record(ctx, domain.LoginAttempt{
+			UserID: magicLink.UserID,
+			Email:  magicLink.Email,
+			Method: domain.LoginMethodMagicLink,
+			Result: domain.LoginResultDeviceMismatch,
+		}); err != nil {
+			log.Error("Failed to record login history", slog.String("error", err.Error()))
+		}
+		return nil, domain.ErrMagicLinkDeviceMismatch
+	}
+
+	user, err := m.userRepository.GetByID(ctx, magicLink.UserID)
+	if err != nil {
+		log.Error("Failed to get user by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if user == nil || user.Email != magicLink.Email {
+		log.Warn("Magic link user not found or email changed")
+		return nil, domain.ErrMagicLinkInvalid
+	}
+
+	if err := m.attemptService.Reset(ctx, domain.AttemptPurposeMagicLink, user.Email); err != nil {
+		log.Error("Failed to reset magic link attempts", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if !user.EmailConfirmed {
+		if err := m.reclaimUnconfirmed(ctx, user); err != nil {
+			return nil, err
+		}
+
+		if err := m.auditService.Record(ctx, domain.AuditEntry{
+			ActorID:    user.ID,
+			Action:     domain.AuditActionUserConfirmEmail,
+			TargetType: domain.AuditTargetUser,
+			TargetID:   user.ID.String(),
+		}); err != nil {
+			log.Error("Failed to record audit event", slog.String("error", err.Error()))
+		}
+	}
+
+	if user.TwoFactorEnabled {
+		challengeToken, err := m.sessionService.CreateTwoFactorChallenge(ctx, user.ID)
+		if err != nil {
+			log.Error("Failed to create two factor challenge", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		log.Info("Two factor challenge issued")
+		if err := m.loginHistoryService.Record(ctx, domain.LoginAttempt{
+			UserID: user.ID,
+			Email:  user.Email,
+			Method: domain.LoginMethodMagicLink,
+			Result: domain.LoginResultTwoFactorRequired,
+		}); err != nil {
+			log.Error("Failed to record login history", slog.String("error", err.Error()))
+		}
+		return &domain.SessionResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+		}, domain.ErrTwoFactorRequired
+	}
+
+	sessionResponse, err := m.sessionService.Create(ctx, *user)
+	if err != nil {
+		log.Error("Failed to create session", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := m.loginHistoryService.Record(ctx, domain.LoginAttempt{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Method:    domain.LoginMethodMagicLink,
+		Result:    domain.LoginResultSuccess,
+		SessionID: sessionResponse.SessionID,
+	}); err != nil {
+		log.Error("Failed to record login history", slog.String("error", err.Error()))
+	}
+
+	log.Info("User signed in with magic link successfully", slog.String("userID", user.ID.String()))
+	return sessionResponse, nil
+}
+
+// reclaimUnconfirmed confirms the account for the owner of the email. Anyone
+// could have registered it before, so their password, second factor, linked
+// identities, api keys and sessions are dropped first.
+func (m *magicLinkService) reclaimUnconfirmed(ctx context.Context, user *domain.User) error {
+	log := slog.With(
+		slog.String("service", "magicLink"),
+		slog.String("func", "reclaimUnconfirmed"),
+	)
+
+	password, err := secure.GenerateRandomToken(32)
+	if err != nil {
+		log.Error("Failed to generate password", slog.String("error", err.Error()))
+		return err
+	}
+
+	passwordHash, err := secure.Hash(password)
+	if err != nil {
+		log.Error("Failed to hash password", slog.String("error", err.Error()))
+		return domain.ErrHashingPassword
+	}
+
+	if err := m.userRepository.ReclaimUnconfirmed(ctx, user.ID, string(passwordHash)); err != nil {
+		log.Error("Failed to confirm user email", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := m.sessionService.RevokeUserSessions(ctx, user.ID); err != nil {
+		log.Error("Failed to revoke user sessions", slog.String("error", err.Error()))
+		return err
+	}
+
+	user.EmailConfirmed = true
+	user.PasswordHash = string(passwordHash)
+	user.TwoFactorEnabled = false
+	user.TwoFactorSecret = ""
+	return nil
+}
+
+func (m *magicLinkService) getExpiration() time.Duration {
+	if config.Env.MagicLinkExp <= 0 {
+		return defaultMagicLinkExp * time.Minute
+	}
+	return time.Duration(config.Env.MagicLinkExp) * time.Minute
+}
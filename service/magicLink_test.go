@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/google/uuid"
+)
+
+type fakeMagicLinkRepository struct {
+	domain.MagicLinkRepository
+	magicLinks map[string]domain.MagicLink
+}
+
+func (f *fakeMagicLinkRepository) Save(_ context.Context, tokenHash string, magicLink domain.MagicLink, _ time.Duration) error {
+	f.magicLinks[tokenHash] = magicLink
+	return nil
+}
+
+func (f *fakeMagicLinkRepository) Consume(_ context.Context, tokenHash string) (*domain.MagicLink, error) {
+	magicLink, ok := f.magicLinks[tokenHash]
+	if !ok {
+		return nil, nil
+	}
+	delete(f.magicLinks, tokenHash)
+	return &magicLink, nil
+}
+
+type fakeEmailService struct {
+	domain.EmailService
+	magicLinkURL string
+}
+
+func (f *fakeEmailService) SendMagicLink(_ context.Context, _ domain.User, magicLinkURL string) error {
+	f.magicLinkURL = magicLinkURL
+	return nil
+}
+
+func (f *fakeUserRepository) ReclaimUnconfirmed(_ context.Context, id uuid.UUID, passwordHash string) error {
+	for index := range f.users {
+		if f.users[index].ID == id {
+			f.users[index].EmailConfirmed = true
+			f.users[index].PasswordHash = passwordHash
+			f.users[index].TwoFactorEnabled = false
+			f.users[index].TwoFactorSecret = ""
+		}
+	}
+	return nil
+}
+
+func (f *fakeSessionService) RevokeUserSessions(_ context.Context, userID uuid.UUID) error {
+	f.revoked = append(f.revoked, userID)
+	return nil
+}
+
+func newTestMagicLinkService(user domain.User) *magicLinkService {
+	return &magicLinkService{
+		magicLinkRepository: &fakeMagicLinkRepository{magicLinks: map[string]domain.MagicLink{}},
+		userRepository:      &fakeUserRepository{users: []domain.User{user}},
+		sessionService:      &fakeSessionService{},
+		emailService:        &fakeEmailService{},
+		attemptService:      &fakeAttemptService{maxFailures: 100, failures: map[string]int{}},
+		auditService:        &fakeAuditService{},
+		loginHistoryService: &fakeLoginHistoryService{},
+	}
+}
+
+// requestMagicLink returns the emailed token and the device token handed to
+// the requesting browser.
+func requestMagicLink(t *testing.T, magicLinkService *magicLinkService, email string) (string, string) {
+	t.Helper()
+
+	magicLinkResponse, err := magicLinkService.Request(context.Background(), domain.MagicLinkPayload{Email: email})
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+
+	magicLinkURL, err := url.Parse(magicLinkService.emailService.(*fakeEmailService).magicLinkURL)
+	if err != nil {
+		t.Fatalf("parse magic link url: %v", err)
+	}
+
+	return magicLinkURL.Query().Get("token"), magicLinkResponse.DeviceToken
+}
+
+func TestMagicLinkServiceSignInRequiresDeviceToken(t *testing.T) {
+	user := domain.User{ID: uuid.New(), Email: "user@example.com", EmailConfirmed: true}
+	magicLinkService := newTestMagicLinkService(user)
+
+	token, _ := requestMagicLink(t, magicLinkService, user.Email)
+	_, otherDeviceToken := requestMagicLink(t, magicLinkService, user.Email)
+	if _, err := magicLinkService.SignIn(context.Background(), domain.MagicLinkSignInPayload{Token: token, DeviceToken: otherDeviceToken}); !errors.Is(err, domain.ErrMagicLinkDeviceMismatch) {
+		t.Fatalf("SignIn() with another device token error = %v, want %v", err, domain.ErrMagicLinkDeviceMismatch)
+	}
+
+	token, deviceToken := requestMagicLink(t, magicLinkService, user.Email)
+	sessionResponse, err := magicLinkService.SignIn(context.Background(), domain.MagicLinkSignInPayload{Token: token, DeviceToken: deviceToken})
+	if err != nil {
+		t.Fatalf("SignIn() error = %v", err)
+	}
+
+	if sessionResponse.Token != "token-"+user.ID.String() {
+		t.Errorf("SignIn() signed in as %q, want %s", sessionResponse.Token, user.ID)
+	}
+}
+
+func TestMagicLinkServiceRequestUnknownEmail(t *testing.T) {
+	magicLinkService := newTestMagicLinkService(domain.User{ID: uuid.New(), Email: "user@example.com"})
+
+	magicLinkResponse, err := magicLinkService.Request(context.Background(), domain.MagicLinkPayload{Email: "unknown@example.com"})
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+
+	if magicLinkResponse.DeviceToken == "" {
+		t.Error("Request() for an unknown email returned no device token")
+	}
+}
+
+func TestMagicLinkServiceSignInReclaimsUnconfirmedAccount(t *testing.T) {
+	passwordHash, err := secure.Hash("squatter-password")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	user := domain.User{ID: uuid.New(), Email: "user@example.com", PasswordHash: string(passwordHash), TwoFactorEnabled: true, TwoFactorSecret: "secret"}
+	magicLinkService := newTestMagicLinkService(user)
+
+	token, deviceToken := requestMagicLink(t, magicLinkService, user.Email)
+	if _, err := magicLinkService.SignIn(context.Background(), domain.MagicLinkSignInPayload{Token: token, DeviceToken: deviceToken}); err != nil {
+		t.Fatalf("SignIn() error = %v", err)
+	}
+
+	reclaimed := magicLinkService.userRepository.(*fakeUserRepository).users[0]
+	if !reclaimed.EmailConfirmed || reclaimed.TwoFactorEnabled {
+		t.Errorf("reclaimed user = %+v, want confirmed without two factor", reclaimed)
+	}
+
+	if secure.CheckPassword(reclaimed.PasswordHash, "squatter-password") == nil {
+		t.Error("password set before the email was confirmed still works")
+	}
+
+	if revoked := magicLinkService.sessionService.(*fakeSessionService).revoked; len(revoked) != 1 || revoked[0] != user.ID {
+		t.Errorf("revoked sessions = %v, want the sessions of %s", revoked, user.ID)
+	}
+}
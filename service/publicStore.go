@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/middleware"
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/samber/do"
+)
+
+const defaultPublicCacheTTL = 60
+
+type publicStoreService struct {
+	i               *do.Injector
+	storeRepository domain.StoreRepository
+}
+
+func NewPublicStoreService(i *do.Injector) (domain.PublicStoreService, error) {
+	storeRepository, err := do.Invoke[domain.StoreRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &publicStoreService{
+		i:               i,
+		storeRepository: storeRepository,
+	}, nil
+}
+
+// GetByID serves the public payload of a store from Redis when possible. The
+// cache is dropped by the store and billboard repositories on every change,
+// so the TTL only bounds how long a missed invalidation can go unnoticed.
+func (p *publicStoreService) GetByID(ctx context.Context, storeID uuid.UUID) (*domain.PublicStoreCache, error) {
+	log := slog.With(
+		slog.String("service", "publicStore"),
+		slog.String("func", "GetByID"),
+	)
+
+	log.Info("Initializing get public store process")
+
+	publicStoreCache, err := p.storeRepository.GetPublicCache(ctx, storeID)
+	if err != nil {
+		log.Warn("Failed to get public store cache", slog.String("error", err.Error()))
+	}
+
+	if publicStoreCache != nil {
+		publicStoreCache.MaxAge = p.getExpiration()
+		log.Info("Public store served from cache", slog.String("storeID", storeID.String()))
+		return publicStoreCache, nil
+	}
+
+	store, err := p.storeRepository.GetPublicByID(ctx, storeID)
+	if err != nil {
+		log.Error("Failed to get public store", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if store == nil {
+		log.Warn("Public store not found", slog.String("storeID", storeID.String()))
+		return nil, domain.ErrStoreNotFound
+	}
+
+	body, err := jsoniter.Marshal(store.ToPublicDetailResponse())
+	if err != nil {
+		log.Error("Failed to marshal public store", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	publicStoreCache = &domain.PublicStoreCache{
+		ETag:   fmt.Sprintf(`"%s"`, secure.HashToken(string(body))[:32]),
+		Body:   body,
+		MaxAge: p.getExpiration(),
+	}
+
+	if err := p.storeRepository.SavePublicCache(ctx, storeID, *publicStoreCache, p.getExpiration()); err != nil {
+		log.Warn("Failed to save public store cache", slog.String("error", err.Error()))
+	}
+
+	log.Info("Public store retrieved successfully", slog.String("storeID", storeID.String()))
+	return publicStoreCache, nil
+}
+
+func (p *publicStoreService) GetStorefront(ctx context.Context) (*domain.PublicStoreCache, error) {
+	log := slog.With(
+		slog.String("service", "publicStore"),
+		slog.String("func", "GetStorefront"),
+	)
+
+	store, ok := ctx.Value(middleware.StoreKey).(*domain.Store)
+	if !ok || store == nil {
+		log.Warn("Store not found in context")
+		return nil, domain.ErrStoreNotFound
+	}
+
+	return p.GetByID(ctx, store.ID)
+}
+
+func (p *publicStoreService) getExpiration() time.Duration {
+	if config.Env.PublicCacheTTL <= 0 {
+		return defaultPublicCacheTTL * time.Second
+	}
+	return time.Duration(config.Env.PublicCacheTTL) * time.Second
+}
@@ -2,22 +2,34 @@ package service
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
+	"fmt"
 	"log/slog"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/GSVillas/e-commercer-api/config"
 	"github.com/GSVillas/e-commercer-api/domain"
 	"github.com/GSVillas/e-commercer-api/middleware"
+	"github.com/GSVillas/e-commercer-api/secure"
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/samber/do"
 )
 
+const (
+	lastSeenInterval      = time.Minute
+	defaultAccessTokenExp = 15
+)
+
 type sessionService struct {
 	i                 *do.Injector
 	sessionRepository domain.SessionRepository
 	userRepository    domain.UserRepository
+	auditService      domain.AuditService
 }
 
 func NewSessionService(i *do.Injector) (domain.SessionService, error) {
@@ -31,14 +43,20 @@ func NewSessionService(i *do.Injector) (domain.SessionService, error) {
 		return nil, err
 	}
 
+	auditService, err := do.Invoke[domain.AuditService](i)
+	if err != nil {
+		return nil, err
+	}
+
 	return &sessionService{
 		i:                 i,
 		sessionRepository: sessionRepository,
 		userRepository:    userRepository,
+		auditService:      auditService,
 	}, nil
 }
 
-func (t *sessionService) Create(ctx context.Context, user domain.User) (string, error) {
+func (t *sessionService) Create(ctx context.Context, user domain.User) (*domain.SessionResponse, error) {
 	log := slog.With(
 		slog.String("service", "token"),
 		slog.String("func", "Create"),
@@ -46,18 +64,176 @@ func (t *sessionService) Create(ctx context.Context, user domain.User) (string,
 
 	log.Info("Initializing token creation process")
 
-	token, err := t.createToken(user)
+	if user.IsSuspended() {
+		log.Warn("Suspended user tried to create a session", slog.String("userID", user.ID.String()))
+		return nil, domain.ErrUserSuspended
+	}
+
+	if user.PasswordResetRequired {
+		log.Warn("User must reset the password before creating a session", slog.String("userID", user.ID.String()))
+		return nil, domain.ErrPasswordResetRequired
+	}
+
+	now := time.Now().UTC()
+	session := domain.Session{
+		ID:         uuid.New(),
+		Name:       user.Name,
+		Username:   user.Username,
+		UserID:     user.ID,
+		Email:      user.Email,
+		AvatarURL:  user.AvatarURL,
+		Role:       user.Role,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+
+	if clientInfo, ok := ctx.Value(middleware.ClientInfoKey).(*domain.ClientInfo); ok && clientInfo != nil {
+		session.Device = clientInfo.Device
+		session.IP = clientInfo.IP
+		session.UserAgent = clientInfo.UserAgent
+	}
+
+	token, expiresAt, err := t.createToken(user, session.ID)
 	if err != nil {
 		log.Error("Failed to create token", slog.String("error", err.Error()))
-		return "", err
+		return nil, err
 	}
 
-	if err := t.sessionRepository.Create(ctx, user, token); err != nil {
+	session.Token = token
+
+	if err := t.sessionRepository.Create(ctx, session); err != nil {
 		log.Error("Failed to save token", slog.String("error", err.Error()))
-		return "", err
+		return nil, err
 	}
 
-	return token, nil
+	refreshToken, err := t.createRefreshToken(ctx, user.ID, session.ID, session.CreatedAt)
+	if err != nil {
+		log.Error("Failed to create refresh token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return &domain.SessionResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresAt:    &expiresAt,
+		SessionID:    session.ID,
+	}, nil
+}
+
+func (t *sessionService) Refresh(ctx context.Context, refreshToken string) (*domain.SessionResponse, error) {
+	log := slog.With(
+		slog.String("service", "token"),
+		slog.String("func", "Refresh"),
+	)
+
+	log.Info("Initializing token refresh process")
+
+	sessionID, secret, err := t.parseRefreshToken(refreshToken)
+	if err != nil {
+		log.Warn("Malformed refresh token")
+		return nil, domain.ErrRefreshTokenInvalid
+	}
+
+	storedRefreshToken, err := t.sessionRepository.GetRefreshToken(ctx, sessionID.String())
+	if err != nil {
+		log.Error("Failed to get refresh token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if storedRefreshToken == nil {
+		log.Warn("Refresh token family not found or expired")
+		return nil, domain.ErrRefreshTokenInvalid
+	}
+
+	if subtle.ConstantTimeCompare([]byte(storedRefreshToken.TokenHash), []byte(secure.HashToken(secret))) != 1 {
+		log.Warn("Refresh token reuse detected, revoking token family", slog.String("sessionID", sessionID.String()))
+		return nil, t.revokeRefreshTokenFamily(ctx, *storedRefreshToken)
+	}
+
+	// Families stored before CreatedAt existed start their lifetime now.
+	familyCreatedAt := storedRefreshToken.CreatedAt
+	if familyCreatedAt.IsZero() {
+		familyCreatedAt = time.Now().UTC()
+	}
+
+	newRefreshToken, rotatedRefreshToken, err := t.generateRefreshToken(storedRefreshToken.UserID, sessionID, familyCreatedAt)
+	if err != nil {
+		log.Error("Failed to generate refresh token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	rotated, err := t.sessionRepository.RotateRefreshToken(ctx, storedRefreshToken.TokenHash, *rotatedRefreshToken)
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionExpired) {
+			log.Warn("Refresh token family reached its lifetime")
+			return nil, domain.ErrRefreshTokenInvalid
+		}
+
+		log.Error("Failed to rotate refresh token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if !rotated {
+		log.Warn("Refresh token used concurrently, revoking token family", slog.String("sessionID", sessionID.String()))
+		return nil, t.revokeRefreshTokenFamily(ctx, *storedRefreshToken)
+	}
+
+	session, err := t.sessionRepository.GetUser(ctx, storedRefreshToken.UserID.String(), sessionID.String())
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Session not found for refresh token")
+			return nil, domain.ErrRefreshTokenInvalid
+		}
+		log.Error("Failed to retrieve session", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	user, err := t.userRepository.GetByID(ctx, storedRefreshToken.UserID)
+	if err != nil {
+		log.Error("Failed to get user by ID", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if user == nil {
+		log.Warn("User not found for refresh token")
+		return nil, domain.ErrRefreshTokenInvalid
+	}
+
+	if user.IsSuspended() {
+		log.Warn("Suspended user tried to refresh a session", slog.String("userID", user.ID.String()))
+		return nil, domain.ErrUserSuspended
+	}
+
+	token, expiresAt, err := t.createToken(*user, session.ID)
+	if err != nil {
+		log.Error("Failed to create token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	session.Token = token
+	session.Name = user.Name
+	session.Username = user.Username
+	session.Email = user.Email
+	session.AvatarURL = user.AvatarURL
+	session.Role = user.Role
+	session.LastSeenAt = time.Now().UTC()
+
+	if err := t.sessionRepository.Create(ctx, *session); err != nil {
+		if errors.Is(err, domain.ErrSessionExpired) {
+			log.Warn("Session reached its lifetime")
+			return nil, domain.ErrRefreshTokenInvalid
+		}
+		log.Error("Failed to save token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Token refreshed successfully")
+	return &domain.SessionResponse{
+		Token:        token,
+		RefreshToken: newRefreshToken,
+		ExpiresAt:    &expiresAt,
+		SessionID:    session.ID,
+	}, nil
 }
 
 func (t *sessionService) GetUser(ctx context.Context, token string) (*domain.Session, error) {
@@ -74,7 +250,18 @@ func (t *sessionService) GetUser(ctx context.Context, token string) (*domain.Ses
 		return nil, err
 	}
 
-	session, err := t.sessionRepository.GetUser(ctx, sessionToken.UserID.String())
+	suspended, err := t.sessionRepository.IsSuspended(ctx, sessionToken.UserID.String())
+	if err != nil {
+		log.Error("Failed to check user suspension", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if suspended {
+		log.Warn("Suspended user tried to use a session", slog.String("userID", sessionToken.UserID.String()))
+		return nil, domain.ErrUserSuspended
+	}
+
+	session, err := t.sessionRepository.GetUser(ctx, sessionToken.UserID.String(), sessionToken.ID.String())
 	if err != nil {
 		log.Error("Failed to retrieve user", slog.String("error", err.Error()))
 		return nil, err
@@ -90,9 +277,48 @@ func (t *sessionService) GetUser(ctx context.Context, token string) (*domain.Ses
 		return nil, domain.ErrTokenInvalid
 	}
 
+	if time.Since(session.LastSeenAt) > lastSeenInterval {
+		session.LastSeenAt = time.Now().UTC()
+		if err := t.sessionRepository.Update(ctx, *session); err != nil {
+			log.Warn("Failed to update session last seen", slog.String("error", err.Error()))
+		}
+	}
+
 	return session, nil
 }
 
+func (t *sessionService) GetAll(ctx context.Context) ([]*domain.SessionInfoResponse, error) {
+	log := slog.With(
+		slog.String("service", "token"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all sessions process")
+
+	userSession, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || userSession == nil {
+		return nil, domain.ErrUserNotFoundInContext
+	}
+
+	sessions, err := t.sessionRepository.GetAll(ctx, userSession.UserID.String())
+	if err != nil {
+		log.Error("Failed to retrieve sessions", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	sessionsResponse := make([]*domain.SessionInfoResponse, 0, len(sessions))
+	for _, session := range sessions {
+		sessionsResponse = append(sessionsResponse, session.ToInfoResponse(userSession.ID))
+	}
+
+	log.Info("Get all sessions process executed successfully")
+	return sessionsResponse, nil
+}
+
 func (t *sessionService) Update(ctx context.Context) error {
 	log := slog.With(
 		slog.String("service", "token"),
@@ -112,15 +338,281 @@ func (t *sessionService) Update(ctx context.Context) error {
 		return err
 	}
 
-	if err := t.sessionRepository.Update(ctx, *user, userSession.Token); err != nil {
-		log.Error("Failed to update token", slog.String("error", err.Error()))
+	sessions, err := t.sessionRepository.GetAll(ctx, user.ID.String())
+	if err != nil {
+		log.Error("Failed to retrieve sessions", slog.String("error", err.Error()))
+		return err
+	}
+
+	for _, session := range sessions {
+		session.Name = user.Name
+		session.Username = user.Username
+		session.Email = user.Email
+		session.AvatarURL = user.AvatarURL
+
+		if err := t.sessionRepository.Update(ctx, *session); err != nil {
+			if errors.Is(err, domain.ErrSessionNotFound) {
+				continue
+			}
+			log.Error("Failed to update token", slog.String("error", err.Error()))
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *sessionService) Delete(ctx context.Context, sessionID uuid.UUID) error {
+	log := slog.With(
+		slog.String("service", "token"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing session revoke process")
+
+	userSession, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || userSession == nil {
+		return domain.ErrUserNotFoundInContext
+	}
+
+	session, err := t.sessionRepository.GetUser(ctx, userSession.UserID.String(), sessionID.String())
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			log.Warn("Session not found", slog.String("sessionID", sessionID.String()))
+		}
+		return err
+	}
+
+	if err := t.sessionRepository.Delete(ctx, session.UserID.String(), session.ID.String()); err != nil {
+		log.Error("Failed to revoke session", slog.String("error", err.Error()))
 		return err
 	}
 
+	if err := t.auditService.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditActionSessionRevoke,
+		TargetType: domain.AuditTargetSession,
+		TargetID:   sessionID.String(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Session revoked successfully", slog.String("sessionID", sessionID.String()))
 	return nil
 }
 
-func (t *sessionService) SaveOTP(ctx context.Context, email string, otp string) error {
+func (t *sessionService) DeleteOthers(ctx context.Context) error {
+	log := slog.With(
+		slog.String("service", "token"),
+		slog.String("func", "DeleteOthers"),
+	)
+
+	log.Info("Initializing revoke other sessions process")
+
+	userSession, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || userSession == nil {
+		return domain.ErrUserNotFoundInContext
+	}
+
+	sessions, err := t.sessionRepository.GetAll(ctx, userSession.UserID.String())
+	if err != nil {
+		log.Error("Failed to retrieve sessions", slog.String("error", err.Error()))
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.ID == userSession.ID {
+			continue
+		}
+
+		if err := t.sessionRepository.Delete(ctx, session.UserID.String(), session.ID.String()); err != nil {
+			log.Error("Failed to revoke session", slog.String("error", err.Error()))
+			return err
+		}
+	}
+
+	if err := t.auditService.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditActionSessionRevokeOthers,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   userSession.UserID.String(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Other sessions revoked successfully")
+	return nil
+}
+
+func (t *sessionService) RevokeToken(ctx context.Context, token string) error {
+	log := slog.With(
+		slog.String("service", "token"),
+		slog.String("func", "RevokeToken"),
+	)
+
+	log.Info("Initializing token revoke process")
+
+	_, err := jwt.Parse(token, config.Env.KeyRing.Keyfunc)
+
+	var validationError *jwt.ValidationError
+	if err != nil && !(errors.As(err, &validationError) && validationError.Errors == jwt.ValidationErrorExpired) {
+		log.Warn("Token signature is invalid", slog.String("error", err.Error()))
+		return domain.ErrTokenInvalid
+	}
+
+	sessionToken, err := t.extractSessionFromToken(token)
+	if err != nil {
+		log.Warn("Failed to extract session from token", slog.String("error", err.Error()))
+		return domain.ErrTokenInvalid
+	}
+
+	if err := t.sessionRepository.Delete(ctx, sessionToken.UserID.String(), sessionToken.ID.String()); err != nil {
+		log.Error("Failed to revoke session", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := t.auditService.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditActionSessionRevoke,
+		TargetType: domain.AuditTargetSession,
+		TargetID:   sessionToken.ID.String(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Token revoked successfully", slog.String("userID", sessionToken.UserID.String()), slog.String("sessionID", sessionToken.ID.String()))
+	return nil
+}
+
+func (t *sessionService) RevokeUserSessions(ctx context.Context, userID uuid.UUID) error {
+	log := slog.With(
+		slog.String("service", "token"),
+		slog.String("func", "RevokeUserSessions"),
+	)
+
+	log.Info("Initializing revoke user sessions process")
+
+	if err := t.sessionRepository.DeleteAll(ctx, userID.String()); err != nil {
+		log.Error("Failed to revoke user sessions", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := t.auditService.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditActionSessionRevokeAll,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   userID.String(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("User sessions revoked successfully", slog.String("userID", userID.String()))
+	return nil
+}
+
+func (t *sessionService) SuspendUser(ctx context.Context, userID uuid.UUID) error {
+	log := slog.With(
+		slog.String("service", "token"),
+		slog.String("func", "SuspendUser"),
+	)
+
+	log.Info("Initializing suspend user sessions process")
+
+	if err := t.sessionRepository.SaveSuspension(ctx, userID.String()); err != nil {
+		log.Error("Failed to save user suspension", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := t.sessionRepository.DeleteAll(ctx, userID.String()); err != nil {
+		log.Error("Failed to revoke user sessions", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("User sessions suspended successfully", slog.String("userID", userID.String()))
+	return nil
+}
+
+func (t *sessionService) UnsuspendUser(ctx context.Context, userID uuid.UUID) error {
+	log := slog.With(
+		slog.String("service", "token"),
+		slog.String("func", "UnsuspendUser"),
+	)
+
+	log.Info("Initializing unsuspend user sessions process")
+
+	if err := t.sessionRepository.DeleteSuspension(ctx, userID.String()); err != nil {
+		log.Error("Failed to delete user suspension", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("User sessions unsuspended successfully", slog.String("userID", userID.String()))
+	return nil
+}
+
+func (t *sessionService) CreateTwoFactorChallenge(ctx context.Context, userID uuid.UUID) (string, error) {
+	log := slog.With(
+		slog.String("service", "token"),
+		slog.String("func", "CreateTwoFactorChallenge"),
+	)
+
+	log.Info("Initializing two factor challenge creation process")
+
+	challengeToken, err := secure.GenerateRandomToken(32)
+	if err != nil {
+		log.Error("Failed to generate challenge token", slog.String("error", err.Error()))
+		return "", err
+	}
+
+	if err := t.sessionRepository.SaveTwoFactorChallenge(ctx, secure.HashToken(challengeToken), userID.String()); err != nil {
+		log.Error("Failed to save two factor challenge", slog.String("error", err.Error()))
+		return "", err
+	}
+
+	log.Info("Two factor challenge created successfully")
+	return challengeToken, nil
+}
+
+func (t *sessionService) GetTwoFactorChallenge(ctx context.Context, challengeToken string) (uuid.UUID, error) {
+	log := slog.With(
+		slog.String("service", "token"),
+		slog.String("func", "GetTwoFactorChallenge"),
+	)
+
+	log.Info("Initializing two factor challenge retrieval process")
+
+	userIDStr, err := t.sessionRepository.GetTwoFactorChallenge(ctx, secure.HashToken(challengeToken))
+	if err != nil {
+		log.Error("Failed to retrieve two factor challenge", slog.String("error", err.Error()))
+		return uuid.Nil, err
+	}
+
+	if userIDStr == "" {
+		log.Warn("Two factor challenge not found or expired")
+		return uuid.Nil, domain.ErrTwoFactorChallengeInvalid
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		log.Error("Stored two factor challenge is malformed", slog.String("error", err.Error()))
+		return uuid.Nil, domain.ErrTwoFactorChallengeInvalid
+	}
+
+	return userID, nil
+}
+
+func (t *sessionService) DeleteTwoFactorChallenge(ctx context.Context, challengeToken string) error {
+	log := slog.With(
+		slog.String("service", "token"),
+		slog.String("func", "DeleteTwoFactorChallenge"),
+	)
+
+	log.Info("Initializing two factor challenge delete process")
+
+	if err := t.sessionRepository.DeleteTwoFactorChallenge(ctx, secure.HashToken(challengeToken)); err != nil {
+		log.Error("Failed to delete two factor challenge", slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func (t *sessionService) SaveOTP(ctx context.Context, purpose domain.OTPPurpose, email string, otp string) error {
 	log := slog.With(
 		slog.String("service", "token"),
 		slog.String("func", "SaveOTP"),
@@ -128,7 +620,7 @@ func (t *sessionService) SaveOTP(ctx context.Context, email string, otp string)
 
 	log.Info("Initializing OTP save process")
 
-	err := t.sessionRepository.SaveOTP(ctx, email, otp)
+	err := t.sessionRepository.SaveOTP(ctx, purpose, email, otp)
 	if err != nil {
 		log.Error("Failed to save OTP", slog.String("error", err.Error()))
 		return err
@@ -138,14 +630,14 @@ func (t *sessionService) SaveOTP(ctx context.Context, email string, otp string)
 	return nil
 }
 
-func (t *sessionService) GetOTP(ctx context.Context, email string) (string, error) {
+func (t *sessionService) GetOTP(ctx context.Context, purpose domain.OTPPurpose, email string) (string, error) {
 	log := slog.With(
 		slog.String("service", "token"),
 		slog.String("func", "getOTP"),
 	)
 
 	log.Info("Initializing OTP get process")
-	OTP, err := t.sessionRepository.GetOTP(ctx, email)
+	OTP, err := t.sessionRepository.GetOTP(ctx, purpose, email)
 	if err != nil {
 		if errors.Is(err, domain.ErrOTPNotFound) {
 			log.Warn("otp not found")
@@ -159,21 +651,101 @@ func (t *sessionService) GetOTP(ctx context.Context, email string) (string, erro
 	return OTP, nil
 }
 
-func (t *sessionService) createToken(user domain.User) (string, error) {
+func (t *sessionService) DeleteOTP(ctx context.Context, purpose domain.OTPPurpose, email string) error {
+	log := slog.With(
+		slog.String("service", "token"),
+		slog.String("func", "DeleteOTP"),
+	)
+
+	log.Info("Initializing OTP delete process")
+
+	if err := t.sessionRepository.DeleteOTP(ctx, purpose, email); err != nil {
+		log.Error("Failed to delete OTP", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("OTP deleted successfully")
+	return nil
+}
+
+func (t *sessionService) createToken(user domain.User, sessionID uuid.UUID) (string, time.Time, error) {
+	issuedAt := time.Now().UTC()
+	expiresAt := issuedAt.Add(t.getAccessTokenExp())
 
 	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
 		"id":        user.ID,
 		"name":      user.Name,
 		"email":     user.Email,
 		"avatarURL": user.AvatarURL,
+		"sid":       sessionID.String(),
+		"jti":       uuid.New().String(),
+		"iat":       issuedAt.Unix(),
+		"exp":       expiresAt.Unix(),
 	})
 
-	tokenString, err := token.SignedString(config.Env.PrivateKey)
+	kid, signingKey := config.Env.KeyRing.SigningKey()
+	token.Header["kid"] = kid
+
+	tokenString, err := token.SignedString(signingKey)
 	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expiresAt, nil
+}
+
+func (t *sessionService) createRefreshToken(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID, createdAt time.Time) (string, error) {
+	token, refreshToken, err := t.generateRefreshToken(userID, sessionID, createdAt)
+	if err != nil {
+		return "", err
+	}
+
+	if err := t.sessionRepository.SaveRefreshToken(ctx, *refreshToken); err != nil {
 		return "", err
 	}
 
-	return tokenString, nil
+	return token, nil
+}
+
+func (t *sessionService) generateRefreshToken(userID uuid.UUID, sessionID uuid.UUID, createdAt time.Time) (string, *domain.RefreshToken, error) {
+	secret, err := secure.GenerateRandomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	refreshToken := &domain.RefreshToken{
+		FamilyID:  sessionID,
+		UserID:    userID,
+		TokenHash: secure.HashToken(secret),
+		CreatedAt: createdAt,
+	}
+
+	return fmt.Sprintf("%s.%s", sessionID.String(), secret), refreshToken, nil
+}
+
+// revokeRefreshTokenFamily ends the session a reused refresh token belongs
+// to, so neither the legitimate client nor an attacker can keep using it.
+func (t *sessionService) revokeRefreshTokenFamily(ctx context.Context, refreshToken domain.RefreshToken) error {
+	if err := t.sessionRepository.Delete(ctx, refreshToken.UserID.String(), refreshToken.FamilyID.String()); err != nil {
+		slog.Error("Failed to revoke refresh token family", slog.String("error", err.Error()))
+		return err
+	}
+
+	return domain.ErrRefreshTokenReused
+}
+
+func (t *sessionService) parseRefreshToken(refreshToken string) (uuid.UUID, string, error) {
+	parts := strings.SplitN(refreshToken, ".", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return uuid.Nil, "", domain.ErrRefreshTokenInvalid
+	}
+
+	sessionID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return uuid.Nil, "", domain.ErrRefreshTokenInvalid
+	}
+
+	return sessionID, parts[1], nil
 }
 
 func (t *sessionService) extractSessionFromToken(tokenString string) (*domain.Session, error) {
@@ -202,12 +774,30 @@ func (t *sessionService) extractSessionFromToken(tokenString string) (*domain.Se
 		return nil, domain.ErrTokenInvalid
 	}
 
+	sessionIDStr, ok := claims["sid"].(string)
+	if !ok {
+		return nil, domain.ErrTokenInvalid
+	}
+
+	sessionID, err := uuid.Parse(sessionIDStr)
+	if err != nil {
+		return nil, domain.ErrTokenInvalid
+	}
+
 	var session domain.Session
 	err = jsoniter.Unmarshal(jsonStr, &session)
 	if err != nil {
 		return nil, err
 	}
 	session.UserID = userID
+	session.ID = sessionID
 
 	return &session, nil
 }
+
+func (t *sessionService) getAccessTokenExp() time.Duration {
+	if config.Env.AccessTokenExp <= 0 {
+		return defaultAccessTokenExp * time.Minute
+	}
+	return time.Duration(config.Env.AccessTokenExp) * time.Minute
+}
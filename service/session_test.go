@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/google/uuid"
+)
+
+// fakeSessionRepository keeps refresh tokens in memory and rotates them with
+// the same compare-and-swap contract as the Redis script.
+type fakeSessionRepository struct {
+	domain.SessionRepository
+	mutex         sync.Mutex
+	refreshTokens map[uuid.UUID]domain.RefreshToken
+	deleted       []uuid.UUID
+}
+
+func (f *fakeSessionRepository) GetRefreshToken(_ context.Context, familyID string) (*domain.RefreshToken, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	refreshToken, ok := f.refreshTokens[uuid.MustParse(familyID)]
+	if !ok {
+		return nil, nil
+	}
+	return &refreshToken, nil
+}
+
+func (f *fakeSessionRepository) SaveRefreshToken(_ context.Context, refreshToken domain.RefreshToken) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.refreshTokens[refreshToken.FamilyID] = refreshToken
+	return nil
+}
+
+func (f *fakeSessionRepository) RotateRefreshToken(_ context.Context, currentTokenHash string, refreshToken domain.RefreshToken) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	current, ok := f.refreshTokens[refreshToken.FamilyID]
+	if !ok || current.TokenHash != currentTokenHash {
+		return false, nil
+	}
+	f.refreshTokens[refreshToken.FamilyID] = refreshToken
+	return true, nil
+}
+
+func (f *fakeSessionRepository) GetUser(_ context.Context, userID string, sessionID string) (*domain.Session, error) {
+	return &domain.Session{ID: uuid.MustParse(sessionID), UserID: uuid.MustParse(userID)}, nil
+}
+
+func (f *fakeSessionRepository) Create(context.Context, domain.Session) error {
+	return nil
+}
+
+func (f *fakeSessionRepository) Delete(_ context.Context, _ string, sessionID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	familyID := uuid.MustParse(sessionID)
+	delete(f.refreshTokens, familyID)
+	f.deleted = append(f.deleted, familyID)
+	return nil
+}
+
+func newTestSessionService(t *testing.T, user domain.User) *sessionService {
+	t.Helper()
+
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate signing key: %v", err)
+	}
+
+	keyRing, err := secure.NewKeyRing("test", signingKey, nil)
+	if err != nil {
+		t.Fatalf("create key ring: %v", err)
+	}
+
+	env := config.Env
+	t.Cleanup(func() { config.Env = env })
+	config.Env.KeyRing = keyRing
+
+	return &sessionService{
+		sessionRepository: &fakeSessionRepository{refreshTokens: map[uuid.UUID]domain.RefreshToken{}},
+		userRepository:    &fakeUserRepository{users: []domain.User{user}},
+		auditService:      &fakeAuditService{},
+	}
+}
+
+func TestSessionServiceRefreshRotates(t *testing.T) {
+	user := domain.User{ID: uuid.New(), Email: "user@example.com"}
+	sessionService := newTestSessionService(t, user)
+	ctx := context.Background()
+
+	refreshToken, err := sessionService.createRefreshToken(ctx, user.ID, uuid.New(), time.Now().UTC())
+	if err != nil {
+		t.Fatalf("createRefreshToken() error = %v", err)
+	}
+
+	sessionResponse, err := sessionService.Refresh(ctx, refreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	if sessionResponse.Token == "" || sessionResponse.RefreshToken == "" || sessionResponse.RefreshToken == refreshToken {
+		t.Fatalf("Refresh() did not rotate the tokens: %+v", sessionResponse)
+	}
+
+	if sessionResponse.ExpiresAt == nil || !sessionResponse.ExpiresAt.After(time.Now()) {
+		t.Errorf("ExpiresAt = %v, want a future expiry", sessionResponse.ExpiresAt)
+	}
+
+	if _, err := sessionService.Refresh(ctx, refreshToken); !errors.Is(err, domain.ErrRefreshTokenReused) {
+		t.Fatalf("Refresh() with rotated token error = %v, want %v", err, domain.ErrRefreshTokenReused)
+	}
+
+	if _, err := sessionService.Refresh(ctx, sessionResponse.RefreshToken); !errors.Is(err, domain.ErrRefreshTokenInvalid) {
+		t.Errorf("Refresh() after family revoke error = %v, want %v", err, domain.ErrRefreshTokenInvalid)
+	}
+}
+
+func TestSessionServiceRefreshConcurrentReuse(t *testing.T) {
+	user := domain.User{ID: uuid.New(), Email: "user@example.com"}
+	sessionService := newTestSessionService(t, user)
+	ctx := context.Background()
+
+	refreshToken, err := sessionService.createRefreshToken(ctx, user.ID, uuid.New(), time.Now().UTC())
+	if err != nil {
+		t.Fatalf("createRefreshToken() error = %v", err)
+	}
+
+	const attempts = 8
+	errs := make(chan error, attempts)
+
+	var wg sync.WaitGroup
+	for range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := sessionService.Refresh(ctx, refreshToken)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, domain.ErrRefreshTokenReused), errors.Is(err, domain.ErrRefreshTokenInvalid):
+		default:
+			t.Errorf("Refresh() unexpected error = %v", err)
+		}
+	}
+
+	if succeeded != 1 {
+		t.Fatalf("%d concurrent refreshes succeeded, want 1", succeeded)
+	}
+
+	if len(sessionService.sessionRepository.(*fakeSessionRepository).deleted) == 0 {
+		t.Error("reused refresh token did not revoke the token family")
+	}
+}
+
+func TestSessionServiceRefreshKeepsFamilyLifetime(t *testing.T) {
+	user := domain.User{ID: uuid.New(), Email: "user@example.com"}
+	sessionService := newTestSessionService(t, user)
+	ctx := context.Background()
+
+	sessionID := uuid.New()
+	createdAt := time.Now().UTC().Add(-time.Hour)
+	refreshToken, err := sessionService.createRefreshToken(ctx, user.ID, sessionID, createdAt)
+	if err != nil {
+		t.Fatalf("createRefreshToken() error = %v", err)
+	}
+
+	sessionResponse, err := sessionService.Refresh(ctx, refreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	if sessionResponse.SessionID != sessionID {
+		t.Errorf("SessionID = %s, want %s", sessionResponse.SessionID, sessionID)
+	}
+
+	rotatedRefreshToken := sessionService.sessionRepository.(*fakeSessionRepository).refreshTokens[sessionID]
+	if !rotatedRefreshToken.CreatedAt.Equal(createdAt) {
+		t.Errorf("rotated family CreatedAt = %v, want %v", rotatedRefreshToken.CreatedAt, createdAt)
+	}
+}
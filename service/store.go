@@ -2,18 +2,26 @@ package service
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/GSVillas/e-commercer-api/client"
+	"github.com/GSVillas/e-commercer-api/config"
 	"github.com/GSVillas/e-commercer-api/domain"
 	"github.com/GSVillas/e-commercer-api/middleware"
 	"github.com/google/uuid"
 	"github.com/samber/do"
 )
 
+const defaultStorePurgeRetentionDays = 30
+
 type storeService struct {
-	i               *do.Injector
-	storeRepository domain.StoreRepository
+	i                     *do.Injector
+	storeRepository       domain.StoreRepository
+	storeMemberRepository domain.StoreMemberRepository
+	storeAuthorizer       domain.StoreAuthorizer
+	cloudFlareService     client.CloudFlareService
+	auditService          domain.AuditService
 }
 
 func NewStoreService(i *do.Injector) (domain.StoreService, error) {
@@ -22,9 +30,33 @@ func NewStoreService(i *do.Injector) (domain.StoreService, error) {
 		return nil, err
 	}
 
+	storeMemberRepository, err := do.Invoke[domain.StoreMemberRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	storeAuthorizer, err := do.Invoke[domain.StoreAuthorizer](i)
+	if err != nil {
+		return nil, err
+	}
+
+	cloudFlareService, err := do.Invoke[client.CloudFlareService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := do.Invoke[domain.AuditService](i)
+	if err != nil {
+		return nil, err
+	}
+
 	return &storeService{
-		i:               i,
-		storeRepository: storeRepository,
+		i:                     i,
+		storeRepository:       storeRepository,
+		storeMemberRepository: storeMemberRepository,
+		storeAuthorizer:       storeAuthorizer,
+		cloudFlareService:     cloudFlareService,
+		auditService:          auditService,
 	}, nil
 }
 
@@ -43,13 +75,46 @@ func (s *storeService) Create(ctx context.Context, storePayload domain.StorePayl
 
 	store := storePayload.ToStore(session.UserID)
 
+	if store.Slug != "" {
+		exists, err := s.storeRepository.ExistsBySlug(ctx, store.Slug)
+		if err != nil {
+			log.Error("Failed to check if store slug exists", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		if exists {
+			log.Warn("Store slug already in use")
+			return nil, domain.ErrStoreSlugAlreadyInUse
+		}
+	} else {
+		slug, err := generateStoreSlug(ctx, s.storeRepository, store.Name)
+		if err != nil {
+			log.Error("Failed to generate store slug", slog.String("error", err.Error()))
+			return nil, err
+		}
+		store.Slug = slug
+	}
+
 	if err := s.storeRepository.Create(ctx, *store); err != nil {
 		log.Error("Failed to create store", slog.String("error", err.Error()))
 		return nil, err
 	}
 
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    store.ID,
+		Action:     domain.AuditActionStoreCreate,
+		TargetType: domain.AuditTargetStore,
+		TargetID:   store.ID.String(),
+		After:      map[string]any{"name": store.Name, "slug": store.Slug},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	storeResponse := store.ToResponse()
+	storeResponse.Role = domain.StoreRoleOwner
+
 	log.Info("store creation process executed sucessfully")
-	return store.ToResponse(), nil
+	return storeResponse, nil
 }
 
 func (s *storeService) GetAll(ctx context.Context) ([]*domain.StoreResponse, error) {
@@ -66,6 +131,21 @@ func (s *storeService) GetAll(ctx context.Context) ([]*domain.StoreResponse, err
 		return nil, domain.ErrUserNotFoundInContext
 	}
 
+	if session.IsAPIKey() {
+		store, err := s.storeRepository.GetByID(ctx, session.StoreID)
+		if err != nil {
+			log.Error("Failed to get store by id", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		if store == nil {
+			log.Warn("store bound to the api key not found")
+			return nil, domain.ErrStoresNotFound
+		}
+
+		return []*domain.StoreResponse{store.ToResponse()}, nil
+	}
+
 	stores, err := s.storeRepository.GetAll(ctx, session.UserID)
 	if err != nil {
 		log.Error("Failed to retrieve stores", slog.String("error", err.Error()))
@@ -79,15 +159,55 @@ func (s *storeService) GetAll(ctx context.Context) ([]*domain.StoreResponse, err
 
 	log.Info("Successfully retrieved stores", slog.Int("storeCount", len(stores)))
 
+	storeMembers, err := s.storeMemberRepository.GetAllByUser(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to retrieve store memberships", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	roles := make(map[uuid.UUID]domain.StoreRole, len(storeMembers))
+	for _, storeMember := range storeMembers {
+		roles[storeMember.StoreID] = storeMember.Role
+	}
+
 	var storesResponse []*domain.StoreResponse
 	for _, store := range stores {
-		storesResponse = append(storesResponse, store.ToResponse())
+		storeResponse := store.ToResponse()
+		storeResponse.Role = roles[store.ID]
+		if store.UserID == session.UserID {
+			storeResponse.Role = domain.StoreRoleOwner
+		}
+		storesResponse = append(storesResponse, storeResponse)
 	}
 
 	log.Info("Store retrieval process executed successfully")
 	return storesResponse, nil
 }
 
+func (s *storeService) GetByID(ctx context.Context, storeID uuid.UUID) (*domain.StoreDetailResponse, error) {
+	log := slog.With(
+		slog.String("service", "store"),
+		slog.String("func", "GetByID"),
+	)
+
+	log.Info("Initializing get store by id process")
+
+	storeAccess, err := s.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionStoreRead)
+	if err != nil {
+		log.Warn("Store read not authorized", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	storeOverview, err := s.storeRepository.GetOverview(ctx, storeID)
+	if err != nil {
+		log.Error("Failed to get store overview", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Store retrieved successfully", slog.String("storeID", storeID.String()))
+	return storeAccess.Store.ToDetailResponse(storeAccess.Role, *storeOverview), nil
+}
+
 func (s *storeService) UpdateName(ctx context.Context, storeID uuid.UUID, updateStoreNamePayload domain.StoreNameUpdatePayload) error {
 	log := slog.With(
 		slog.String("service", "store"),
@@ -96,36 +216,187 @@ func (s *storeService) UpdateName(ctx context.Context, storeID uuid.UUID, update
 
 	log.Info("Initializing store name updated process")
 
-	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
-	if !ok || session == nil {
-		return domain.ErrUserNotFoundInContext
+	storeAccess, err := s.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionStoreUpdate)
+	if err != nil {
+		log.Warn("Store name update not authorized", slog.String("error", err.Error()))
+		return err
+	}
+
+	store := storeAccess.Store
+
+	if err := s.storeRepository.UpdateName(ctx, updateStoreNamePayload.Name, storeID); err != nil {
+		log.Error("Failed to update store name", slog.String("error", err.Error()))
+		return err
 	}
 
-	store, err := s.storeRepository.GetByID(ctx, storeID)
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    store.ID,
+		Action:     domain.AuditActionStoreUpdateName,
+		TargetType: domain.AuditTargetStore,
+		TargetID:   store.ID.String(),
+		Before:     map[string]any{"name": store.Name},
+		After:      map[string]any{"name": updateStoreNamePayload.Name},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Store name updated successfully", slog.String("storeID", store.ID.String()), slog.String("newName", store.Name))
+	return nil
+}
+
+func (s *storeService) UpdateSlug(ctx context.Context, storeID uuid.UUID, storeSlugUpdatePayload domain.StoreSlugUpdatePayload) error {
+	log := slog.With(
+		slog.String("service", "store"),
+		slog.String("func", "UpdateSlug"),
+	)
+
+	log.Info("Initializing store slug update process")
+
+	storeAccess, err := s.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionStoreUpdate)
 	if err != nil {
-		log.Error("Failed to get store by id", slog.String("error", err.Error()))
+		log.Warn("Store slug update not authorized", slog.String("error", err.Error()))
 		return err
 	}
 
-	if store == nil {
-		log.Warn("store not found with this id")
-		return domain.ErrStoreNotFound
+	store := storeAccess.Store
+	slug := storeSlugUpdatePayload.Slug
+
+	if store.Slug == slug {
+		log.Warn("New store slug is the same as the old one")
+		return domain.ErrStoreSlugIsSame
 	}
 
-	if store.UserID != session.UserID {
-		log.Error("Unauthorized attempt to update store name", slog.String("storeID", store.ID.String()), slog.String("userID", session.UserID.String()))
-		return fmt.Errorf("%w: user %s is not authorized to update store %s", domain.ErrUnauthorizedAction, session.UserID.String(), store.ID.String())
+	storeSlugRedirect, err := s.storeRepository.GetSlugRedirect(ctx, slug)
+	if err != nil {
+		log.Error("Failed to get store slug redirect", slog.String("error", err.Error()))
+		return err
 	}
 
-	if err := s.storeRepository.UpdateName(ctx, updateStoreNamePayload.Name, storeID); err != nil {
-		log.Error("Failed to update store name", slog.String("error", err.Error()))
+	if storeSlugRedirect == nil || storeSlugRedirect.StoreID != storeID {
+		exists, err := s.storeRepository.ExistsBySlug(ctx, slug)
+		if err != nil {
+			log.Error("Failed to check if store slug exists", slog.String("error", err.Error()))
+			return err
+		}
+
+		if exists {
+			log.Warn("Store slug already in use")
+			return domain.ErrStoreSlugAlreadyInUse
+		}
+	}
+
+	if err := s.storeRepository.UpdateSlug(ctx, store.Slug, slug, storeID); err != nil {
+		log.Error("Failed to update store slug", slog.String("error", err.Error()))
 		return err
 	}
 
-	log.Info("Store name updated successfully", slog.String("storeID", store.ID.String()), slog.String("newName", store.Name))
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    store.ID,
+		Action:     domain.AuditActionStoreUpdateSlug,
+		TargetType: domain.AuditTargetStore,
+		TargetID:   store.ID.String(),
+		Before:     map[string]any{"slug": store.Slug},
+		After:      map[string]any{"slug": slug},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Store slug updated successfully", slog.String("storeID", store.ID.String()), slog.String("slug", slug))
+	return nil
+}
+
+func (s *storeService) UpdateVisibility(ctx context.Context, storeID uuid.UUID, storeVisibilityUpdatePayload domain.StoreVisibilityUpdatePayload) error {
+	log := slog.With(
+		slog.String("service", "store"),
+		slog.String("func", "UpdateVisibility"),
+	)
+
+	log.Info("Initializing store visibility update process")
+
+	storeAccess, err := s.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionStoreUpdate)
+	if err != nil {
+		log.Warn("Store visibility update not authorized", slog.String("error", err.Error()))
+		return err
+	}
+
+	store := storeAccess.Store
+	isPublic := *storeVisibilityUpdatePayload.IsPublic
+
+	if err := s.storeRepository.UpdateVisibility(ctx, isPublic, storeID); err != nil {
+		log.Error("Failed to update store visibility", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    store.ID,
+		Action:     domain.AuditActionStoreUpdateVisibility,
+		TargetType: domain.AuditTargetStore,
+		TargetID:   store.ID.String(),
+		Before:     map[string]any{"isPublic": store.IsPublic},
+		After:      map[string]any{"isPublic": isPublic},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Store visibility updated successfully", slog.String("storeID", store.ID.String()), slog.Bool("isPublic", isPublic))
 	return nil
 }
 
+func (s *storeService) GetSettings(ctx context.Context, storeID uuid.UUID) (*domain.StoreSettingsResponse, error) {
+	log := slog.With(
+		slog.String("service", "store"),
+		slog.String("func", "GetSettings"),
+	)
+
+	log.Info("Initializing get store settings process")
+
+	storeAccess, err := s.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionStoreRead)
+	if err != nil {
+		log.Warn("Store settings read not authorized", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Store settings retrieved successfully", slog.String("storeID", storeID.String()))
+	return storeAccess.Store.Settings.ToResponse(), nil
+}
+
+func (s *storeService) UpdateSettings(ctx context.Context, storeID uuid.UUID, storeSettingsPayload domain.StoreSettingsPayload) (*domain.StoreSettingsResponse, error) {
+	log := slog.With(
+		slog.String("service", "store"),
+		slog.String("func", "UpdateSettings"),
+	)
+
+	log.Info("Initializing store settings update process")
+
+	storeAccess, err := s.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionStoreUpdate)
+	if err != nil {
+		log.Warn("Store settings update not authorized", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	store := storeAccess.Store
+	storeSettings := storeSettingsPayload.Apply(store.Settings)
+
+	if err := s.storeRepository.UpdateSettings(ctx, storeSettings, storeID); err != nil {
+		log.Error("Failed to update store settings", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    store.ID,
+		Action:     domain.AuditActionStoreUpdateSettings,
+		TargetType: domain.AuditTargetStore,
+		TargetID:   store.ID.String(),
+		Before:     store.Settings.ToResponse(),
+		After:      storeSettings.ToResponse(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Store settings updated successfully", slog.String("storeID", store.ID.String()))
+	return storeSettings.ToResponse(), nil
+}
+
 func (s *storeService) Delete(ctx context.Context, storeID uuid.UUID) error {
 	log := slog.With(
 		slog.String("service", "store"),
@@ -134,32 +405,216 @@ func (s *storeService) Delete(ctx context.Context, storeID uuid.UUID) error {
 
 	log.Info("Initializing delete store process")
 
+	storeAccess, err := s.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionStoreDelete)
+	if err != nil {
+		log.Warn("Store delete not authorized", slog.String("error", err.Error()))
+		return err
+	}
+
+	store := storeAccess.Store
+
+	if err := s.storeRepository.Delete(ctx, storeID); err != nil {
+		log.Error("Failed to delete store", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    store.ID,
+		Action:     domain.AuditActionStoreDelete,
+		TargetType: domain.AuditTargetStore,
+		TargetID:   store.ID.String(),
+		Before:     map[string]any{"name": store.Name},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Store deleted successfully", slog.String("storeID", store.ID.String()))
+	return nil
+}
+
+func (s *storeService) GetTrash(ctx context.Context) ([]*domain.TrashedStoreResponse, error) {
+	log := slog.With(
+		slog.String("service", "store"),
+		slog.String("func", "GetTrash"),
+	)
+
+	log.Info("Initializing get store trash process")
+
 	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
 	if !ok || session == nil {
-		return domain.ErrUserNotFoundInContext
+		log.Error("User not found in context")
+		return nil, domain.ErrUserNotFoundInContext
 	}
 
-	store, err := s.storeRepository.GetByID(ctx, storeID)
+	stores, err := s.storeRepository.GetDeleted(ctx, session.UserID)
 	if err != nil {
-		log.Error("Failed to get store by id", slog.String("error", err.Error()))
-		return err
+		log.Error("Failed to get deleted stores", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	trashedStoresResponse := make([]*domain.TrashedStoreResponse, 0, len(stores))
+	for _, store := range stores {
+		trashedStoresResponse = append(trashedStoresResponse, store.ToTrashResponse(store.DeletedAt.Time.Add(s.getPurgeRetention())))
+	}
+
+	log.Info("Store trash retrieved successfully", slog.Int("storeCount", len(trashedStoresResponse)))
+	return trashedStoresResponse, nil
+}
+
+// Restore brings a trashed store back together with the billboards removed
+// by the same delete. Only the owner can restore, as members lose access to
+// a store as soon as it is deleted.
+func (s *storeService) Restore(ctx context.Context, storeID uuid.UUID) (*domain.StoreResponse, error) {
+	log := slog.With(
+		slog.String("service", "store"),
+		slog.String("func", "Restore"),
+	)
+
+	log.Info("Initializing store restore process")
+
+	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || session == nil {
+		log.Error("User not found in context")
+		return nil, domain.ErrUserNotFoundInContext
+	}
+
+	if session.IsAPIKey() {
+		log.Warn("API key tried to restore a store")
+		return nil, domain.ErrAPIKeyNotAllowed
+	}
+
+	store, err := s.storeRepository.GetDeletedByID(ctx, storeID)
+	if err != nil {
+		log.Error("Failed to get deleted store", slog.String("error", err.Error()))
+		return nil, err
 	}
 
 	if store == nil {
-		log.Warn("store not found with this id")
-		return domain.ErrStoreNotFound
+		log.Warn("Deleted store not found", slog.String("storeID", storeID.String()))
+		return nil, domain.ErrStoreNotFound
 	}
 
 	if store.UserID != session.UserID {
-		log.Error("Unauthorized attempt to delete store", slog.String("storeID", store.ID.String()), slog.String("userID", session.UserID.String()))
-		return fmt.Errorf("%w: user %s is not authorized to delete store %s", domain.ErrUnauthorizedAction, session.UserID.String(), store.ID.String())
+		log.Warn("User is not the owner of the store", slog.String("storeID", storeID.String()))
+		return nil, domain.ErrStoreNotFound
 	}
 
-	if err := s.storeRepository.Delete(ctx, storeID); err != nil {
-		log.Error("Failed to delete store", slog.String("error", err.Error()))
-		return err
+	if err := s.storeRepository.Restore(ctx, storeID, store.DeletedAt.Time); err != nil {
+		log.Error("Failed to restore store", slog.String("error", err.Error()))
+		return nil, err
 	}
 
-	log.Info("Store deleted successfully", slog.String("storeID", store.ID.String()))
-	return nil
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    store.ID,
+		Action:     domain.AuditActionStoreRestore,
+		TargetType: domain.AuditTargetStore,
+		TargetID:   store.ID.String(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	storeResponse := store.ToResponse()
+	storeResponse.Role = domain.StoreRoleOwner
+
+	log.Info("Store restored successfully", slog.String("storeID", store.ID.String()))
+	return storeResponse, nil
+}
+
+// PurgeDeleted hard-deletes stores that have been in the trash for longer
+// than the retention period, including their billboard images.
+func (s *storeService) PurgeDeleted(ctx context.Context) (int, error) {
+	log := slog.With(
+		slog.String("service", "store"),
+		slog.String("func", "PurgeDeleted"),
+	)
+
+	log.Info("Initializing deleted stores purge process")
+
+	stores, err := s.storeRepository.GetDeletedBefore(ctx, time.Now().UTC().Add(-s.getPurgeRetention()))
+	if err != nil {
+		log.Error("Failed to get deleted stores", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	purged := 0
+	for _, store := range stores {
+		imageURLs, err := s.storeRepository.Purge(ctx, store.ID)
+		if err != nil {
+			log.Error("Failed to purge store", slog.String("storeID", store.ID.String()), slog.String("error", err.Error()))
+			continue
+		}
+
+		for _, imageURL := range imageURLs {
+			if err := s.cloudFlareService.DeleteImage(imageURL); err != nil {
+				log.Error("Failed to delete image from cloud", slog.String("imageURL", imageURL), slog.String("error", err.Error()))
+			}
+		}
+
+		purged++
+	}
+
+	log.Info("Deleted stores purge executed successfully", slog.Int("purged", purged))
+	return purged, nil
+}
+
+// ResolveSlug finds the public store behind a storefront slug. Old slugs
+// return a StoreSlugMovedError carrying the current one.
+func (s *storeService) ResolveSlug(ctx context.Context, slug string) (*domain.Store, error) {
+	log := slog.With(
+		slog.String("service", "store"),
+		slog.String("func", "ResolveSlug"),
+	)
+
+	slug = domain.NormalizeStoreSlug(slug)
+
+	store, err := s.storeRepository.GetBySlug(ctx, slug)
+	if err != nil {
+		log.Error("Failed to get store by slug", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if store == nil {
+		storeSlugRedirect, err := s.storeRepository.GetSlugRedirect(ctx, slug)
+		if err != nil {
+			log.Error("Failed to get store slug redirect", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		if storeSlugRedirect == nil {
+			log.Warn("Store slug not found", slog.String("slug", slug))
+			return nil, domain.ErrStoreNotFound
+		}
+
+		store, err = s.storeRepository.GetByID(ctx, storeSlugRedirect.StoreID)
+		if err != nil {
+			log.Error("Failed to get store by id", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		if store != nil && store.IsPublic {
+			log.Info("Store slug moved", slog.String("slug", slug), slog.String("newSlug", store.Slug))
+			return nil, &domain.StoreSlugMovedError{Slug: store.Slug}
+		}
+	}
+
+	if store == nil || !store.IsPublic {
+		log.Warn("No public store found for slug", slog.String("slug", slug))
+		return nil, domain.ErrStoreNotFound
+	}
+
+	return store, nil
+}
+
+// generateStoreSlug picks a free slug derived from the store name.
+func generateStoreSlug(ctx context.Context, storeRepository domain.StoreRepository, name string) (string, error) {
+	return domain.GenerateStoreSlug(name, func(slug string) (bool, error) {
+		return storeRepository.ExistsBySlug(ctx, slug)
+	})
+}
+
+func (s *storeService) getPurgeRetention() time.Duration {
+	if config.Env.StorePurgeRetentionDays <= 0 {
+		return defaultStorePurgeRetentionDays * 24 * time.Hour
+	}
+	return time.Duration(config.Env.StorePurgeRetentionDays) * 24 * time.Hour
 }
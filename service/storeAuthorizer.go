@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/middleware"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+type storeAuthorizer struct {
+	i                     *do.Injector
+	storeRepository       domain.StoreRepository
+	storeMemberRepository domain.StoreMemberRepository
+}
+
+func NewStoreAuthorizer(i *do.Injector) (domain.StoreAuthorizer, error) {
+	storeRepository, err := do.Invoke[domain.StoreRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	storeMemberRepository, err := do.Invoke[domain.StoreMemberRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storeAuthorizer{
+		i:                     i,
+		storeRepository:       storeRepository,
+		storeMemberRepository: storeMemberRepository,
+	}, nil
+}
+
+func (s *storeAuthorizer) Authorize(ctx context.Context, storeID uuid.UUID, permission domain.StorePermission) (*domain.StoreAccess, error) {
+	log := slog.With(
+		slog.String("service", "storeAuthorizer"),
+		slog.String("func", "Authorize"),
+	)
+
+	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrUserNotFoundInContext
+	}
+
+	store, err := s.storeRepository.GetByID(ctx, storeID)
+	if err != nil {
+		log.Error("Failed to get store by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if store == nil {
+		log.Warn("store not found with this id")
+		return nil, domain.ErrStoreNotFound
+	}
+
+	if session.IsAPIKey() {
+		scope, ok := permission.Scope()
+		if !ok {
+			log.Warn("Permission not available to api keys", slog.String("permission", string(permission)))
+			return nil, domain.ErrAPIKeyNotAllowed
+		}
+
+		if session.StoreID != store.ID || !session.HasScope(scope) {
+			log.Warn("API key not allowed to access store", slog.String("storeID", store.ID.String()), slog.String("permission", string(permission)))
+			return nil, fmt.Errorf("%w: api key %s is not authorized to %s on store %s", domain.ErrUnauthorizedAction, session.APIKeyID.String(), permission, store.ID.String())
+		}
+
+		return &domain.StoreAccess{Session: session, Store: store}, nil
+	}
+
+	role, err := s.getRole(ctx, store, session.UserID)
+	if err != nil {
+		log.Error("Failed to get store role", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if role == "" {
+		log.Warn("User is not a member of the store", slog.String("storeID", store.ID.String()), slog.String("userID", session.UserID.String()))
+		return nil, domain.ErrStoreNotFound
+	}
+
+	if !role.Can(permission) {
+		log.Warn("Unauthorized store action", slog.String("storeID", store.ID.String()), slog.String("userID", session.UserID.String()), slog.String("role", string(role)), slog.String("permission", string(permission)))
+		return nil, fmt.Errorf("%w: user %s with role %s is not authorized to %s on store %s", domain.ErrUnauthorizedAction, session.UserID.String(), role, permission, store.ID.String())
+	}
+
+	return &domain.StoreAccess{Session: session, Store: store, Role: role}, nil
+}
+
+func (s *storeAuthorizer) getRole(ctx context.Context, store *domain.Store, userID uuid.UUID) (domain.StoreRole, error) {
+	if store.UserID == userID {
+		return domain.StoreRoleOwner, nil
+	}
+
+	storeMember, err := s.storeMemberRepository.GetByStoreAndUser(ctx, store.ID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if storeMember == nil {
+		return "", nil
+	}
+
+	return storeMember.Role, nil
+}
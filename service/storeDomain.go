@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/client"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+const storeDomainTokenSize = 24
+
+type storeDomainService struct {
+	i                     *do.Injector
+	storeDomainRepository domain.StoreDomainRepository
+	storeAuthorizer       domain.StoreAuthorizer
+	dnsResolver           client.DNSResolver
+	auditService          domain.AuditService
+}
+
+func NewStoreDomainService(i *do.Injector) (domain.StoreDomainService, error) {
+	storeDomainRepository, err := do.Invoke[domain.StoreDomainRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	storeAuthorizer, err := do.Invoke[domain.StoreAuthorizer](i)
+	if err != nil {
+		return nil, err
+	}
+
+	dnsResolver, err := do.Invoke[client.DNSResolver](i)
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := do.Invoke[domain.AuditService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storeDomainService{
+		i:                     i,
+		storeDomainRepository: storeDomainRepository,
+		storeAuthorizer:       storeAuthorizer,
+		dnsResolver:           dnsResolver,
+		auditService:          auditService,
+	}, nil
+}
+
+func (s *storeDomainService) Create(ctx context.Context, storeID uuid.UUID, storeDomainPayload domain.StoreDomainPayload) (*domain.StoreDomainResponse, error) {
+	log := slog.With(
+		slog.String("service", "storeDomain"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing store domain creation process")
+
+	storeAccess, err := s.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionStoreUpdate)
+	if err != nil {
+		log.Warn("Store domain creation not authorized", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	existingStoreDomain, err := s.storeDomainRepository.GetByStoreAndHostname(ctx, storeID, storeDomainPayload.Hostname)
+	if err != nil {
+		log.Error("Failed to get store domain", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if existingStoreDomain != nil {
+		log.Warn("Hostname already added to this store")
+		return nil, domain.ErrStoreDomainAlreadyInUse
+	}
+
+	verifiedStoreDomain, err := s.storeDomainRepository.GetVerifiedByHostname(ctx, storeDomainPayload.Hostname)
+	if err != nil {
+		log.Error("Failed to get verified store domain", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if verifiedStoreDomain != nil {
+		log.Warn("Hostname already verified by another store")
+		return nil, domain.ErrStoreDomainAlreadyInUse
+	}
+
+	verificationToken, err := secure.GenerateRandomToken(storeDomainTokenSize)
+	if err != nil {
+		log.Error("Failed to generate verification token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	storeDomain := storeDomainPayload.ToStoreDomain(storeID, verificationToken)
+
+	if err := s.storeDomainRepository.Create(ctx, *storeDomain); err != nil {
+		log.Error("Failed to create store domain", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    storeID,
+		Action:     domain.AuditActionDomainCreate,
+		TargetType: domain.AuditTargetDomain,
+		TargetID:   storeDomain.ID.String(),
+		After:      map[string]any{"hostname": storeDomain.Hostname},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Store domain created successfully", slog.String("storeDomainID", storeDomain.ID.String()))
+	return storeDomain.ToResponse().In(storeAccess.Store.Settings.Location()), nil
+}
+
+func (s *storeDomainService) GetAll(ctx context.Context, storeID uuid.UUID) ([]*domain.StoreDomainResponse, error) {
+	log := slog.With(
+		slog.String("service", "storeDomain"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all store domains process")
+
+	storeAccess, err := s.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionStoreRead)
+	if err != nil {
+		log.Warn("Store domains listing not authorized", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	storeDomains, err := s.storeDomainRepository.GetAll(ctx, storeID)
+	if err != nil {
+		log.Error("Failed to get store domains", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	location := storeAccess.Store.Settings.Location()
+	storeDomainsResponse := make([]*domain.StoreDomainResponse, 0, len(storeDomains))
+	for _, storeDomain := range storeDomains {
+		storeDomainsResponse = append(storeDomainsResponse, storeDomain.ToResponse().In(location))
+	}
+
+	log.Info("Store domains retrieved successfully", slog.Int("domainCount", len(storeDomainsResponse)))
+	return storeDomainsResponse, nil
+}
+
+// Verify looks up the TXT record shown to the owner when the hostname was
+// added. Only one store can hold a verified hostname at a time.
+func (s *storeDomainService) Verify(ctx context.Context, storeID uuid.UUID, storeDomainID uuid.UUID) (*domain.StoreDomainResponse, error) {
+	log := slog.With(
+		slog.String("service", "storeDomain"),
+		slog.String("func", "Verify"),
+	)
+
+	log.Info("Initializing store domain verification process")
+
+	storeAccess, err := s.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionStoreUpdate)
+	if err != nil {
+		log.Warn("Store domain verification not authorized", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	storeDomain, err := s.getStoreDomain(ctx, storeID, storeDomainID)
+	if err != nil {
+		return nil, err
+	}
+
+	location := storeAccess.Store.Settings.Location()
+	if storeDomain.VerifiedAt != nil {
+		log.Info("Store domain already verified", slog.String("storeDomainID", storeDomainID.String()))
+		return storeDomain.ToResponse().In(location), nil
+	}
+
+	verifiedStoreDomain, err := s.storeDomainRepository.GetVerifiedByHostname(ctx, storeDomain.Hostname)
+	if err != nil {
+		log.Error("Failed to get verified store domain", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if verifiedStoreDomain != nil {
+		log.Warn("Hostname already verified by another store")
+		return nil, domain.ErrStoreDomainAlreadyInUse
+	}
+
+	records, err := s.dnsResolver.LookupTXT(ctx, storeDomain.VerificationRecordName())
+	if err != nil {
+		log.Error("Failed to lookup verification record", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	verified := false
+	for _, record := range records {
+		if record == storeDomain.VerificationRecordValue() {
+			verified = true
+			break
+		}
+	}
+
+	if !verified {
+		log.Warn("Verification record not found", slog.String("hostname", storeDomain.Hostname))
+		return nil, domain.ErrStoreDomainNotVerified
+	}
+
+	verifiedAt := time.Now().UTC()
+	if err := s.storeDomainRepository.UpdateVerifiedAt(ctx, storeDomainID, verifiedAt); err != nil {
+		log.Error("Failed to update store domain verification", slog.String("error", err.Error()))
+		return nil, err
+	}
+	storeDomain.VerifiedAt = &verifiedAt
+
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    storeID,
+		Action:     domain.AuditActionDomainVerify,
+		TargetType: domain.AuditTargetDomain,
+		TargetID:   storeDomain.ID.String(),
+		After:      map[string]any{"hostname": storeDomain.Hostname},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Store domain verified successfully", slog.String("storeDomainID", storeDomainID.String()))
+	return storeDomain.ToResponse().In(location), nil
+}
+
+func (s *storeDomainService) Delete(ctx context.Context, storeID uuid.UUID, storeDomainID uuid.UUID) error {
+	log := slog.With(
+		slog.String("service", "storeDomain"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing store domain delete process")
+
+	if _, err := s.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionStoreUpdate); err != nil {
+		log.Warn("Store domain delete not authorized", slog.String("error", err.Error()))
+		return err
+	}
+
+	storeDomain, err := s.getStoreDomain(ctx, storeID, storeDomainID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.storeDomainRepository.Delete(ctx, storeDomainID); err != nil {
+		log.Error("Failed to delete store domain", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    storeID,
+		Action:     domain.AuditActionDomainDelete,
+		TargetType: domain.AuditTargetDomain,
+		TargetID:   storeDomain.ID.String(),
+		Before:     map[string]any{"hostname": storeDomain.Hostname},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Store domain deleted successfully", slog.String("storeDomainID", storeDomainID.String()))
+	return nil
+}
+
+func (s *storeDomainService) ResolveHost(ctx context.Context, hostname string) (*domain.Store, error) {
+	log := slog.With(
+		slog.String("service", "storeDomain"),
+		slog.String("func", "ResolveHost"),
+	)
+
+	storeDomain, err := s.storeDomainRepository.GetVerifiedByHostname(ctx, domain.NormalizeHostname(hostname))
+	if err != nil {
+		log.Error("Failed to get verified store domain", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if storeDomain == nil || storeDomain.Store.ID == uuid.Nil || !storeDomain.Store.IsPublic {
+		log.Warn("No public store found for hostname", slog.String("hostname", hostname))
+		return nil, domain.ErrStoreNotFound
+	}
+
+	return &storeDomain.Store, nil
+}
+
+func (s *storeDomainService) getStoreDomain(ctx context.Context, storeID uuid.UUID, storeDomainID uuid.UUID) (*domain.StoreDomain, error) {
+	log := slog.With(
+		slog.String("service", "storeDomain"),
+		slog.String("func", "getStoreDomain"),
+	)
+
+	storeDomain, err := s.storeDomainRepository.GetByID(ctx, storeDomainID)
+	if err != nil {
+		log.Error("Failed to get store domain", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if storeDomain == nil || storeDomain.StoreID != storeID {
+		log.Warn("Store domain not found for this store")
+		return nil, domain.ErrStoreDomainNotFound
+	}
+
+	return storeDomain, nil
+}
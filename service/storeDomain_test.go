@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/client"
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+)
+
+type fakeStoreDomainRepository struct {
+	domain.StoreDomainRepository
+	storeDomains []*domain.StoreDomain
+}
+
+func (f *fakeStoreDomainRepository) GetByID(_ context.Context, storeDomainID uuid.UUID) (*domain.StoreDomain, error) {
+	for _, storeDomain := range f.storeDomains {
+		if storeDomain.ID == storeDomainID {
+			return storeDomain, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeStoreDomainRepository) GetVerifiedByHostname(_ context.Context, hostname string) (*domain.StoreDomain, error) {
+	for _, storeDomain := range f.storeDomains {
+		if storeDomain.Hostname == hostname && storeDomain.VerifiedAt != nil {
+			return storeDomain, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeStoreDomainRepository) UpdateVerifiedAt(_ context.Context, storeDomainID uuid.UUID, verifiedAt time.Time) error {
+	for _, storeDomain := range f.storeDomains {
+		if storeDomain.ID == storeDomainID {
+			storeDomain.VerifiedAt = &verifiedAt
+		}
+	}
+	return nil
+}
+
+type fakeStoreAuthorizer struct {
+	domain.StoreAuthorizer
+}
+
+func (f *fakeStoreAuthorizer) Authorize(_ context.Context, storeID uuid.UUID, _ domain.StorePermission) (*domain.StoreAccess, error) {
+	return &domain.StoreAccess{Store: &domain.Store{ID: storeID}}, nil
+}
+
+// newTestStoreDomainService resolves TXT records through the local resolver,
+// reading them from a file holding records.
+func newTestStoreDomainService(t *testing.T, records string, storeDomains ...*domain.StoreDomain) *storeDomainService {
+	t.Helper()
+
+	env := config.Env
+	t.Cleanup(func() { config.Env = env })
+	config.Env.DNSRecordsPath = filepath.Join(t.TempDir(), "dns-records")
+
+	if err := os.WriteFile(config.Env.DNSRecordsPath, []byte(records), 0o600); err != nil {
+		t.Fatalf("write dns records: %v", err)
+	}
+
+	dnsResolver, err := client.NewDNSResolver(nil)
+	if err != nil {
+		t.Fatalf("NewDNSResolver() error = %v", err)
+	}
+
+	return &storeDomainService{
+		storeDomainRepository: &fakeStoreDomainRepository{storeDomains: storeDomains},
+		storeAuthorizer:       &fakeStoreAuthorizer{},
+		dnsResolver:           dnsResolver,
+		auditService:          &fakeAuditService{},
+	}
+}
+
+func TestStoreDomainServiceVerify(t *testing.T) {
+	const hostname = "shop.example.com"
+	const recordName = domain.StoreDomainVerificationLabel + "." + hostname
+
+	verifiedAt := time.Now().UTC()
+	verifiedElsewhere := &domain.StoreDomain{ID: uuid.New(), StoreID: uuid.New(), Hostname: hostname, VerificationToken: "other", VerifiedAt: &verifiedAt}
+
+	tests := []struct {
+		name    string
+		records string
+		others  []*domain.StoreDomain
+		wantErr error
+	}{
+		{
+			name:    "matching TXT record",
+			records: "# local records\n" + recordName + " \"" + domain.StoreDomainVerificationValue + "token\"\n",
+		},
+		{
+			name:    "mismatched TXT record",
+			records: recordName + " " + domain.StoreDomainVerificationValue + "other\n",
+			wantErr: domain.ErrStoreDomainNotVerified,
+		},
+		{
+			name:    "record for another hostname",
+			records: domain.StoreDomainVerificationLabel + ".other.example.com " + domain.StoreDomainVerificationValue + "token\n",
+			wantErr: domain.ErrStoreDomainNotVerified,
+		},
+		{
+			name:    "already verified by another store",
+			records: recordName + " " + domain.StoreDomainVerificationValue + "token\n",
+			others:  []*domain.StoreDomain{verifiedElsewhere},
+			wantErr: domain.ErrStoreDomainAlreadyInUse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storeID := uuid.New()
+			storeDomain := &domain.StoreDomain{ID: uuid.New(), StoreID: storeID, Hostname: hostname, VerificationToken: "token"}
+			storeDomainService := newTestStoreDomainService(t, tt.records, append([]*domain.StoreDomain{storeDomain}, tt.others...)...)
+
+			storeDomainResponse, err := storeDomainService.Verify(context.Background(), storeID, storeDomain.ID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				if storeDomain.VerifiedAt != nil {
+					t.Fatal("store domain was verified despite the error")
+				}
+				return
+			}
+
+			if storeDomain.VerifiedAt == nil || storeDomainResponse.VerifiedAt == nil {
+				t.Fatal("store domain was not marked as verified")
+			}
+		})
+	}
+}
+
+func TestStoreDomainServiceVerifyRejectsAnotherStoresDomain(t *testing.T) {
+	storeDomain := &domain.StoreDomain{ID: uuid.New(), StoreID: uuid.New(), Hostname: "shop.example.com", VerificationToken: "token"}
+	storeDomainService := newTestStoreDomainService(t, "", storeDomain)
+
+	if _, err := storeDomainService.Verify(context.Background(), uuid.New(), storeDomain.ID); !errors.Is(err, domain.ErrStoreDomainNotFound) {
+		t.Fatalf("Verify() error = %v, want %v", err, domain.ErrStoreDomainNotFound)
+	}
+}
+
+func TestStoreDomainServiceResolveHost(t *testing.T) {
+	verifiedAt := time.Now().UTC()
+	publicStore := domain.Store{ID: uuid.New(), IsPublic: true}
+	privateStore := domain.Store{ID: uuid.New()}
+
+	storeDomainService := newTestStoreDomainService(t, "",
+		&domain.StoreDomain{ID: uuid.New(), StoreID: publicStore.ID, Store: publicStore, Hostname: "shop.example.com", VerifiedAt: &verifiedAt},
+		&domain.StoreDomain{ID: uuid.New(), StoreID: publicStore.ID, Store: publicStore, Hostname: "pending.example.com"},
+		&domain.StoreDomain{ID: uuid.New(), StoreID: privateStore.ID, Store: privateStore, Hostname: "private.example.com", VerifiedAt: &verifiedAt},
+	)
+
+	tests := []struct {
+		name    string
+		host    string
+		wantErr error
+	}{
+		{name: "verified hostname", host: "shop.example.com"},
+		{name: "host header with port and uppercase", host: "Shop.Example.com:8080"},
+		{name: "unverified hostname", host: "pending.example.com", wantErr: domain.ErrStoreNotFound},
+		{name: "unpublished store", host: "private.example.com", wantErr: domain.ErrStoreNotFound},
+		{name: "unknown hostname", host: "unknown.example.com", wantErr: domain.ErrStoreNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := storeDomainService.ResolveHost(context.Background(), tt.host)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResolveHost() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && store.ID != publicStore.ID {
+				t.Fatalf("ResolveHost() store = %s, want %s", store.ID, publicStore.ID)
+			}
+		})
+	}
+}
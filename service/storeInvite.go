@@ -0,0 +1,575 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/middleware"
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+const (
+	defaultStoreInviteExp = 72
+	storeInviteTokenType  = "store_invite"
+	storeEmailTimeLayout  = "January 2, 2006 at 3:04 PM MST"
+)
+
+type storeInviteService struct {
+	i                     *do.Injector
+	storeInviteRepository domain.StoreInviteRepository
+	storeMemberRepository domain.StoreMemberRepository
+	userRepository        domain.UserRepository
+	storeAuthorizer       domain.StoreAuthorizer
+	sessionService        domain.SessionService
+	emailService          domain.EmailService
+	auditService          domain.AuditService
+	loginHistoryService   domain.LoginHistoryService
+}
+
+func NewStoreInviteService(i *do.Injector) (domain.StoreInviteService, error) {
+	storeInviteRepository, err := do.Invoke[domain.StoreInviteRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	storeMemberRepository, err := do.Invoke[domain.StoreMemberRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	userRepository, err := do.Invoke[domain.UserRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	storeAuthorizer, err := do.Invoke[domain.StoreAuthorizer](i)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionService, err := do.Invoke[domain.SessionService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	emailService, err := do.Invoke[domain.EmailService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := do.Invoke[domain.AuditService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	loginHistoryService, err := do.Invoke[domain.LoginHistoryService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storeInviteService{
+		i:                     i,
+		storeInviteRepository: storeInviteRepository,
+		storeMemberRepository: storeMemberRepository,
+		userRepository:        userRepository,
+		storeAuthorizer:       storeAuthorizer,
+		sessionService:        sessionService,
+		emailService:          emailService,
+		auditService:          auditService,
+		loginHistoryService:   loginHistoryService,
+	}, nil
+}
+
+func (s *storeInviteService) Create(ctx context.Context, storeID uuid.UUID, storeInvitePayload domain.StoreInvitePayload) (*domain.StoreInviteResponse, error) {
+	log := slog.With(
+		slog.String("service", "storeInvite"),
+		slog.String("func", "Create"),
+	)
+
+	log.Info("Initializing store invite creation process")
+
+	storeAccess, err := s.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionMembersManage)
+	if err != nil {
+		log.Warn("Store invite creation not authorized", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if storeAccess.Role != domain.StoreRoleOwner && storeInvitePayload.Role == domain.StoreRoleAdmin {
+		log.Warn("Only the owner can invite admins")
+		return nil, fmt.Errorf("%w: only the owner can invite admins", domain.ErrUnauthorizedAction)
+	}
+
+	if err := s.checkNotMember(ctx, storeAccess.Store, storeInvitePayload.Email); err != nil {
+		return nil, err
+	}
+
+	pendingInvite, err := s.storeInviteRepository.GetPending(ctx, storeID, storeInvitePayload.Email)
+	if err != nil {
+		log.Error("Failed to get pending store invite", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if pendingInvite != nil {
+		log.Warn("Pending store invite already exists")
+		return nil, domain.ErrStoreInviteAlreadyExists
+	}
+
+	storeInvite := storeInvitePayload.ToStoreInvite(storeID, storeAccess.Session.UserID, time.Now().UTC().Add(s.getExpiration()))
+
+	if err := s.storeInviteRepository.Create(ctx, *storeInvite); err != nil {
+		log.Error("Failed to create store invite", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := s.send(ctx, storeInvite, storeAccess.Store, storeAccess.Session.Name); err != nil {
+		log.Error("Failed to send store invite", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    storeInvite.StoreID,
+		Action:     domain.AuditActionInviteCreate,
+		TargetType: domain.AuditTargetInvite,
+		TargetID:   storeInvite.ID.String(),
+		After:      storeInvite.ToResponse(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Store invite created successfully", slog.String("storeID", storeID.String()), slog.String("inviteID", storeInvite.ID.String()))
+	return storeInvite.ToResponse().In(storeAccess.Store.Settings.Location()), nil
+}
+
+func (s *storeInviteService) GetAll(ctx context.Context, storeID uuid.UUID) ([]*domain.StoreInviteResponse, error) {
+	log := slog.With(
+		slog.String("service", "storeInvite"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all store invites process")
+
+	storeAccess, err := s.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionMembersRead)
+	if err != nil {
+		log.Warn("Store invites listing not authorized", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	storeInvites, err := s.storeInviteRepository.GetAll(ctx, storeID)
+	if err != nil {
+		log.Error("Failed to get store invites", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	location := storeAccess.Store.Settings.Location()
+	storeInvitesResponse := make([]*domain.StoreInviteResponse, 0, len(storeInvites))
+	for _, storeInvite := range storeInvites {
+		storeInvitesResponse = append(storeInvitesResponse, storeInvite.ToResponse().In(location))
+	}
+
+	log.Info("Store invites retrieved successfully", slog.Int("inviteCount", len(storeInvitesResponse)))
+	return storeInvitesResponse, nil
+}
+
+func (s *storeInviteService) Resend(ctx context.Context, storeID uuid.UUID, inviteID uuid.UUID) error {
+	log := slog.With(
+		slog.String("service", "storeInvite"),
+		slog.String("func", "Resend"),
+	)
+
+	log.Info("Initializing store invite resend process")
+
+	storeAccess, storeInvite, err := s.getManageableInvite(ctx, storeID, inviteID)
+	if err != nil {
+		return err
+	}
+
+	status := storeInvite.Status()
+	if status != domain.StoreInviteStatusPending && status != domain.StoreInviteStatusExpired {
+		log.Warn("Store invite is no longer pending", slog.String("status", string(status)))
+		return domain.ErrStoreInviteInvalid
+	}
+
+	storeInvite.ExpiresAt = time.Now().UTC().Add(s.getExpiration())
+
+	if err := s.storeInviteRepository.UpdateExpiration(ctx, storeInvite.ID, storeInvite.ExpiresAt); err != nil {
+		log.Error("Failed to update store invite expiration", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.send(ctx, storeInvite, storeAccess.Store, storeAccess.Session.Name); err != nil {
+		log.Error("Failed to send store invite", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    storeInvite.StoreID,
+		Action:     domain.AuditActionInviteResend,
+		TargetType: domain.AuditTargetInvite,
+		TargetID:   storeInvite.ID.String(),
+		After:      map[string]any{"expiresAt": storeInvite.ExpiresAt},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Store invite resent successfully", slog.String("inviteID", inviteID.String()))
+	return nil
+}
+
+func (s *storeInviteService) Revoke(ctx context.Context, storeID uuid.UUID, inviteID uuid.UUID) error {
+	log := slog.With(
+		slog.String("service", "storeInvite"),
+		slog.String("func", "Revoke"),
+	)
+
+	log.Info("Initializing store invite revoke process")
+
+	_, storeInvite, err := s.getManageableInvite(ctx, storeID, inviteID)
+	if err != nil {
+		return err
+	}
+
+	status := storeInvite.Status()
+	if status != domain.StoreInviteStatusPending && status != domain.StoreInviteStatusExpired {
+		log.Warn("Store invite is no longer pending", slog.String("status", string(status)))
+		return domain.ErrStoreInviteInvalid
+	}
+
+	if err := s.storeInviteRepository.Revoke(ctx, storeInvite.ID); err != nil {
+		log.Error("Failed to revoke store invite", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    storeInvite.StoreID,
+		Action:     domain.AuditActionInviteRevoke,
+		TargetType: domain.AuditTargetInvite,
+		TargetID:   storeInvite.ID.String(),
+		Before:     storeInvite.ToResponse(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Store invite revoked successfully", slog.String("inviteID", inviteID.String()))
+	return nil
+}
+
+func (s *storeInviteService) Accept(ctx context.Context, storeInviteTokenPayload domain.StoreInviteTokenPayload) (*domain.StoreResponse, error) {
+	log := slog.With(
+		slog.String("service", "storeInvite"),
+		slog.String("func", "Accept"),
+	)
+
+	log.Info("Initializing store invite accept process")
+
+	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || session == nil {
+		log.Error("User not found in context")
+		return nil, domain.ErrUserNotFoundInContext
+	}
+
+	storeInvite, err := s.getInviteFromToken(ctx, storeInviteTokenPayload.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(session.Email, storeInvite.Email) {
+		log.Warn("Store invite email does not match the signed in user")
+		return nil, domain.ErrStoreInviteEmailMismatch
+	}
+
+	if err := s.checkNotMember(ctx, &storeInvite.Store, storeInvite.Email); err != nil {
+		return nil, err
+	}
+
+	if err := s.storeInviteRepository.Accept(ctx, storeInvite.ID, *storeInvite.ToStoreMember(session.UserID)); err != nil {
+		log.Error("Failed to accept store invite", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    storeInvite.StoreID,
+		Action:     domain.AuditActionInviteAccept,
+		TargetType: domain.AuditTargetInvite,
+		TargetID:   storeInvite.ID.String(),
+		After:      map[string]any{"role": storeInvite.Role},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	storeResponse := storeInvite.Store.ToResponse()
+	storeResponse.Role = storeInvite.Role
+
+	log.Info("Store invite accepted successfully", slog.String("inviteID", storeInvite.ID.String()), slog.String("userID", session.UserID.String()))
+	return storeResponse, nil
+}
+
+func (s *storeInviteService) Decline(ctx context.Context, storeInviteTokenPayload domain.StoreInviteTokenPayload) error {
+	log := slog.With(
+		slog.String("service", "storeInvite"),
+		slog.String("func", "Decline"),
+	)
+
+	log.Info("Initializing store invite decline process")
+
+	storeInvite, err := s.getInviteFromToken(ctx, storeInviteTokenPayload.Token)
+	if err != nil {
+		return err
+	}
+
+	if err := s.storeInviteRepository.Decline(ctx, storeInvite.ID); err != nil {
+		log.Error("Failed to decline store invite", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    storeInvite.StoreID,
+		Action:     domain.AuditActionInviteDecline,
+		TargetType: domain.AuditTargetInvite,
+		TargetID:   storeInvite.ID.String(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Store invite declined successfully", slog.String("inviteID", storeInvite.ID.String()))
+	return nil
+}
+
+func (s *storeInviteService) SignUp(ctx context.Context, storeInviteSignUpPayload domain.StoreInviteSignUpPayload) (*domain.SessionResponse, error) {
+	log := slog.With(
+		slog.String("service", "storeInvite"),
+		slog.String("func", "SignUp"),
+	)
+
+	log.Info("Initializing store invite sign up process")
+
+	storeInvite, err := s.getInviteFromToken(ctx, storeInviteSignUpPayload.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.userRepository.ExistsByEmail(ctx, storeInvite.Email)
+	if err != nil {
+		log.Error("Failed to check if email exists", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if exists {
+		log.Warn("User already exists")
+		return nil, domain.ErrUserAlreadyExists
+	}
+
+	passwordHash, err := secure.Hash(storeInviteSignUpPayload.Password)
+	if err != nil {
+		log.Error("Failed to hash password", slog.String("error", err.Error()))
+		return nil, domain.ErrHashingPassword
+	}
+
+	userPayload := domain.UserPayLoad{
+		Name:  storeInviteSignUpPayload.Name,
+		Email: storeInvite.Email,
+	}
+
+	user := userPayload.ToUser(string(passwordHash))
+	user.EmailConfirmed = true
+
+	user.Username, err = generateUsername(ctx, s.userRepository, user.Name)
+	if err != nil {
+		log.Error("Failed to generate username", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := s.storeInviteRepository.AcceptWithUser(ctx, storeInvite.ID, *user, *storeInvite.ToStoreMember(user.ID)); err != nil {
+		log.Error("Failed to create user and accept store invite", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	sessionResponse, err := s.sessionService.Create(ctx, *user)
+	if err != nil {
+		log.Error("Failed to create session", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := s.loginHistoryService.Record(ctx, domain.LoginAttempt{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Method:    domain.LoginMethodInvite,
+		Result:    domain.LoginResultSuccess,
+		SessionID: sessionResponse.SessionID,
+	}); err != nil {
+		log.Error("Failed to record login history", slog.String("error", err.Error()))
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    storeInvite.StoreID,
+		ActorID:    user.ID,
+		Action:     domain.AuditActionInviteAccept,
+		TargetType: domain.AuditTargetInvite,
+		TargetID:   storeInvite.ID.String(),
+		After:      map[string]any{"role": storeInvite.Role},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("User signed up through store invite successfully", slog.String("inviteID", storeInvite.ID.String()), slog.String("userID", user.ID.String()))
+	return sessionResponse, nil
+}
+
+func (s *storeInviteService) getManageableInvite(ctx context.Context, storeID uuid.UUID, inviteID uuid.UUID) (*domain.StoreAccess, *domain.StoreInvite, error) {
+	log := slog.With(
+		slog.String("service", "storeInvite"),
+		slog.String("func", "getManageableInvite"),
+	)
+
+	storeAccess, err := s.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionMembersManage)
+	if err != nil {
+		log.Warn("Store invite management not authorized", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+
+	storeInvite, err := s.storeInviteRepository.GetByID(ctx, inviteID)
+	if err != nil {
+		log.Error("Failed to get store invite", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+
+	if storeInvite == nil || storeInvite.StoreID != storeID {
+		log.Warn("Store invite not found")
+		return nil, nil, domain.ErrStoreInviteNotFound
+	}
+
+	if storeAccess.Role != domain.StoreRoleOwner && storeInvite.Role == domain.StoreRoleAdmin {
+		log.Warn("Only the owner can manage admin invites")
+		return nil, nil, fmt.Errorf("%w: only the owner can manage admin invites", domain.ErrUnauthorizedAction)
+	}
+
+	return storeAccess, storeInvite, nil
+}
+
+func (s *storeInviteService) checkNotMember(ctx context.Context, store *domain.Store, email string) error {
+	log := slog.With(
+		slog.String("service", "storeInvite"),
+		slog.String("func", "checkNotMember"),
+	)
+
+	user, err := s.userRepository.GetByEmail(ctx, email)
+	if err != nil {
+		log.Error("Failed to get user by email", slog.String("error", err.Error()))
+		return err
+	}
+
+	if user == nil {
+		return nil
+	}
+
+	if store.UserID == user.ID {
+		log.Warn("User is the store owner")
+		return domain.ErrStoreMemberAlreadyExists
+	}
+
+	storeMember, err := s.storeMemberRepository.GetByStoreAndUser(ctx, store.ID, user.ID)
+	if err != nil {
+		log.Error("Failed to get store member", slog.String("error", err.Error()))
+		return err
+	}
+
+	if storeMember != nil {
+		log.Warn("User is already a store member")
+		return domain.ErrStoreMemberAlreadyExists
+	}
+
+	return nil
+}
+
+func (s *storeInviteService) getInviteFromToken(ctx context.Context, token string) (*domain.StoreInvite, error) {
+	log := slog.With(
+		slog.String("service", "storeInvite"),
+		slog.String("func", "getInviteFromToken"),
+	)
+
+	parsedToken, err := jwt.Parse(token, config.Env.KeyRing.Keyfunc)
+	if err != nil || !parsedToken.Valid {
+		log.Warn("Store invite token is invalid")
+		return nil, domain.ErrStoreInviteInvalid
+	}
+
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok || claims["typ"] != storeInviteTokenType {
+		log.Warn("Store invite token has unexpected claims")
+		return nil, domain.ErrStoreInviteInvalid
+	}
+
+	subject, _ := claims["sub"].(string)
+	inviteID, err := uuid.Parse(subject)
+	if err != nil {
+		log.Warn("Store invite token has an invalid subject")
+		return nil, domain.ErrStoreInviteInvalid
+	}
+
+	storeInvite, err := s.storeInviteRepository.GetByID(ctx, inviteID)
+	if err != nil {
+		log.Error("Failed to get store invite", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	email, _ := claims["email"].(string)
+	if storeInvite == nil || storeInvite.Store.ID == uuid.Nil || !strings.EqualFold(storeInvite.Email, email) {
+		log.Warn("Store invite not found")
+		return nil, domain.ErrStoreInviteInvalid
+	}
+
+	if storeInvite.Status() != domain.StoreInviteStatusPending {
+		log.Warn("Store invite is no longer pending", slog.String("status", string(storeInvite.Status())))
+		return nil, domain.ErrStoreInviteInvalid
+	}
+
+	return storeInvite, nil
+}
+
+func (s *storeInviteService) send(ctx context.Context, storeInvite *domain.StoreInvite, store *domain.Store, inviterName string) error {
+	token, err := s.createToken(storeInvite)
+	if err != nil {
+		return err
+	}
+
+	inviteURL := fmt.Sprintf("%s/invites?token=%s", strings.TrimSuffix(config.Env.URLFront, "/"), url.QueryEscape(token))
+	return s.emailService.SendStoreInvite(ctx, storeInvite.Email, domain.StoreInviteEmailPayload{
+		StoreName:    store.Name,
+		InviterName:  inviterName,
+		Role:         storeInvite.Role,
+		URL:          inviteURL,
+		ExpiresAt:    storeInvite.ExpiresAt.In(store.Settings.Location()).Format(storeEmailTimeLayout),
+		SupportEmail: store.Settings.SupportEmail,
+	})
+}
+
+func (s *storeInviteService) createToken(storeInvite *domain.StoreInvite) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"sub":   storeInvite.ID.String(),
+		"email": storeInvite.Email,
+		"typ":   storeInviteTokenType,
+		"jti":   uuid.New().String(),
+		"iat":   time.Now().UTC().Unix(),
+		"exp":   storeInvite.ExpiresAt.Unix(),
+	})
+
+	kid, signingKey := config.Env.KeyRing.SigningKey()
+	token.Header["kid"] = kid
+
+	return token.SignedString(signingKey)
+}
+
+func (s *storeInviteService) getExpiration() time.Duration {
+	if config.Env.StoreInviteExp <= 0 {
+		return defaultStoreInviteExp * time.Hour
+	}
+	return time.Duration(config.Env.StoreInviteExp) * time.Hour
+}
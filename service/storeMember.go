@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/middleware"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+type storeMemberService struct {
+	i                     *do.Injector
+	storeMemberRepository domain.StoreMemberRepository
+	userRepository        domain.UserRepository
+	storeAuthorizer       domain.StoreAuthorizer
+	auditService          domain.AuditService
+}
+
+func NewStoreMemberService(i *do.Injector) (domain.StoreMemberService, error) {
+	storeMemberRepository, err := do.Invoke[domain.StoreMemberRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	userRepository, err := do.Invoke[domain.UserRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	storeAuthorizer, err := do.Invoke[domain.StoreAuthorizer](i)
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := do.Invoke[domain.AuditService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storeMemberService{
+		i:                     i,
+		storeMemberRepository: storeMemberRepository,
+		userRepository:        userRepository,
+		storeAuthorizer:       storeAuthorizer,
+		auditService:          auditService,
+	}, nil
+}
+
+func (s *storeMemberService) GetAll(ctx context.Context, storeID uuid.UUID) ([]*domain.StoreMemberResponse, error) {
+	log := slog.With(
+		slog.String("service", "storeMember"),
+		slog.String("func", "GetAll"),
+	)
+
+	log.Info("Initializing get all store members process")
+
+	storeAccess, err := s.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionMembersRead)
+	if err != nil {
+		log.Warn("Store members listing not authorized", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	owner, err := s.userRepository.GetByID(ctx, storeAccess.Store.UserID)
+	if err != nil {
+		log.Error("Failed to get store owner", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	storeMembers, err := s.storeMemberRepository.GetAll(ctx, storeID)
+	if err != nil {
+		log.Error("Failed to get store members", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	location := storeAccess.Store.Settings.Location()
+	storeMembersResponse := make([]*domain.StoreMemberResponse, 0, len(storeMembers)+1)
+	if owner != nil {
+		storeMembersResponse = append(storeMembersResponse, &domain.StoreMemberResponse{
+			UserID:    owner.ID.String(),
+			Name:      owner.Name,
+			Email:     owner.Email,
+			AvatarURL: owner.AvatarURL,
+			Role:      domain.StoreRoleOwner,
+			CreatedAt: storeAccess.Store.CreatedAt.In(location),
+		})
+	}
+
+	for _, storeMember := range storeMembers {
+		storeMembersResponse = append(storeMembersResponse, storeMember.ToResponse().In(location))
+	}
+
+	log.Info("Store members retrieved successfully", slog.Int("memberCount", len(storeMembersResponse)))
+	return storeMembersResponse, nil
+}
+
+func (s *storeMemberService) UpdateRole(ctx context.Context, storeID uuid.UUID, userID uuid.UUID, storeMemberRolePayload domain.StoreMemberRolePayload) error {
+	log := slog.With(
+		slog.String("service", "storeMember"),
+		slog.String("func", "UpdateRole"),
+	)
+
+	log.Info("Initializing store member role update process")
+
+	storeAccess, err := s.storeAuthorizer.Authorize(ctx, storeID, domain.PermissionMembersManage)
+	if err != nil {
+		log.Warn("Store member role update not authorized", slog.String("error", err.Error()))
+		return err
+	}
+
+	storeMember, err := s.getEditableMember(ctx, storeAccess, userID)
+	if err != nil {
+		return err
+	}
+
+	if storeAccess.Role != domain.StoreRoleOwner && storeMemberRolePayload.Role == domain.StoreRoleAdmin {
+		log.Warn("Only the owner can promote members to admin")
+		return fmt.Errorf("%w: only the owner can promote members to admin", domain.ErrUnauthorizedAction)
+	}
+
+	if err := s.storeMemberRepository.UpdateRole(ctx, storeID, storeMember.UserID, storeMemberRolePayload.Role); err != nil {
+		log.Error("Failed to update store member role", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    storeID,
+		Action:     domain.AuditActionMemberUpdateRole,
+		TargetType: domain.AuditTargetMember,
+		TargetID:   userID.String(),
+		Before:     map[string]any{"role": storeMember.Role},
+		After:      map[string]any{"role": storeMemberRolePayload.Role},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Store member role updated successfully", slog.String("storeID", storeID.String()), slog.String("userID", userID.String()), slog.String("role", string(storeMemberRolePayload.Role)))
+	return nil
+}
+
+func (s *storeMemberService) Delete(ctx context.Context, storeID uuid.UUID, userID uuid.UUID) error {
+	log := slog.With(
+		slog.String("service", "storeMember"),
+		slog.String("func", "Delete"),
+	)
+
+	log.Info("Initializing store member delete process")
+
+	permission := domain.PermissionMembersManage
+	if session, ok := ctx.Value(middleware.UserKey).(*domain.Session); ok && session != nil && session.UserID == userID {
+		permission = domain.PermissionStoreRead
+	}
+
+	storeAccess, err := s.storeAuthorizer.Authorize(ctx, storeID, permission)
+	if err != nil {
+		log.Warn("Store member delete not authorized", slog.String("error", err.Error()))
+		return err
+	}
+
+	storeMember, err := s.getEditableMember(ctx, storeAccess, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.storeMemberRepository.Delete(ctx, storeID, storeMember.UserID); err != nil {
+		log.Error("Failed to delete store member", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.auditService.Record(ctx, domain.AuditEntry{
+		StoreID:    storeID,
+		Action:     domain.AuditActionMemberDelete,
+		TargetType: domain.AuditTargetMember,
+		TargetID:   userID.String(),
+		Before:     map[string]any{"role": storeMember.Role},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Store member deleted successfully", slog.String("storeID", storeID.String()), slog.String("userID", userID.String()))
+	return nil
+}
+
+func (s *storeMemberService) getEditableMember(ctx context.Context, storeAccess *domain.StoreAccess, userID uuid.UUID) (*domain.StoreMember, error) {
+	log := slog.With(
+		slog.String("service", "storeMember"),
+		slog.String("func", "getEditableMember"),
+	)
+
+	if storeAccess.Store.UserID == userID {
+		log.Warn("Store owner cannot be edited")
+		return nil, domain.ErrStoreOwnerNotEditable
+	}
+
+	storeMember, err := s.storeMemberRepository.GetByStoreAndUser(ctx, storeAccess.Store.ID, userID)
+	if err != nil {
+		log.Error("Failed to get store member", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if storeMember == nil {
+		log.Warn("Store member not found")
+		return nil, domain.ErrStoreMemberNotFound
+	}
+
+	isSelf := storeAccess.Session.UserID == userID
+	if !isSelf && storeAccess.Role != domain.StoreRoleOwner && storeMember.Role == domain.StoreRoleAdmin {
+		log.Warn("Only the owner can manage admins")
+		return nil, fmt.Errorf("%w: only the owner can manage admins", domain.ErrUnauthorizedAction)
+	}
+
+	return storeMember, nil
+}
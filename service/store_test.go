@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/google/uuid"
+)
+
+type fakeStoreRepository struct {
+	domain.StoreRepository
+	stores    []*domain.Store
+	redirects []*domain.StoreSlugRedirect
+}
+
+func (f *fakeStoreRepository) GetByID(_ context.Context, storeID uuid.UUID) (*domain.Store, error) {
+	for _, store := range f.stores {
+		if store.ID == storeID {
+			return store, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeStoreRepository) GetBySlug(_ context.Context, slug string) (*domain.Store, error) {
+	for _, store := range f.stores {
+		if store.Slug == slug {
+			return store, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeStoreRepository) GetSlugRedirect(_ context.Context, slug string) (*domain.StoreSlugRedirect, error) {
+	for _, storeSlugRedirect := range f.redirects {
+		if storeSlugRedirect.Slug == slug {
+			return storeSlugRedirect, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestStoreServiceResolveSlug(t *testing.T) {
+	publicStore := &domain.Store{ID: uuid.New(), Slug: "new-shop", IsPublic: true}
+	privateStore := &domain.Store{ID: uuid.New(), Slug: "private-shop"}
+
+	storeService := &storeService{storeRepository: &fakeStoreRepository{
+		stores: []*domain.Store{publicStore, privateStore},
+		redirects: []*domain.StoreSlugRedirect{
+			{ID: uuid.New(), StoreID: publicStore.ID, Slug: "old-shop"},
+			{ID: uuid.New(), StoreID: privateStore.ID, Slug: "old-private-shop"},
+		},
+	}}
+
+	tests := []struct {
+		name      string
+		slug      string
+		wantMoved string
+		wantErr   error
+	}{
+		{name: "current slug", slug: "New-Shop"},
+		{name: "old slug moves to the current one", slug: "old-shop", wantMoved: "new-shop"},
+		{name: "old slug of an unpublished store", slug: "old-private-shop", wantErr: domain.ErrStoreNotFound},
+		{name: "unpublished store", slug: "private-shop", wantErr: domain.ErrStoreNotFound},
+		{name: "unknown slug", slug: "unknown-shop", wantErr: domain.ErrStoreNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := storeService.ResolveSlug(context.Background(), tt.slug)
+
+			var storeSlugMovedError *domain.StoreSlugMovedError
+			switch {
+			case tt.wantMoved != "":
+				if !errors.As(err, &storeSlugMovedError) || storeSlugMovedError.Slug != tt.wantMoved {
+					t.Fatalf("ResolveSlug() error = %v, want a move to %q", err, tt.wantMoved)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ResolveSlug() error = %v, want %v", err, tt.wantErr)
+				}
+			default:
+				if err != nil || store.ID != publicStore.ID {
+					t.Fatalf("ResolveSlug() = %v, %v, want store %s", store, err, publicStore.ID)
+				}
+			}
+		})
+	}
+}
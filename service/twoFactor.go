@@ -0,0 +1,404 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/middleware"
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/google/uuid"
+	"github.com/samber/do"
+)
+
+const recoveryCodesCount = 10
+
+type twoFactorService struct {
+	i                   *do.Injector
+	twoFactorRepository domain.TwoFactorRepository
+	userRepository      domain.UserRepository
+	sessionService      domain.SessionService
+	attemptService      domain.AttemptService
+	auditService        domain.AuditService
+	loginHistoryService domain.LoginHistoryService
+}
+
+func NewTwoFactorService(i *do.Injector) (domain.TwoFactorService, error) {
+	twoFactorRepository, err := do.Invoke[domain.TwoFactorRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	userRepository, err := do.Invoke[domain.UserRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionService, err := do.Invoke[domain.SessionService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	attemptService, err := do.Invoke[domain.AttemptService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := do.Invoke[domain.AuditService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	loginHistoryService, err := do.Invoke[domain.LoginHistoryService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	return &twoFactorService{
+		i:                   i,
+		twoFactorRepository: twoFactorRepository,
+		userRepository:      userRepository,
+		sessionService:      sessionService,
+		attemptService:      attemptService,
+		auditService:        auditService,
+		loginHistoryService: loginHistoryService,
+	}, nil
+}
+
+func (t *twoFactorService) Enroll(ctx context.Context) (*domain.TwoFactorEnrollResponse, error) {
+	log := slog.With(
+		slog.String("service", "twoFactor"),
+		slog.String("func", "Enroll"),
+	)
+
+	log.Info("Initializing two factor enroll process")
+
+	user, err := t.getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.EmailConfirmed {
+		log.Warn("User email not confirmed")
+		return nil, domain.ErrEmailNotConfirmed
+	}
+
+	if user.TwoFactorEnabled {
+		log.Warn("Two factor already enabled")
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	key, err := secure.GenerateTOTPKey(user.Email)
+	if err != nil {
+		log.Error("Failed to generate TOTP key", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	qrCode, err := secure.GenerateQRCode(key, 256)
+	if err != nil {
+		log.Error("Failed to generate QR code", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	encryptedSecret, err := config.Env.TwoFactorSecretBox.Seal(key.Secret())
+	if err != nil {
+		log.Error("Failed to encrypt two factor secret", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := t.userRepository.UpdateTwoFactor(ctx, user.ID, encryptedSecret, false); err != nil {
+		log.Error("Failed to save two factor secret", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Two factor enroll process executed successfully")
+	return &domain.TwoFactorEnrollResponse{
+		Secret:     key.Secret(),
+		OTPAuthURL: key.URL(),
+		QRCode:     qrCode,
+	}, nil
+}
+
+func (t *twoFactorService) Confirm(ctx context.Context, twoFactorCodePayload domain.TwoFactorCodePayload) (*domain.RecoveryCodesResponse, error) {
+	log := slog.With(
+		slog.String("service", "twoFactor"),
+		slog.String("func", "Confirm"),
+	)
+
+	log.Info("Initializing two factor confirm process")
+
+	user, err := t.getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TwoFactorEnabled {
+		log.Warn("Two factor already enabled")
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	if user.TwoFactorSecret == "" {
+		log.Warn("Two factor enrollment not started")
+		return nil, domain.ErrTwoFactorNotEnrolled
+	}
+
+	account := user.ID.String()
+	if err := t.attemptService.Check(ctx, domain.AttemptPurposeOTP, account); err != nil {
+		log.Warn("Two factor confirm blocked", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	valid, err := t.verifyTOTP(ctx, *user, twoFactorCodePayload.Code)
+	if err != nil {
+		log.Error("Failed to verify two factor code", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if !valid {
+		log.Warn("Invalid two factor code")
+		if err := t.attemptService.Fail(ctx, domain.AttemptPurposeOTP, account, user); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrTwoFactorCodeInvalid
+	}
+
+	if err := t.attemptService.Reset(ctx, domain.AttemptPurposeOTP, account); err != nil {
+		log.Error("Failed to reset two factor attempts", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	codes, err := secure.GenerateRecoveryCodes(recoveryCodesCount)
+	if err != nil {
+		log.Error("Failed to generate recovery codes", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	recoveryCodes := make([]domain.RecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		recoveryCodes = append(recoveryCodes, domain.RecoveryCode{
+			ID:        uuid.New(),
+			UserID:    user.ID,
+			CodeHash:  secure.HashToken(code),
+			CreatedAt: now,
+		})
+	}
+
+	if err := t.twoFactorRepository.ReplaceRecoveryCodes(ctx, user.ID, recoveryCodes); err != nil {
+		log.Error("Failed to save recovery codes", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := t.userRepository.UpdateTwoFactor(ctx, user.ID, user.TwoFactorSecret, true); err != nil {
+		log.Error("Failed to enable two factor", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := t.auditService.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditActionTwoFactorEnable,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID.String(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Two factor enabled successfully", slog.String("userID", user.ID.String()))
+	return &domain.RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	}, nil
+}
+
+func (t *twoFactorService) Disable(ctx context.Context, twoFactorCodePayload domain.TwoFactorCodePayload) error {
+	log := slog.With(
+		slog.String("service", "twoFactor"),
+		slog.String("func", "Disable"),
+	)
+
+	log.Info("Initializing two factor disable process")
+
+	user, err := t.getUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !user.TwoFactorEnabled {
+		log.Warn("Two factor not enabled")
+		return domain.ErrTwoFactorNotEnabled
+	}
+
+	valid, err := t.verifyCode(ctx, *user, twoFactorCodePayload.Code)
+	if err != nil {
+		log.Error("Failed to verify two factor code", slog.String("error", err.Error()))
+		return err
+	}
+
+	if !valid {
+		log.Warn("Invalid two factor code")
+		return domain.ErrTwoFactorCodeInvalid
+	}
+
+	if err := t.userRepository.UpdateTwoFactor(ctx, user.ID, "", false); err != nil {
+		log.Error("Failed to disable two factor", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := t.twoFactorRepository.DeleteRecoveryCodes(ctx, user.ID); err != nil {
+		log.Error("Failed to delete recovery codes", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := t.auditService.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditActionTwoFactorDisable,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID.String(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Two factor disabled successfully", slog.String("userID", user.ID.String()))
+	return nil
+}
+
+func (t *twoFactorService) SignIn(ctx context.Context, twoFactorSignInPayload domain.TwoFactorSignInPayload) (*domain.SessionResponse, error) {
+	log := slog.With(
+		slog.String("service", "twoFactor"),
+		slog.String("func", "SignIn"),
+	)
+
+	log.Info("Initializing two factor sign in process")
+
+	userID, err := t.sessionService.GetTwoFactorChallenge(ctx, twoFactorSignInPayload.ChallengeToken)
+	if err != nil {
+		log.Warn("Failed to get two factor challenge", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	user, err := t.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get user by ID", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if user == nil || !user.TwoFactorEnabled {
+		log.Warn("User not found or two factor disabled since challenge")
+		return nil, domain.ErrTwoFactorChallengeInvalid
+	}
+
+	valid, err := t.verifyCode(ctx, *user, twoFactorSignInPayload.Code)
+	if err != nil {
+		log.Error("Failed to verify two factor code", slog.String("error", err.Error()))
+		var attemptBlockedError *domain.AttemptBlockedError
+		if errors.As(err, &attemptBlockedError) {
+			if err := t.loginHistoryService.Record(ctx, domain.LoginAttempt{
+				UserID: user.ID,
+				Email:  user.Email,
+				Method: domain.LoginMethodTwoFactor,
+				Result: domain.LoginResultBlocked,
+			}); err != nil {
+				log.Error("Failed to record login history", slog.String("error", err.Error()))
+			}
+		}
+		return nil, err
+	}
+
+	if !valid {
+		log.Warn("Invalid two factor code")
+		if err := t.loginHistoryService.Record(ctx, domain.LoginAttempt{
+			UserID: user.ID,
+			Email:  user.Email,
+			Method: domain.LoginMethodTwoFactor,
+			Result: domain.LoginResultInvalidTwoFactor,
+		}); err != nil {
+			log.Error("Failed to record login history", slog.String("error", err.Error()))
+		}
+		return nil, domain.ErrTwoFactorCodeInvalid
+	}
+
+	if err := t.sessionService.DeleteTwoFactorChallenge(ctx, twoFactorSignInPayload.ChallengeToken); err != nil {
+		log.Error("Failed to delete two factor challenge", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	sessionResponse, err := t.sessionService.Create(ctx, *user)
+	if err != nil {
+		log.Error("Failed to create token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := t.loginHistoryService.Record(ctx, domain.LoginAttempt{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Method:    domain.LoginMethodTwoFactor,
+		Result:    domain.LoginResultSuccess,
+		SessionID: sessionResponse.SessionID,
+	}); err != nil {
+		log.Error("Failed to record login history", slog.String("error", err.Error()))
+	}
+
+	log.Info("Two factor sign in executed successfully")
+	return sessionResponse, nil
+}
+
+func (t *twoFactorService) verifyCode(ctx context.Context, user domain.User, code string) (bool, error) {
+	account := user.ID.String()
+
+	if err := t.attemptService.Check(ctx, domain.AttemptPurposeOTP, account); err != nil {
+		return false, err
+	}
+
+	valid, err := t.verifyTOTP(ctx, user, code)
+	if err != nil {
+		return false, err
+	}
+
+	if !valid {
+		valid, err = t.twoFactorRepository.UseRecoveryCode(ctx, user.ID, secure.HashToken(secure.NormalizeRecoveryCode(code)))
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if !valid {
+		return false, t.attemptService.Fail(ctx, domain.AttemptPurposeOTP, account, &user)
+	}
+
+	return true, t.attemptService.Reset(ctx, domain.AttemptPurposeOTP, account)
+}
+
+// verifyTOTP accepts each authenticator code once. A code that was already
+// used, even within its validity window, is treated as invalid. The secret is
+// stored encrypted and only decrypted here.
+func (t *twoFactorService) verifyTOTP(ctx context.Context, user domain.User, code string) (bool, error) {
+	secret, err := config.Env.TwoFactorSecretBox.Open(user.TwoFactorSecret)
+	if err != nil {
+		return false, err
+	}
+
+	step, valid := secure.ValidateTOTP(code, secret, time.Now().UTC())
+	if !valid {
+		return false, nil
+	}
+
+	return t.twoFactorRepository.UseTOTPStep(ctx, user.ID, step)
+}
+
+func (t *twoFactorService) getUserFromContext(ctx context.Context) (*domain.User, error) {
+	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || session == nil {
+		return nil, domain.ErrUserNotFoundInContext
+	}
+
+	user, err := t.userRepository.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	return user, nil
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/middleware"
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/google/uuid"
+)
+
+// fakeTwoFactorRepository remembers the last accepted TOTP step per user with
+// the same contract as the Redis script.
+type fakeTwoFactorRepository struct {
+	domain.TwoFactorRepository
+	totpSteps map[uuid.UUID]int64
+}
+
+func (f *fakeTwoFactorRepository) UseTOTPStep(_ context.Context, userID uuid.UUID, step int64) (bool, error) {
+	if last, ok := f.totpSteps[userID]; ok && last >= step {
+		return false, nil
+	}
+	f.totpSteps[userID] = step
+	return true, nil
+}
+
+func (f *fakeTwoFactorRepository) UseRecoveryCode(context.Context, uuid.UUID, string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeTwoFactorRepository) ReplaceRecoveryCodes(context.Context, uuid.UUID, []domain.RecoveryCode) error {
+	return nil
+}
+
+func (f *fakeUserRepository) UpdateTwoFactor(_ context.Context, id uuid.UUID, secret string, enabled bool) error {
+	for index := range f.users {
+		if f.users[index].ID == id {
+			f.users[index].TwoFactorSecret = secret
+			f.users[index].TwoFactorEnabled = enabled
+		}
+	}
+	return nil
+}
+
+// fakeAttemptService blocks an account once it has failed maxFailures times.
+type fakeAttemptService struct {
+	domain.AttemptService
+	maxFailures int
+	failures    map[string]int
+}
+
+func (f *fakeAttemptService) Check(_ context.Context, purpose domain.AttemptPurpose, account string) error {
+	if f.failures[string(purpose)+account] >= f.maxFailures {
+		return &domain.AttemptBlockedError{Err: domain.ErrAccountLocked}
+	}
+	return nil
+}
+
+func (f *fakeAttemptService) Fail(_ context.Context, purpose domain.AttemptPurpose, account string, _ *domain.User) error {
+	f.failures[string(purpose)+account]++
+	return nil
+}
+
+func (f *fakeAttemptService) Reset(_ context.Context, purpose domain.AttemptPurpose, account string) error {
+	delete(f.failures, string(purpose)+account)
+	return nil
+}
+
+func (f *fakeAttemptService) Limit(_ context.Context, _ domain.AttemptPurpose, _ string) error {
+	return nil
+}
+
+func newTestTwoFactorService(t *testing.T, user domain.User) (*twoFactorService, context.Context) {
+	t.Helper()
+
+	twoFactorService := &twoFactorService{
+		twoFactorRepository: &fakeTwoFactorRepository{totpSteps: map[uuid.UUID]int64{}},
+		userRepository:      &fakeUserRepository{users: []domain.User{user}},
+		attemptService:      &fakeAttemptService{maxFailures: 3, failures: map[string]int{}},
+		auditService:        &fakeAuditService{},
+	}
+
+	ctx := context.WithValue(context.Background(), middleware.UserKey, &domain.Session{ID: uuid.New(), UserID: user.ID})
+	return twoFactorService, ctx
+}
+
+// newTestTwoFactorSecret returns a TOTP secret and the encrypted form stored
+// on the user.
+func newTestTwoFactorSecret(t *testing.T) (string, string) {
+	t.Helper()
+
+	secretBox, err := secure.NewSecretBox(base64.StdEncoding.EncodeToString(make([]byte, 32)))
+	if err != nil {
+		t.Fatalf("NewSecretBox() error = %v", err)
+	}
+
+	env := config.Env
+	t.Cleanup(func() { config.Env = env })
+	config.Env.TwoFactorSecretBox = secretBox
+
+	secret, err := secure.GenerateSecret("user@example.com")
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+
+	encryptedSecret, err := secretBox.Seal(secret)
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+
+	return secret, encryptedSecret
+}
+
+func TestTwoFactorServiceRejectsReplayedCode(t *testing.T) {
+	secret, encryptedSecret := newTestTwoFactorSecret(t)
+
+	user := domain.User{ID: uuid.New(), Email: "user@example.com", TwoFactorSecret: encryptedSecret, TwoFactorEnabled: true}
+	twoFactorService, ctx := newTestTwoFactorService(t, user)
+
+	code, err := secure.GenerateNumericOTP(secret)
+	if err != nil {
+		t.Fatalf("GenerateNumericOTP() error = %v", err)
+	}
+
+	valid, err := twoFactorService.verifyCode(ctx, user, code)
+	if err != nil || !valid {
+		t.Fatalf("verifyCode() = (%v, %v), want (true, nil)", valid, err)
+	}
+
+	valid, err = twoFactorService.verifyCode(ctx, user, code)
+	if err != nil || valid {
+		t.Fatalf("replayed verifyCode() = (%v, %v), want (false, nil)", valid, err)
+	}
+}
+
+func TestTwoFactorServiceConfirmLimitsAttempts(t *testing.T) {
+	secret, encryptedSecret := newTestTwoFactorSecret(t)
+
+	user := domain.User{ID: uuid.New(), Email: "user@example.com", TwoFactorSecret: encryptedSecret}
+	twoFactorService, ctx := newTestTwoFactorService(t, user)
+
+	for range 3 {
+		if _, err := twoFactorService.Confirm(ctx, domain.TwoFactorCodePayload{Code: "000000x"}); !errors.Is(err, domain.ErrTwoFactorCodeInvalid) {
+			t.Fatalf("Confirm() error = %v, want %v", err, domain.ErrTwoFactorCodeInvalid)
+		}
+	}
+
+	code, err := secure.GenerateNumericOTP(secret)
+	if err != nil {
+		t.Fatalf("GenerateNumericOTP() error = %v", err)
+	}
+
+	var attemptBlockedError *domain.AttemptBlockedError
+	if _, err := twoFactorService.Confirm(ctx, domain.TwoFactorCodePayload{Code: code}); !errors.As(err, &attemptBlockedError) {
+		t.Fatalf("Confirm() after failures error = %v, want an attempt blocked error", err)
+	}
+}
+
+func TestTwoFactorServiceConfirmRejectsReplayedCode(t *testing.T) {
+	secret, encryptedSecret := newTestTwoFactorSecret(t)
+
+	user := domain.User{ID: uuid.New(), Email: "user@example.com", TwoFactorSecret: encryptedSecret}
+	twoFactorService, ctx := newTestTwoFactorService(t, user)
+
+	code, err := secure.GenerateNumericOTP(secret)
+	if err != nil {
+		t.Fatalf("GenerateNumericOTP() error = %v", err)
+	}
+
+	if _, err := twoFactorService.verifyTOTP(ctx, user, code); err != nil {
+		t.Fatalf("verifyTOTP() error = %v", err)
+	}
+
+	if _, err := twoFactorService.Confirm(ctx, domain.TwoFactorCodePayload{Code: code}); !errors.Is(err, domain.ErrTwoFactorCodeInvalid) {
+		t.Fatalf("Confirm() with used code error = %v, want %v", err, domain.ErrTwoFactorCodeInvalid)
+	}
+}
+
+func TestTwoFactorServiceEnrollStoresEncryptedSecret(t *testing.T) {
+	newTestTwoFactorSecret(t)
+
+	user := domain.User{ID: uuid.New(), Email: "user@example.com", EmailConfirmed: true}
+	twoFactorService, ctx := newTestTwoFactorService(t, user)
+
+	twoFactorEnrollResponse, err := twoFactorService.Enroll(ctx)
+	if err != nil {
+		t.Fatalf("Enroll() error = %v", err)
+	}
+
+	storedSecret := twoFactorService.userRepository.(*fakeUserRepository).users[0].TwoFactorSecret
+	if storedSecret == "" || storedSecret == twoFactorEnrollResponse.Secret {
+		t.Fatalf("stored secret = %q, want it encrypted", storedSecret)
+	}
+
+	secret, err := config.Env.TwoFactorSecretBox.Open(storedSecret)
+	if err != nil || secret != twoFactorEnrollResponse.Secret {
+		t.Fatalf("Open() = (%q, %v), want the enrolled secret", secret, err)
+	}
+}
@@ -2,19 +2,31 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/url"
+	"strings"
 
+	"github.com/GSVillas/e-commercer-api/client"
+	"github.com/GSVillas/e-commercer-api/config"
 	"github.com/GSVillas/e-commercer-api/domain"
 	"github.com/GSVillas/e-commercer-api/middleware"
 	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/google/uuid"
 	"github.com/samber/do"
 )
 
 type userService struct {
-	i               *do.Injector
-	userRespository domain.UserRepository
-	sessionService  domain.SessionService
-	emailService    domain.EmailService
+	i                   *do.Injector
+	userRespository     domain.UserRepository
+	sessionService      domain.SessionService
+	emailService        domain.EmailService
+	attemptService      domain.AttemptService
+	cloudFlareService   client.CloudFlareService
+	auditService        domain.AuditService
+	loginHistoryService domain.LoginHistoryService
+	storeRepository     domain.StoreRepository
 }
 
 func NewUserService(i *do.Injector) (domain.UserService, error) {
@@ -33,11 +45,41 @@ func NewUserService(i *do.Injector) (domain.UserService, error) {
 		return nil, err
 	}
 
+	attemptService, err := do.Invoke[domain.AttemptService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	cloudFlareService, err := do.Invoke[client.CloudFlareService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	auditService, err := do.Invoke[domain.AuditService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	loginHistoryService, err := do.Invoke[domain.LoginHistoryService](i)
+	if err != nil {
+		return nil, err
+	}
+
+	storeRepository, err := do.Invoke[domain.StoreRepository](i)
+	if err != nil {
+		return nil, err
+	}
+
 	return &userService{
-		i:               i,
-		userRespository: userRepository,
-		sessionService:  sessionService,
-		emailService:    emailService,
+		i:                   i,
+		userRespository:     userRepository,
+		sessionService:      sessionService,
+		emailService:        emailService,
+		attemptService:      attemptService,
+		cloudFlareService:   cloudFlareService,
+		auditService:        auditService,
+		loginHistoryService: loginHistoryService,
+		storeRepository:     storeRepository,
 	}, nil
 }
 
@@ -49,13 +91,13 @@ func (u *userService) Create(ctx context.Context, userPayload domain.UserPayLoad
 
 	log.Info("Initializing user creation process")
 
-	user, err := u.userRespository.GetByEmail(ctx, userPayload.Email)
+	exists, err := u.userRespository.ExistsByEmail(ctx, userPayload.Email)
 	if err != nil {
-		log.Error("Failed to get user by email", slog.String("error", err.Error()))
+		log.Error("Failed to check if email exists", slog.String("error", err.Error()))
 		return err
 	}
 
-	if user != nil {
+	if exists {
 		log.Warn("User already exists")
 		return domain.ErrUserAlreadyExists
 	}
@@ -66,13 +108,42 @@ func (u *userService) Create(ctx context.Context, userPayload domain.UserPayLoad
 		return domain.ErrHashingPassword
 	}
 
-	user = userPayload.ToUser(string(passwordHash))
+	user := userPayload.ToUser(string(passwordHash))
+
+	if user.Username != "" {
+		exists, err := u.userRespository.ExistsByUsername(ctx, user.Username)
+		if err != nil {
+			log.Error("Failed to check if username exists", slog.String("error", err.Error()))
+			return err
+		}
+
+		if exists {
+			log.Warn("Username already in use")
+			return domain.ErrUsernameAlreadyInUse
+		}
+	} else {
+		user.Username, err = generateUsername(ctx, u.userRespository, user.Name)
+		if err != nil {
+			log.Error("Failed to generate username", slog.String("error", err.Error()))
+			return err
+		}
+	}
 
 	if err := u.userRespository.Create(ctx, *user); err != nil {
 		log.Error("Failed to create user", slog.String("error", err.Error()))
 		return err
 	}
 
+	if err := u.auditService.Record(ctx, domain.AuditEntry{
+		ActorID:    user.ID,
+		Action:     domain.AuditActionUserCreate,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID.String(),
+		After:      map[string]any{"name": user.Name, "username": user.Username, "email": user.Email},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
 	log.Info("User created successfully")
 	return nil
 }
@@ -85,6 +156,15 @@ func (u *userService) SignIn(ctx context.Context, signInPayload domain.SignInPay
 
 	log.Info("Initializing user sign in process")
 
+	if err := u.attemptService.Check(ctx, domain.AttemptPurposeSignIn, signInPayload.Email); err != nil {
+		log.Warn("Sign in attempt blocked", slog.String("error", err.Error()))
+		var attemptBlockedError *domain.AttemptBlockedError
+		if errors.As(err, &attemptBlockedError) {
+			u.recordLogin(ctx, nil, signInPayload.Email, domain.LoginResultBlocked, uuid.Nil)
+		}
+		return nil, err
+	}
+
 	user, err := u.userRespository.GetByEmail(ctx, signInPayload.Email)
 	if err != nil {
 		log.Error("Failed to get user by email", slog.String("error", err.Error()))
@@ -93,20 +173,66 @@ func (u *userService) SignIn(ctx context.Context, signInPayload domain.SignInPay
 
 	if user == nil {
 		log.Warn("User not found")
+		u.recordLogin(ctx, nil, signInPayload.Email, domain.LoginResultUnknownUser, uuid.Nil)
+		if err := u.attemptService.Fail(ctx, domain.AttemptPurposeSignIn, signInPayload.Email, nil); err != nil {
+			return nil, err
+		}
 		return nil, domain.ErrUserNotFound
 	}
 
 	if err := secure.CheckPassword(user.PasswordHash, signInPayload.Password); err != nil {
 		log.Warn("Invalid password")
+		u.recordLogin(ctx, user, signInPayload.Email, domain.LoginResultInvalidPassword, uuid.Nil)
+		if err := u.attemptService.Fail(ctx, domain.AttemptPurposeSignIn, signInPayload.Email, user); err != nil {
+			return nil, err
+		}
 		return nil, domain.ErrInvalidPassword
 	}
 
-	token, err := u.sessionService.Create(ctx, *user)
+	if err := u.attemptService.Reset(ctx, domain.AttemptPurposeSignIn, signInPayload.Email); err != nil {
+		log.Error("Failed to reset sign in attempts", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if secure.NeedsRehash(user.PasswordHash) {
+		u.upgradePasswordHash(ctx, *user, signInPayload.Password)
+	}
+
+	if user.IsSuspended() {
+		log.Warn("User suspended")
+		u.recordLogin(ctx, user, signInPayload.Email, domain.LoginResultSuspended, uuid.Nil)
+		return nil, domain.ErrUserSuspended
+	}
+
+	if user.PasswordResetRequired {
+		log.Warn("User password reset required")
+		u.recordLogin(ctx, user, signInPayload.Email, domain.LoginResultPasswordResetRequired, uuid.Nil)
+		return nil, domain.ErrPasswordResetRequired
+	}
+
+	if user.TwoFactorEnabled {
+		challengeToken, err := u.sessionService.CreateTwoFactorChallenge(ctx, user.ID)
+		if err != nil {
+			log.Error("Failed to create two factor challenge", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		log.Info("Two factor challenge issued")
+		u.recordLogin(ctx, user, signInPayload.Email, domain.LoginResultTwoFactorRequired, uuid.Nil)
+		return &domain.SessionResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+		}, domain.ErrTwoFactorRequired
+	}
+
+	sessionResponse, err := u.sessionService.Create(ctx, *user)
 	if err != nil {
 		log.Error("Failed to create token", slog.String("error", err.Error()))
 		return nil, err
 	}
 
+	u.recordLogin(ctx, user, signInPayload.Email, domain.LoginResultSuccess, sessionResponse.SessionID)
+
 	if !user.EmailConfirmed {
 		log.Warn("User email not confirmed")
 
@@ -115,16 +241,12 @@ func (u *userService) SignIn(ctx context.Context, signInPayload domain.SignInPay
 			return nil, err
 		}
 
-		return &domain.SessionResponse{
-			Token: token,
-		}, domain.ErrEmailNotConfirmed
+		return sessionResponse, domain.ErrEmailNotConfirmed
 	}
 
 	log.Info("User signed in successfully")
 
-	return &domain.SessionResponse{
-		Token: token,
-	}, nil
+	return sessionResponse, nil
 }
 
 func (u *userService) UpdateName(ctx context.Context, name string) error {
@@ -166,10 +288,85 @@ func (u *userService) UpdateName(ctx context.Context, name string) error {
 		return err
 	}
 
+	if err := u.auditService.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditActionUserUpdateName,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID.String(),
+		Before:     map[string]any{"name": user.Name},
+		After:      map[string]any{"name": name},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
 	log.Info("User name updated successfully")
 	return nil
 }
 
+func (u *userService) UpdateUsername(ctx context.Context, updateUsernamePayload domain.UpdateUsernamePayload) error {
+	log := slog.With(
+		slog.String("service", "user"),
+		slog.String("func", "UpdateUsername"),
+	)
+
+	log.Info("Initializing user username update process")
+
+	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || session == nil {
+		log.Warn("User not found in context")
+		return domain.ErrUserNotFoundInContext
+	}
+
+	user, err := u.userRespository.GetByID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get user by ID", slog.String("error", err.Error()))
+		return err
+	}
+
+	if user == nil {
+		log.Warn("User not found")
+		return domain.ErrUserNotFound
+	}
+
+	if user.Username == updateUsernamePayload.Username {
+		log.Warn("New username is the same as the old username")
+		return domain.ErrUsernameIsSame
+	}
+
+	exists, err := u.userRespository.ExistsByUsername(ctx, updateUsernamePayload.Username)
+	if err != nil {
+		log.Error("Failed to check if username exists", slog.String("error", err.Error()))
+		return err
+	}
+
+	if exists {
+		log.Warn("Username already in use")
+		return domain.ErrUsernameAlreadyInUse
+	}
+
+	if err := u.userRespository.UpdateUsername(ctx, user.ID, updateUsernamePayload.Username); err != nil {
+		log.Error("Failed to update user username", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := u.sessionService.Update(ctx); err != nil {
+		log.Error("Failed to update token", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := u.auditService.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditActionUserUpdateUsername,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID.String(),
+		Before:     map[string]any{"username": user.Username},
+		After:      map[string]any{"username": updateUsernamePayload.Username},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("User username updated successfully")
+	return nil
+}
+
 func (u *userService) UpdatePassword(ctx context.Context, updatePasswordPayload domain.UpdatePasswordPayload) error {
 	log := slog.With(
 		slog.String("service", "user"),
@@ -190,14 +387,22 @@ func (u *userService) UpdatePassword(ctx context.Context, updatePasswordPayload
 		return err
 	}
 
+	if user == nil {
+		log.Warn("User not found")
+		return domain.ErrUserNotFound
+	}
+
 	if !user.EmailConfirmed {
 		log.Warn("User email not confirmed")
 		return domain.ErrEmailNotConfirmed
 	}
 
-	if err := secure.CheckPassword(user.PasswordHash, updatePasswordPayload.OldPassword); err != nil {
-		log.Warn("Invalid old password")
-		return domain.ErrInvalidOldPassword
+	if err := u.attemptService.VerifyPassword(ctx, *user, updatePasswordPayload.OldPassword); err != nil {
+		log.Warn("Old password not verified", slog.String("error", err.Error()))
+		if errors.Is(err, domain.ErrInvalidPassword) {
+			return domain.ErrInvalidOldPassword
+		}
+		return err
 	}
 
 	if updatePasswordPayload.OldPassword == updatePasswordPayload.NewPassword {
@@ -221,6 +426,14 @@ func (u *userService) UpdatePassword(ctx context.Context, updatePasswordPayload
 		return err
 	}
 
+	if err := u.auditService.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditActionUserUpdatePassword,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID.String(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
 	log.Info("User password updated successfully")
 	return nil
 }
@@ -242,6 +455,35 @@ func (u *userService) GetUserInfo(ctx context.Context) (*domain.UserResponse, er
 	return session.ToResponse(), nil
 }
 
+func (u *userService) GetProfile(ctx context.Context, username string) (*domain.PublicProfileResponse, error) {
+	log := slog.With(
+		slog.String("service", "user"),
+		slog.String("func", "GetProfile"),
+	)
+
+	log.Info("Initializing get public profile process")
+
+	user, err := u.userRespository.GetByUsername(ctx, domain.NormalizeUsername(username))
+	if err != nil {
+		log.Error("Failed to get user by username", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if user == nil || user.IsSuspended() {
+		log.Warn("User not found or suspended")
+		return nil, domain.ErrUserNotFound
+	}
+
+	stores, err := u.storeRepository.GetPublicByOwner(ctx, user.ID)
+	if err != nil {
+		log.Error("Failed to get public stores", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Public profile retrieved successfully", slog.Int("storeCount", len(stores)))
+	return user.ToPublicProfileResponse(stores), nil
+}
+
 func (u *userService) ResendCode(ctx context.Context, resendCodePayload domain.ResendCodePayload) error {
 	log := slog.With(
 		slog.String("service", "user"),
@@ -250,12 +492,22 @@ func (u *userService) ResendCode(ctx context.Context, resendCodePayload domain.R
 
 	log.Info("Initializing resend code process")
 
+	if err := u.attemptService.Limit(ctx, domain.AttemptPurposeResendCode, resendCodePayload.Email); err != nil {
+		log.Warn("Resend code request rate limited", slog.String("error", err.Error()))
+		return err
+	}
+
 	user, err := u.userRespository.GetByEmail(ctx, resendCodePayload.Email)
 	if err != nil {
 		log.Error("Failed to get user by email", slog.String("error", err.Error()))
 		return err
 	}
 
+	if user == nil {
+		log.Warn("Resend code requested for unknown email")
+		return nil
+	}
+
 	if err := u.emailService.SendConfirmationCode(ctx, *user); err != nil {
 		log.Error("Failed to send OTP email", slog.String("error", err.Error()))
 		return err
@@ -279,7 +531,12 @@ func (u *userService) ConfirmEmail(ctx context.Context, confirmEmailPayload doma
 		return domain.ErrUserNotFoundInContext
 	}
 
-	OTP, err := u.sessionService.GetOTP(ctx, session.Email)
+	if err := u.attemptService.Check(ctx, domain.AttemptPurposeConfirmEmail, session.UserID.String()); err != nil {
+		log.Warn("Confirm email attempt blocked", slog.String("error", err.Error()))
+		return err
+	}
+
+	OTP, err := u.sessionService.GetOTP(ctx, domain.OTPPurposeEmailConfirmation, session.Email)
 	if err != nil {
 		log.Error("Failed to retrieve OTP from session service", slog.String("error", err.Error()))
 		return err
@@ -292,14 +549,31 @@ func (u *userService) ConfirmEmail(ctx context.Context, confirmEmailPayload doma
 
 	if OTP != confirmEmailPayload.OTP {
 		log.Warn("Provided OTP does not match the stored OTP")
+		user := &domain.User{ID: session.UserID, Name: session.Name, Email: session.Email}
+		if err := u.attemptService.Fail(ctx, domain.AttemptPurposeConfirmEmail, session.UserID.String(), user); err != nil {
+			return err
+		}
 		return domain.ErrOTPInvalid
 	}
 
+	if err := u.attemptService.Reset(ctx, domain.AttemptPurposeConfirmEmail, session.UserID.String()); err != nil {
+		log.Error("Failed to reset confirm email attempts", slog.String("error", err.Error()))
+		return err
+	}
+
 	if err := u.userRespository.UpdateConfirmEmail(ctx, session.UserID); err != nil {
 		log.Error("Failed to update user's email confirmation status in the repository", slog.String("error", err.Error()))
 		return err
 	}
 
+	if err := u.auditService.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditActionUserConfirmEmail,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   session.UserID.String(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
 	log.Info("Email confirmed successfully for user", slog.String("userID", session.UserID.String()))
 	return nil
 }
@@ -337,3 +611,610 @@ func (u *userService) CheckStatus(ctx context.Context) error {
 	log.Info("check user status executed succefully")
 	return nil
 }
+
+func (u *userService) RefreshToken(ctx context.Context, refreshTokenPayload domain.RefreshTokenPayload) (*domain.SessionResponse, error) {
+	log := slog.With(
+		slog.String("service", "user"),
+		slog.String("func", "RefreshToken"),
+	)
+
+	log.Info("Initializing refresh token process")
+
+	sessionResponse, err := u.sessionService.Refresh(ctx, refreshTokenPayload.RefreshToken)
+	if err != nil {
+		log.Warn("Failed to refresh token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Refresh token process executed successfully")
+	return sessionResponse, nil
+}
+
+func (u *userService) SignOut(ctx context.Context) error {
+	log := slog.With(
+		slog.String("service", "user"),
+		slog.String("func", "SignOut"),
+	)
+
+	log.Info("Initializing sign out process")
+
+	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || session == nil {
+		log.Warn("User not found in context")
+		return domain.ErrUserNotFoundInContext
+	}
+
+	if err := u.sessionService.Delete(ctx, session.ID); err != nil {
+		log.Error("Failed to delete session", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("User signed out successfully")
+	return nil
+}
+
+func (u *userService) ForgotPassword(ctx context.Context, forgotPasswordPayload domain.ForgotPasswordPayload) error {
+	log := slog.With(
+		slog.String("service", "user"),
+		slog.String("func", "ForgotPassword"),
+	)
+
+	log.Info("Initializing forgot password process")
+
+	if err := u.attemptService.Limit(ctx, domain.AttemptPurposeForgotPassword, forgotPasswordPayload.Email); err != nil {
+		log.Warn("Forgot password request rate limited", slog.String("error", err.Error()))
+		return err
+	}
+
+	user, err := u.userRespository.GetByEmail(ctx, forgotPasswordPayload.Email)
+	if err != nil {
+		log.Error("Failed to get user by email", slog.String("error", err.Error()))
+		return err
+	}
+
+	if user == nil {
+		log.Warn("Password reset requested for unknown email")
+		return nil
+	}
+
+	if err := u.emailService.SendPasswordResetCode(ctx, *user); err != nil {
+		log.Error("Failed to send password reset email", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Forgot password process executed successfully")
+	return nil
+}
+
+func (u *userService) ResetPassword(ctx context.Context, resetPasswordPayload domain.ResetPasswordPayload) error {
+	log := slog.With(
+		slog.String("service", "user"),
+		slog.String("func", "ResetPassword"),
+	)
+
+	log.Info("Initializing reset password process")
+
+	if err := u.attemptService.Check(ctx, domain.AttemptPurposeOTP, resetPasswordPayload.Email); err != nil {
+		log.Warn("Reset password attempt blocked", slog.String("error", err.Error()))
+		return err
+	}
+
+	user, err := u.userRespository.GetByEmail(ctx, resetPasswordPayload.Email)
+	if err != nil {
+		log.Error("Failed to get user by email", slog.String("error", err.Error()))
+		return err
+	}
+
+	if user == nil {
+		log.Warn("Password reset attempted for unknown email")
+		if err := u.attemptService.Fail(ctx, domain.AttemptPurposeOTP, resetPasswordPayload.Email, nil); err != nil {
+			return err
+		}
+		return domain.ErrOTPInvalid
+	}
+
+	OTP, err := u.sessionService.GetOTP(ctx, domain.OTPPurposePasswordReset, user.Email)
+	if err != nil {
+		log.Error("Failed to retrieve OTP from session service", slog.String("error", err.Error()))
+		return err
+	}
+
+	if OTP == "" {
+		log.Warn("OTP has expired or is invalid")
+		return domain.ErrOTPExpires
+	}
+
+	if OTP != resetPasswordPayload.OTP {
+		log.Warn("Provided OTP does not match the stored OTP")
+		if err := u.attemptService.Fail(ctx, domain.AttemptPurposeOTP, resetPasswordPayload.Email, user); err != nil {
+			return err
+		}
+		return domain.ErrOTPInvalid
+	}
+
+	if err := u.attemptService.Reset(ctx, domain.AttemptPurposeOTP, resetPasswordPayload.Email); err != nil {
+		log.Error("Failed to reset otp attempts", slog.String("error", err.Error()))
+		return err
+	}
+
+	passwordHash, err := secure.Hash(resetPasswordPayload.NewPassword)
+	if err != nil {
+		log.Error("Failed to hash password", slog.String("error", err.Error()))
+		return domain.ErrHashingPassword
+	}
+
+	if err := u.userRespository.UpdatePassword(ctx, user.ID, string(passwordHash)); err != nil {
+		log.Error("Failed to update user password", slog.String("error", err.Error()))
+		return err
+	}
+
+	if user.PasswordResetRequired {
+		if err := u.userRespository.UpdatePasswordResetRequired(ctx, user.ID, false); err != nil {
+			log.Error("Failed to clear password reset required", slog.String("error", err.Error()))
+			return err
+		}
+	}
+
+	if err := u.sessionService.DeleteOTP(ctx, domain.OTPPurposePasswordReset, user.Email); err != nil {
+		log.Error("Failed to delete OTP", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := u.sessionService.RevokeUserSessions(ctx, user.ID); err != nil {
+		log.Error("Failed to revoke user sessions", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := u.auditService.Record(ctx, domain.AuditEntry{
+		ActorID:    user.ID,
+		Action:     domain.AuditActionUserResetPassword,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID.String(),
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Password reset successfully", slog.String("userID", user.ID.String()))
+	return nil
+}
+
+func (u *userService) ChangeEmail(ctx context.Context, changeEmailPayload domain.ChangeEmailPayload) error {
+	log := slog.With(
+		slog.String("service", "user"),
+		slog.String("func", "ChangeEmail"),
+	)
+
+	log.Info("Initializing change email process")
+
+	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || session == nil {
+		log.Warn("User not found in context")
+		return domain.ErrUserNotFoundInContext
+	}
+
+	user, err := u.userRespository.GetByID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get user by ID", slog.String("error", err.Error()))
+		return err
+	}
+
+	if user == nil {
+		log.Warn("User not found")
+		return domain.ErrUserNotFound
+	}
+
+	if err := u.attemptService.VerifyPassword(ctx, *user, changeEmailPayload.Password); err != nil {
+		log.Warn("Password not verified", slog.String("error", err.Error()))
+		return err
+	}
+
+	if strings.EqualFold(user.Email, changeEmailPayload.NewEmail) {
+		log.Warn("New email is the same as the current email")
+		return domain.ErrEmailIsSame
+	}
+
+	exists, err := u.userRespository.ExistsByEmail(ctx, changeEmailPayload.NewEmail)
+	if err != nil {
+		log.Error("Failed to check if email exists", slog.String("error", err.Error()))
+		return err
+	}
+
+	if exists {
+		log.Warn("Email already in use")
+		return domain.ErrEmailAlreadyInUse
+	}
+
+	emailChange := domain.EmailChange{
+		UserID:   user.ID,
+		OldEmail: user.Email,
+		NewEmail: changeEmailPayload.NewEmail,
+	}
+
+	if err := u.userRespository.SaveEmailChange(ctx, emailChange); err != nil {
+		log.Error("Failed to save email change", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := u.emailService.SendEmailChangeCode(ctx, *user, emailChange.NewEmail); err != nil {
+		log.Error("Failed to send email change code", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Change email process executed successfully")
+	return nil
+}
+
+func (u *userService) ConfirmEmailChange(ctx context.Context, confirmEmailChangePayload domain.ConfirmEmailChangePayload) error {
+	log := slog.With(
+		slog.String("service", "user"),
+		slog.String("func", "ConfirmEmailChange"),
+	)
+
+	log.Info("Initializing confirm email change process")
+
+	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || session == nil {
+		log.Warn("User not found in context")
+		return domain.ErrUserNotFoundInContext
+	}
+
+	if err := u.attemptService.Check(ctx, domain.AttemptPurposeOTP, session.UserID.String()); err != nil {
+		log.Warn("Confirm email change attempt blocked", slog.String("error", err.Error()))
+		return err
+	}
+
+	emailChange, err := u.userRespository.GetEmailChange(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get email change", slog.String("error", err.Error()))
+		return err
+	}
+
+	if emailChange == nil {
+		log.Warn("No pending email change")
+		return domain.ErrEmailChangeNotFound
+	}
+
+	OTP, err := u.sessionService.GetOTP(ctx, domain.OTPPurposeEmailChange, emailChange.NewEmail)
+	if err != nil {
+		log.Error("Failed to retrieve OTP from session service", slog.String("error", err.Error()))
+		return err
+	}
+
+	if OTP == "" {
+		log.Warn("OTP has expired or is invalid")
+		return domain.ErrOTPExpires
+	}
+
+	if OTP != confirmEmailChangePayload.OTP {
+		log.Warn("Provided OTP does not match the stored OTP")
+		user := &domain.User{ID: session.UserID, Name: session.Name, Email: session.Email}
+		if err := u.attemptService.Fail(ctx, domain.AttemptPurposeOTP, session.UserID.String(), user); err != nil {
+			return err
+		}
+		return domain.ErrOTPInvalid
+	}
+
+	if err := u.attemptService.Reset(ctx, domain.AttemptPurposeOTP, session.UserID.String()); err != nil {
+		log.Error("Failed to reset otp attempts", slog.String("error", err.Error()))
+		return err
+	}
+
+	exists, err := u.userRespository.ExistsByEmail(ctx, emailChange.NewEmail)
+	if err != nil {
+		log.Error("Failed to check if email exists", slog.String("error", err.Error()))
+		return err
+	}
+
+	if exists {
+		log.Warn("Email already in use")
+		return domain.ErrEmailAlreadyInUse
+	}
+
+	user, err := u.userRespository.GetByID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get user by ID", slog.String("error", err.Error()))
+		return err
+	}
+
+	if user == nil {
+		log.Warn("User not found")
+		return domain.ErrUserNotFound
+	}
+
+	if err := u.userRespository.UpdateEmail(ctx, user.ID, emailChange.NewEmail); err != nil {
+		log.Error("Failed to update user email", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := u.sessionService.DeleteOTP(ctx, domain.OTPPurposeEmailChange, emailChange.NewEmail); err != nil {
+		log.Error("Failed to delete OTP", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := u.userRespository.DeleteEmailChange(ctx, user.ID); err != nil {
+		log.Error("Failed to delete email change", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := u.sendEmailChangedNotice(ctx, *user, *emailChange); err != nil {
+		log.Error("Failed to send email changed notice", slog.String("error", err.Error()))
+	}
+
+	if err := u.sessionService.Update(ctx); err != nil {
+		log.Error("Failed to update token", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := u.auditService.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditActionUserChangeEmail,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID.String(),
+		Before:     map[string]any{"email": user.Email},
+		After:      map[string]any{"email": emailChange.NewEmail},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Email changed successfully", slog.String("userID", user.ID.String()))
+	return nil
+}
+
+func (u *userService) CancelEmailChange(ctx context.Context, cancelEmailChangePayload domain.CancelEmailChangePayload) error {
+	log := slog.With(
+		slog.String("service", "user"),
+		slog.String("func", "CancelEmailChange"),
+	)
+
+	log.Info("Initializing cancel email change process")
+
+	emailChange, err := u.userRespository.GetEmailChangeCancelToken(ctx, secure.HashToken(cancelEmailChangePayload.Token))
+	if err != nil {
+		log.Error("Failed to get email change cancel token", slog.String("error", err.Error()))
+		return err
+	}
+
+	if emailChange == nil {
+		log.Warn("Email change cancel token not found or expired")
+		return domain.ErrEmailChangeCancelInvalid
+	}
+
+	user, err := u.userRespository.GetByID(ctx, emailChange.UserID)
+	if err != nil {
+		log.Error("Failed to get user by ID", slog.String("error", err.Error()))
+		return err
+	}
+
+	if user == nil || user.Email != emailChange.NewEmail {
+		log.Warn("Email was changed again since the cancel token was issued")
+		return domain.ErrEmailChangeCancelInvalid
+	}
+
+	exists, err := u.userRespository.ExistsByEmail(ctx, emailChange.OldEmail)
+	if err != nil {
+		log.Error("Failed to check if email exists", slog.String("error", err.Error()))
+		return err
+	}
+
+	if exists {
+		log.Warn("Old email already in use")
+		return domain.ErrEmailAlreadyInUse
+	}
+
+	if err := u.userRespository.UpdateEmail(ctx, user.ID, emailChange.OldEmail); err != nil {
+		log.Error("Failed to restore user email", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := u.sessionService.RevokeUserSessions(ctx, user.ID); err != nil {
+		log.Error("Failed to revoke user sessions", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := u.auditService.Record(ctx, domain.AuditEntry{
+		ActorID:    user.ID,
+		Action:     domain.AuditActionUserCancelEmailChange,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID.String(),
+		Before:     map[string]any{"email": user.Email},
+		After:      map[string]any{"email": emailChange.OldEmail},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("Email change cancelled successfully", slog.String("userID", user.ID.String()))
+	return nil
+}
+
+func (u *userService) UpdateAvatar(ctx context.Context, updateAvatarPayload domain.UpdateAvatarPayload) (*domain.UserResponse, error) {
+	log := slog.With(
+		slog.String("service", "user"),
+		slog.String("func", "UpdateAvatar"),
+	)
+
+	log.Info("Initializing user avatar update process")
+
+	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || session == nil {
+		log.Warn("User not found in context")
+		return nil, domain.ErrUserNotFoundInContext
+	}
+
+	user, err := u.userRespository.GetByID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get user by ID", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if user == nil {
+		log.Warn("User not found")
+		return nil, domain.ErrUserNotFound
+	}
+
+	if !user.EmailConfirmed {
+		log.Warn("User email not confirmed")
+		return nil, domain.ErrEmailNotConfirmed
+	}
+
+	avatarURL, err := u.cloudFlareService.UploadImage(updateAvatarPayload.Avatar)
+	if err != nil {
+		log.Error("Error to upload avatar in cloud", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := u.userRespository.UpdateAvatar(ctx, user.ID, avatarURL); err != nil {
+		log.Error("Failed to update user avatar", slog.String("error", err.Error()))
+		if err := u.cloudFlareService.DeleteImage(avatarURL); err != nil {
+			log.Error("Failed to delete uploaded avatar", slog.String("error", err.Error()))
+		}
+		return nil, err
+	}
+
+	if user.AvatarURL != "" {
+		if err := u.cloudFlareService.DeleteImage(user.AvatarURL); err != nil {
+			log.Error("Failed to delete old avatar", slog.String("error", err.Error()))
+		}
+	}
+
+	if err := u.sessionService.Update(ctx); err != nil {
+		log.Error("Failed to update token", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := u.auditService.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditActionUserUpdateAvatar,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID.String(),
+		Before:     map[string]any{"avatarUrl": user.AvatarURL},
+		After:      map[string]any{"avatarUrl": avatarURL},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	user.AvatarURL = avatarURL
+
+	log.Info("User avatar updated successfully")
+	return user.ToResponse(), nil
+}
+
+func (u *userService) DeleteAvatar(ctx context.Context) error {
+	log := slog.With(
+		slog.String("service", "user"),
+		slog.String("func", "DeleteAvatar"),
+	)
+
+	log.Info("Initializing user avatar delete process")
+
+	session, ok := ctx.Value(middleware.UserKey).(*domain.Session)
+	if !ok || session == nil {
+		log.Warn("User not found in context")
+		return domain.ErrUserNotFoundInContext
+	}
+
+	user, err := u.userRespository.GetByID(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to get user by ID", slog.String("error", err.Error()))
+		return err
+	}
+
+	if user == nil {
+		log.Warn("User not found")
+		return domain.ErrUserNotFound
+	}
+
+	if user.AvatarURL == "" {
+		log.Warn("User has no avatar")
+		return domain.ErrAvatarNotFound
+	}
+
+	if err := u.userRespository.UpdateAvatar(ctx, user.ID, ""); err != nil {
+		log.Error("Failed to remove user avatar", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := u.cloudFlareService.DeleteImage(user.AvatarURL); err != nil {
+		log.Error("Failed to delete avatar from cloud", slog.String("error", err.Error()))
+	}
+
+	if err := u.sessionService.Update(ctx); err != nil {
+		log.Error("Failed to update token", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := u.auditService.Record(ctx, domain.AuditEntry{
+		Action:     domain.AuditActionUserDeleteAvatar,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID.String(),
+		Before:     map[string]any{"avatarUrl": user.AvatarURL},
+	}); err != nil {
+		log.Error("Failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	log.Info("User avatar deleted successfully")
+	return nil
+}
+
+func (u *userService) upgradePasswordHash(ctx context.Context, user domain.User, password string) {
+	log := slog.With(
+		slog.String("service", "user"),
+		slog.String("func", "upgradePasswordHash"),
+	)
+
+	passwordHash, err := secure.Hash(password)
+	if err != nil {
+		log.Warn("Failed to rehash password", slog.String("error", err.Error()))
+		return
+	}
+
+	if err := u.userRespository.UpdatePassword(ctx, user.ID, string(passwordHash)); err != nil {
+		log.Warn("Failed to upgrade password hash", slog.String("error", err.Error()))
+		return
+	}
+
+	log.Info("Password hash upgraded", slog.String("userID", user.ID.String()))
+}
+
+func (u *userService) recordLogin(ctx context.Context, user *domain.User, email string, result domain.LoginResult, sessionID uuid.UUID) {
+	log := slog.With(
+		slog.String("service", "user"),
+		slog.String("func", "recordLogin"),
+	)
+
+	loginAttempt := domain.LoginAttempt{
+		Email:     email,
+		Method:    domain.LoginMethodPassword,
+		Result:    result,
+		SessionID: sessionID,
+	}
+
+	if user != nil {
+		loginAttempt.UserID = user.ID
+	}
+
+	if err := u.loginHistoryService.Record(ctx, loginAttempt); err != nil {
+		log.Error("Failed to record login history", slog.String("error", err.Error()))
+	}
+}
+
+func (u *userService) sendEmailChangedNotice(ctx context.Context, user domain.User, emailChange domain.EmailChange) error {
+	token, err := secure.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	if err := u.userRespository.SaveEmailChangeCancelToken(ctx, secure.HashToken(token), emailChange); err != nil {
+		return err
+	}
+
+	user.Email = emailChange.OldEmail
+	cancelURL := fmt.Sprintf("%s/email/change/cancel?token=%s", strings.TrimSuffix(config.Env.URLFront, "/"), url.QueryEscape(token))
+	return u.emailService.SendEmailChangedNotice(ctx, user, emailChange.NewEmail, cancelURL)
+}
+
+// generateUsername picks a free handle derived from the user's name. Handles
+// of deleted accounts stay reserved so links to an old profile never start
+// pointing at somebody else.
+func generateUsername(ctx context.Context, userRepository domain.UserRepository, name string) (string, error) {
+	return domain.GenerateUsername(name, func(username string) (bool, error) {
+		return userRepository.ExistsByUsername(ctx, username)
+	})
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GSVillas/e-commercer-api/config"
+	"github.com/GSVillas/e-commercer-api/domain"
+	"github.com/GSVillas/e-commercer-api/middleware"
+	"github.com/GSVillas/e-commercer-api/secure"
+	"github.com/google/uuid"
+)
+
+// fakeAttemptRepository keeps failures, locks and throttles in memory so the
+// real attempt service can be exercised.
+type fakeAttemptRepository struct {
+	domain.AttemptRepository
+	failures  map[string]int64
+	requests  map[string]int64
+	locks     map[string]time.Duration
+	throttles map[string]time.Duration
+}
+
+func newFakeAttemptRepository() *fakeAttemptRepository {
+	return &fakeAttemptRepository{
+		failures:  map[string]int64{},
+		requests:  map[string]int64{},
+		locks:     map[string]time.Duration{},
+		throttles: map[string]time.Duration{},
+	}
+}
+
+func (f *fakeAttemptRepository) IncrementFailures(_ context.Context, purpose domain.AttemptPurpose, scope domain.AttemptScope, identifier string, _ time.Duration) (int64, error) {
+	key := string(purpose) + string(scope) + identifier
+	f.failures[key]++
+	return f.failures[key], nil
+}
+
+func (f *fakeAttemptRepository) IncrementRequests(_ context.Context, purpose domain.AttemptPurpose, scope domain.AttemptScope, identifier string, window time.Duration) (int64, time.Duration, error) {
+	key := string(purpose) + string(scope) + identifier
+	f.requests[key]++
+	return f.requests[key], window, nil
+}
+
+func (f *fakeAttemptRepository) DeleteFailures(_ context.Context, purpose domain.AttemptPurpose, scope domain.AttemptScope, identifier string) error {
+	delete(f.failures, string(purpose)+string(scope)+identifier)
+	return nil
+}
+
+func (f *fakeAttemptRepository) SaveLock(_ context.Context, purpose domain.AttemptPurpose, scope domain.AttemptScope, identifier string, duration time.Duration) error {
+	f.locks[string(purpose)+string(scope)+identifier] = duration
+	return nil
+}
+
+func (f *fakeAttemptRepository) GetLock(_ context.Context, purpose domain.AttemptPurpose, scope domain.AttemptScope, identifier string) (time.Duration, error) {
+	return f.locks[string(purpose)+string(scope)+identifier], nil
+}
+
+func (f *fakeAttemptRepository) SaveThrottle(_ context.Context, purpose domain.AttemptPurpose, account string, delay time.Duration) error {
+	f.throttles[string(purpose)+account] = delay
+	return nil
+}
+
+func (f *fakeAttemptRepository) GetThrottle(_ context.Context, purpose domain.AttemptPurpose, account string) (time.Duration, error) {
+	return f.throttles[string(purpose)+account], nil
+}
+
+func TestReauthenticationLimitsPasswordAttempts(t *testing.T) {
+	tests := []struct {
+		name           string
+		reauthenticate func(attemptService domain.AttemptService, userRepository domain.UserRepository, ctx context.Context, password string) error
+	}{
+		{
+			name: "change email",
+			reauthenticate: func(attemptService domain.AttemptService, userRepository domain.UserRepository, ctx context.Context, password string) error {
+				userService := &userService{userRespository: userRepository, attemptService: attemptService}
+				return userService.ChangeEmail(ctx, domain.ChangeEmailPayload{NewEmail: "new@example.com", Password: password})
+			},
+		},
+		{
+			name: "update password",
+			reauthenticate: func(attemptService domain.AttemptService, userRepository domain.UserRepository, ctx context.Context, password string) error {
+				userService := &userService{userRespository: userRepository, attemptService: attemptService}
+				err := userService.UpdatePassword(ctx, domain.UpdatePasswordPayload{OldPassword: password, NewPassword: "new-password"})
+				if errors.Is(err, domain.ErrInvalidOldPassword) {
+					return domain.ErrInvalidPassword
+				}
+				return err
+			},
+		},
+		{
+			name: "delete account",
+			reauthenticate: func(attemptService domain.AttemptService, userRepository domain.UserRepository, ctx context.Context, password string) error {
+				accountService := &accountService{userRepository: userRepository, attemptService: attemptService}
+				return accountService.Delete(ctx, domain.DeleteAccountPayload{Password: password})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := config.Env
+			t.Cleanup(func() { config.Env = env })
+			config.Env.LockoutMaxAttempts = 0
+
+			passwordHash, err := secure.Hash("correct-password")
+			if err != nil {
+				t.Fatalf("Hash() error = %v", err)
+			}
+
+			user := domain.User{ID: uuid.New(), Email: "user@example.com", PasswordHash: string(passwordHash), EmailConfirmed: true}
+			userRepository := &fakeUserRepository{users: []domain.User{user}}
+			attemptService := &attemptService{attemptRepository: newFakeAttemptRepository()}
+			ctx := context.WithValue(context.Background(), middleware.UserKey, &domain.Session{ID: uuid.New(), UserID: user.ID})
+
+			for range throttleAfterFailures {
+				if err := tt.reauthenticate(attemptService, userRepository, ctx, "wrong-password"); !errors.Is(err, domain.ErrInvalidPassword) {
+					t.Fatalf("error = %v, want %v", err, domain.ErrInvalidPassword)
+				}
+			}
+
+			var attemptBlockedError *domain.AttemptBlockedError
+			if err := tt.reauthenticate(attemptService, userRepository, ctx, "correct-password"); !errors.As(err, &attemptBlockedError) {
+				t.Fatalf("error after %d failures = %v, want an attempt blocked error", throttleAfterFailures, err)
+			}
+		})
+	}
+}
+
+func TestForgotPasswordRateLimitDoesNotLockAccount(t *testing.T) {
+	env := config.Env
+	t.Cleanup(func() { config.Env = env })
+	config.Env.RequestRateLimit = 2
+
+	attemptService := &attemptService{attemptRepository: newFakeAttemptRepository()}
+	userService := &userService{userRespository: &fakeUserRepository{}, attemptService: attemptService}
+	payload := domain.ForgotPasswordPayload{Email: "user@example.com"}
+
+	for range 2 {
+		if err := userService.ForgotPassword(context.Background(), payload); err != nil {
+			t.Fatalf("ForgotPassword() error = %v", err)
+		}
+	}
+
+	var attemptBlockedError *domain.AttemptBlockedError
+	err := userService.ForgotPassword(context.Background(), payload)
+	if !errors.As(err, &attemptBlockedError) || !errors.Is(err, domain.ErrRequestRateLimited) {
+		t.Fatalf("ForgotPassword() error = %v, want %v", err, domain.ErrRequestRateLimited)
+	}
+
+	if attemptBlockedError.RetryAfter <= 0 {
+		t.Fatalf("RetryAfter = %v, want a positive duration", attemptBlockedError.RetryAfter)
+	}
+
+	for _, purpose := range []domain.AttemptPurpose{domain.AttemptPurposeForgotPassword, domain.AttemptPurposeSignIn} {
+		if err := attemptService.Check(context.Background(), purpose, payload.Email); err != nil {
+			t.Fatalf("Check(%s) error = %v, want the account to stay unlocked", purpose, err)
+		}
+	}
+}
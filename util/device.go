@@ -0,0 +1,20 @@
+package util
+
+import "strings"
+
+func DeviceFromUserAgent(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+
+	switch {
+	case ua == "":
+		return "Unknown device"
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet"):
+		return "Tablet"
+	case strings.Contains(ua, "mobile") || strings.Contains(ua, "iphone") || strings.Contains(ua, "android"):
+		return "Mobile"
+	case strings.Contains(ua, "windows") || strings.Contains(ua, "macintosh") || strings.Contains(ua, "linux"):
+		return "Desktop"
+	default:
+		return "Unknown device"
+	}
+}